@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/audit"
+	"github.com/bluehoodie/whoseport/internal/terminal"
+)
+
+// runLog implements "whoseport log": it lists recent actions from the audit log,
+// most recent first, optionally for one port, process or user.
+func runLog(args []string) {
+	fs := flag.NewFlagSet("whoseport log", flag.ExitOnError)
+	port := fs.Int("port", 0, "Only actions on this port")
+	process := fs.String("process", "", "Only actions on this PID, command, part of a command line, or container")
+	fs.StringVar(process, "p", "", "Only actions on this process (shorthand)")
+	user := fs.String("user", "", "Only actions by or against this user")
+	since := fs.Duration("since", 0, "Only actions in this period, e.g. 24h")
+	limit := fs.Int("n", 20, "Number of actions to show (0 for all)")
+	jsonOutput := fs.Bool("json", false, "Output the entries as JSON lines")
+	path := fs.String("audit-log", "", "Audit log to read (default: /var/log/whoseport/audit.jsonl if readable, else ~/.local/state/whoseport/audit.jsonl)")
+
+	fs.Usage = func() {
+		fmt.Printf("%s%sUsage of whoseport log:%s\n", terminal.ColorBold, terminal.ColorCyan, terminal.ColorReset)
+		fmt.Printf("  %swhoseport log [options] [port]%s\n\n", terminal.ColorWhite, terminal.ColorReset)
+		fmt.Printf("%sOptions:%s\n", terminal.ColorBold, terminal.ColorReset)
+		fs.PrintDefaults()
+		fmt.Printf("\n%sExample:%s\n", terminal.ColorBold, terminal.ColorReset)
+		fmt.Printf("  whoseport log 8080              # Who stopped what on port 8080\n")
+		fmt.Printf("  whoseport log -p node --since 24h  # Actions on node processes today\n")
+	}
+	if err := parseLogArgs(fs, args, port); err != nil {
+		fmt.Printf("%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	file := audit.ReadPath(*path)
+	if file == "" {
+		fmt.Fprintf(os.Stderr, "%serror:%s the audit log is off (%s=%s)\n", terminal.ColorRed, terminal.ColorReset, audit.EnvPath, audit.Off)
+		os.Exit(1)
+	}
+	entries, err := audit.Read(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+		os.Exit(1)
+	}
+
+	filter := audit.Filter{Port: *port, Process: *process, User: *user, Limit: *limit}
+	if *since > 0 {
+		filter.Since = time.Now().Add(-*since)
+	}
+	matched := audit.Query(entries, filter)
+
+	if *jsonOutput {
+		for _, e := range matched {
+			line, _ := json.Marshal(e)
+			fmt.Printf("%s\n", line)
+		}
+		return
+	}
+	if len(matched) == 0 {
+		fmt.Fprintf(os.Stderr, "No recorded actions match in %s\n", file)
+		return
+	}
+	for _, e := range matched {
+		printLogEntry(e)
+	}
+}
+
+// parseLogArgs parses the options of "whoseport log" and the optional port, which
+// options may also follow
+func parseLogArgs(fs *flag.FlagSet, args []string, port *int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return nil
+	}
+	n, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("port must be an integer")
+	}
+	*port = n
+
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q after port %d", fs.Arg(0), n)
+	}
+	return nil
+}
+
+// printLogEntry prints an audit entry: when, where and what on the first line, who
+// on the second and, if it failed, why on the third
+func printLogEntry(e audit.Entry) {
+	color := terminal.ColorGreen
+	switch e.Outcome {
+	case audit.OutcomeFailed:
+		color = terminal.ColorRed
+	case audit.OutcomeRefused:
+		color = terminal.ColorYellow
+	}
+
+	fmt.Printf("%s%s%s  port %-5d  %s%-7s%s  %s\n", terminal.ColorBold, e.Time.Local().Format("2006-01-02 15:04:05"), terminal.ColorReset,
+		e.Port, color, e.Outcome, terminal.ColorReset, e.Summary())
+
+	who := "by " + e.User
+	if e.RunAs != "" {
+		who += " as " + e.RunAs
+	}
+	if e.TTY != "" {
+		who += " on " + e.TTY
+	}
+	if e.From != "" {
+		who += " from " + e.From
+	}
+	if e.Host != "" {
+		who += " at " + e.Host
+	}
+	fmt.Printf("%s                     %s%s\n", terminal.ColorCyan, who, terminal.ColorReset)
+	if e.Error != "" {
+		fmt.Printf("%s                     %s%s\n", color, e.Error, terminal.ColorReset)
+	}
+}
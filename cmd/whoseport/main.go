@@ -1,31 +1,113 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/bluehoodie/whoseport/internal/action"
+	"github.com/bluehoodie/whoseport/internal/audit"
 	"github.com/bluehoodie/whoseport/internal/display/docker"
 	"github.com/bluehoodie/whoseport/internal/display/interactive"
 	displayjson "github.com/bluehoodie/whoseport/internal/display/json"
+	displaykubernetes "github.com/bluehoodie/whoseport/internal/display/kubernetes"
 	dockerpkg "github.com/bluehoodie/whoseport/internal/docker"
+	"github.com/bluehoodie/whoseport/internal/kubernetes"
 	"github.com/bluehoodie/whoseport/internal/model"
+	"github.com/bluehoodie/whoseport/internal/netfilter"
+	"github.com/bluehoodie/whoseport/internal/privilege"
 	"github.com/bluehoodie/whoseport/internal/process"
 	"github.com/bluehoodie/whoseport/internal/procfs"
+	"github.com/bluehoodie/whoseport/internal/safety"
+	"github.com/bluehoodie/whoseport/internal/supervisor"
 	"github.com/bluehoodie/whoseport/internal/terminal"
 )
 
 var (
 	killFlag      bool
 	termFlag      bool
+	stopFlag      bool
+	restartFlag   bool
+	grace         time.Duration
+	scopeName     string
+	scope         action.Scope
+	signalName    string
+	signalFlag    syscall.Signal
 	noInteractive bool
 	jsonFlag      bool
+	stopTimeout   = -1 // Seconds given with --timeout; negative when it is not given
+	dockerContext string
+	dockerHost    string
+	forceFlag     bool
+	sudoFlag      bool
+	dryRunFlag    bool
+	auditPath     string
+
+	// dryRun collects what an action would do under --dry-run; nil otherwise
+	dryRun *dryRunReport
+
+	// auditLog records every signal and container action; nil when the log is off
+	// or nothing is done (--dry-run)
+	auditLog *audit.Logger
+
+	// auditBase is the start of every audit entry: who runs whoseport, and the port
+	auditBase audit.Entry
+
+	// guard decides which processes and containers are protected
+	guard *safety.Guard
+
+	// escalateFlags repeat an action chosen interactively when whoseport runs again
+	// with elevated privileges after the action failed for lack of permission
+	escalateFlags []string
+
+	// actionRan is set once a container, service or pod command has run, so that the
+	// port is only verified after something changed
+	actionRan bool
+
+	// holderHandle pins the identity of the port holder from the moment it is looked
+	// up, so a PID reused while the user reads the output is never signalled
+	holderHandle *action.Handle
 )
 
+// dryRunReport is what --dry-run found and the operations the action would perform.
+// With --json it replaces the usual document.
+type dryRunReport struct {
+	DryRun    bool                     `json:"dry_run"`
+	Port      int                      `json:"port"`
+	Process   *model.ProcessInfo       `json:"process,omitempty"`
+	Container *dockerpkg.ContainerInfo `json:"container,omitempty"`
+	Service   *dockerpkg.ServiceInfo   `json:"service,omitempty"`
+	Pod       *kubernetes.PodInfo      `json:"pod,omitempty"`
+	action.Plan
+}
+
+// scopePreviewMax is how many processes a --scope preview lists
+const scopePreviewMax = 20
+
+// parsePort reads the port from the positional arguments. Flags after the port are
+// not parsed, so anything following it is refused rather than ignored; this also
+// catches "-t 30 8080", where 30 would be taken as the port.
+func parsePort(args []string) (int, error) {
+	if len(args) < 1 {
+		return 0, errors.New("missing port number")
+	}
+	if len(args) > 1 {
+		return 0, fmt.Errorf("unexpected argument %q after port %s (options go before the port)", args[1], args[0])
+	}
+	port, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, errors.New("port must be an integer")
+	}
+	return port, nil
+}
+
 // isNoServiceError checks if the error indicates no service was found on the port
 func isNoServiceError(err error) bool {
 	if err == nil {
@@ -35,28 +117,66 @@ func isNoServiceError(err error) bool {
 }
 
 func main() {
+	// whoseport log [options] [port] queries the audit log
+	if len(os.Args) > 1 && os.Args[1] == "log" {
+		runLog(os.Args[2:])
+		return
+	}
+
 	flag.BoolVar(&killFlag, "kill", false, "Kill the process using the port (SIGKILL)")
 	flag.BoolVar(&killFlag, "k", false, "Kill the process using the port (shorthand)")
 	flag.BoolVar(&termFlag, "term", false, "Terminate the process using the port (SIGTERM)")
 	flag.BoolVar(&termFlag, "t", false, "Terminate the process using the port (shorthand)")
+	flag.IntVar(&stopTimeout, "timeout", -1, "Seconds -t gives the process or container before SIGKILL (like docker stop -t)")
+	flag.BoolVar(&stopFlag, "stop", false, "Stop the process using the port (SIGTERM, then SIGKILL after the grace period)")
+	flag.BoolVar(&restartFlag, "restart", false, "Stop the process and start its command line again, detached, with the same directory, environment and user")
+	flag.DurationVar(&grace, "grace", action.DefaultGrace, "How long --stop waits for the process to exit before SIGKILL")
+	flag.StringVar(&signalName, "signal", "", "Send a signal by name or number (HUP, USR1, 15, RTMIN+3) without prompting")
+	flag.StringVar(&scopeName, "scope", "", "Processes to signal: pid, pgroup, session, descendants or cgroup")
+	flag.BoolVar(&forceFlag, "force", false, "Allow actions on protected processes, containers and pods (after typing their name)")
+	flag.BoolVar(&sudoFlag, "sudo", false, "Run with elevated privileges (sudo, doas or pkexec)")
+	flag.BoolVar(&dryRunFlag, "dry-run", false, "Print the signals and commands an action would run without running them")
+	flag.StringVar(&auditPath, "audit-log", "", "File signals and container actions are recorded in (\"off\" disables it)")
 	flag.BoolVar(&noInteractive, "no-interactive", false, "Disable interactive mode (show info only)")
 	flag.BoolVar(&noInteractive, "n", false, "Disable interactive mode (shorthand)")
 	flag.BoolVar(&jsonFlag, "json", false, "Output in JSON format")
+	flag.StringVar(&dockerContext, "docker-context", "", "Docker context to query for containers")
+	flag.StringVar(&dockerHost, "docker-host", "", "Docker engine address to query for containers")
 
 	flag.Usage = func() {
 		fmt.Printf("%s%sUsage of whoseport:%s\n", terminal.ColorBold, terminal.ColorCyan, terminal.ColorReset)
-		fmt.Printf("  %swhoseport [options] {port}%s\n\n", terminal.ColorWhite, terminal.ColorReset)
+		fmt.Printf("  %swhoseport [options] {port}%s\n", terminal.ColorWhite, terminal.ColorReset)
+		fmt.Printf("  %swhoseport log [options] [port]%s   Show recent actions (see whoseport log -h)\n\n", terminal.ColorWhite, terminal.ColorReset)
 		fmt.Printf("%sOptions:%s\n", terminal.ColorBold, terminal.ColorReset)
 		fmt.Printf("  %s-k, --kill%s            Kill the process using the port (SIGKILL - force kill)\n", terminal.ColorYellow, terminal.ColorReset)
 		fmt.Printf("  %s-t, --term%s            Terminate the process using the port (SIGTERM - graceful)\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--timeout%s N           With -t: SIGKILL after N seconds (docker stop -t N for containers)\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--stop%s                SIGTERM, then SIGKILL if the process outlives the grace period\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--restart%s             Stop the process, then start the same command line again\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--grace%s DURATION      Grace period for --stop and --restart (default: 10s)\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--signal%s SIG         Send a signal without prompting (HUP, USR1, 15, RTMIN+3)\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--scope%s SCOPE         Signal the pid, pgroup, session, descendants or cgroup\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--force%s               Act on a protected process, container or pod (asks to type its name)\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--sudo%s                Run as root via sudo, doas or pkexec (see WHOSEPORT_ESCALATE)\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--dry-run%s             Print what an action would do, without doing it\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--audit-log%s FILE      Record actions in FILE (default: /var/log/whoseport/audit.jsonl if writable,\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("                        else ~/.local/state/whoseport/audit.jsonl; also %s)\n", audit.EnvPath)
 		fmt.Printf("  %s-n, --no-interactive%s  Disable interactive mode (show info only)\n", terminal.ColorYellow, terminal.ColorReset)
 		fmt.Printf("  %s--json%s                Output in JSON format\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--docker-context%s NAME Docker context to query (default: the active context)\n", terminal.ColorYellow, terminal.ColorReset)
+		fmt.Printf("  %s--docker-host%s HOST    Docker engine address to query (e.g. ssh://user@host)\n", terminal.ColorYellow, terminal.ColorReset)
 		fmt.Printf("\n%sNote:%s Interactive mode is enabled by default\n", terminal.ColorBold, terminal.ColorReset)
 		fmt.Printf("\n%sExample:%s\n", terminal.ColorBold, terminal.ColorReset)
 		fmt.Printf("  whoseport 8080           # Show detailed info with interactive prompt\n")
 		fmt.Printf("  whoseport -n 8080        # Show info only, no prompt\n")
 		fmt.Printf("  whoseport -k 8080        # Force kill without prompting (SIGKILL)\n")
 		fmt.Printf("  whoseport -t 8080        # Gracefully terminate without prompting (SIGTERM)\n")
+		fmt.Printf("  whoseport -t --timeout 30 8080    # SIGTERM, then SIGKILL after 30 seconds\n")
+		fmt.Printf("  whoseport --stop --grace 5s 8080  # SIGTERM, then SIGKILL after 5s\n")
+		fmt.Printf("  whoseport --signal HUP 8080       # Ask the process to reload\n")
+		fmt.Printf("  whoseport --restart 8080          # Bounce the dev server on port 8080\n")
+		fmt.Printf("  whoseport -k --scope descendants 8080  # Kill the process and all of its children\n")
+		fmt.Printf("  whoseport --dry-run -k 8080       # Show the kill that -k would send\n")
 	}
 	flag.Parse()
 
@@ -67,68 +187,407 @@ func main() {
 		os.Exit(1)
 	}
 
-	if flag.NArg() < 1 {
-		fmt.Printf("%serror:%s missing port number\n", terminal.ColorRed, terminal.ColorReset)
+	if stopFlag && (killFlag || termFlag) {
+		fmt.Printf("%serror:%s cannot use --stop together with -k/--kill or -t/--term\n", terminal.ColorRed, terminal.ColorReset)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if restartFlag && (killFlag || termFlag || stopFlag) {
+		fmt.Printf("%serror:%s cannot use --restart together with -k/--kill, -t/--term or --stop\n", terminal.ColorRed, terminal.ColorReset)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if signalName != "" {
+		if killFlag || termFlag || stopFlag || restartFlag {
+			fmt.Printf("%serror:%s cannot use --signal together with -k/--kill, -t/--term, --stop or --restart\n", terminal.ColorRed, terminal.ColorReset)
+			flag.Usage()
+			os.Exit(1)
+		}
+		parsed, err := action.ParseSignal(signalName)
+		if err != nil {
+			fmt.Printf("%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+			flag.Usage()
+			os.Exit(1)
+		}
+		signalFlag = parsed
+	}
+
+	if flagPassed("timeout") {
+		if stopTimeout < 0 {
+			fmt.Printf("%serror:%s --timeout must not be negative\n", terminal.ColorRed, terminal.ColorReset)
+			flag.Usage()
+			os.Exit(1)
+		}
+		if !termFlag {
+			fmt.Printf("%serror:%s --timeout is used with -t/--term (--stop takes --grace)\n", terminal.ColorRed, terminal.ColorReset)
+			flag.Usage()
+			os.Exit(1)
+		}
+	}
+
+	if grace <= 0 {
+		fmt.Printf("%serror:%s --grace must be a positive duration (e.g. 10s)\n", terminal.ColorRed, terminal.ColorReset)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if scopeName != "" {
+		parsed, err := action.ParseScope(scopeName)
+		if err != nil {
+			fmt.Printf("%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+			flag.Usage()
+			os.Exit(1)
+		}
+		scope = parsed
+	}
+
+	if dockerContext != "" && dockerHost != "" {
+		fmt.Printf("%serror:%s cannot use both --docker-context and --docker-host flags together\n", terminal.ColorRed, terminal.ColorReset)
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	port, err := strconv.Atoi(flag.Arg(0))
+	port, err := parsePort(flag.Args())
 	if err != nil {
-		fmt.Printf("%serror:%s port must be an integer\n", terminal.ColorRed, terminal.ColorReset)
+		fmt.Printf("%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
 		flag.Usage()
 		os.Exit(1)
 	}
 
+	// Run the same command line as root before looking anything up
+	if sudoFlag && !privilege.Elevated() {
+		tool, err := privilege.Tool()
+		if err == nil {
+			err = privilege.Reexec(tool, privilege.Args(os.Args[1:], flag.NArg()))
+		}
+		fmt.Printf("%serror:%s --sudo: %v\n", terminal.ColorRed, terminal.ColorReset, err)
+		os.Exit(1)
+	}
+
+	// Load the built-in deny list and the user's protection list
+	guard, err = safety.NewGuard()
+	if err != nil {
+		fmt.Printf("%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+		os.Exit(1)
+	}
+
+	// Record actions instead of performing them, and print them before exiting
+	if dryRunFlag {
+		dryRun = &dryRunReport{DryRun: true, Port: port, Plan: action.Plan{Operations: []action.Operation{}}}
+		defer printDryRun(false)
+	}
+
+	// Record actions in the audit log
+	if path := audit.Path(auditPath); path != "" && dryRun == nil {
+		auditLog = audit.NewLogger(path)
+		auditBase = audit.NewEntry(port)
+	}
+
 	// Retrieve process information
 	retriever := process.NewDefaultRetriever()
 	processInfo, err := retriever.GetProcessByPort(port)
 	if err != nil {
 		// Check if it's a "no service found" case and provide a friendlier message
 		if isNoServiceError(err) {
+			// The port may still be published by a container through netfilter
+			if handlePublishedPort(port) {
+				return
+			}
+			// lsof only lists the current user's processes without root
+			if !privilege.Elevated() && procfs.PortListening(port) {
+				fmt.Fprintf(os.Stderr, "Port %d is in use, but its process is hidden from you\n", port)
+				offerEscalation("lsof only shows your own processes without root")
+				exit(1)
+			}
 			fmt.Fprintf(os.Stderr, "No process is listening on port %d\n", port)
 		} else {
 			fmt.Fprintf(os.Stderr, "%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
 		}
-		os.Exit(1)
+		exit(1)
+	}
+
+	// Capture the process identity before the slower enhancement and display steps
+	if handle, err := action.OpenHandle(processInfo.ID); err == nil {
+		holderHandle = handle
+		defer handle.Close()
 	}
 
 	// Enhance with detailed process information
 	enhancer := procfs.NewProcessEnhancer()
 	enhancer.Enhance(processInfo)
 
-	// Check if this is a Docker container process
-	detector := dockerpkg.NewDetector()
-	isDocker, containerID, err := detector.IsDockerRelated(processInfo, port)
+	// Recognize process managers and watchers that would respawn the process
+	processInfo.Supervisor = supervisor.NewDetector().Detect(processInfo)
+
+	// Check if this is a Docker or Podman container process
+	for _, runtime := range containerRuntimes() {
+		detector := dockerpkg.NewDetectorForRuntime(runtime)
+		isContainer, containerID, err := detector.IsDockerRelated(processInfo, port)
+
+		if err == nil && isContainer && containerID != "" {
+			// Container detected - use container-specific workflow
+			handleDockerContainer(detector.Runtime(), containerID, processInfo, nil, port)
+			return
+		}
+
+		// dockerd itself holds ports published through the swarm routing mesh
+		if serviceID := detector.FindService(processInfo, port); serviceID != "" {
+			handleSwarmService(runtime, serviceID, processInfo, port)
+			return
+		}
+	}
+
+	// Check if this process runs inside a Kubernetes pod
+	if pod, err := kubernetes.NewDetector().Detect(processInfo); err == nil && pod != nil {
+		handleKubernetesPod(pod, processInfo, port)
+		return
+	}
+
+	// Regular process - use existing workflow
+	handleRegularProcess(processInfo, port)
+}
+
+// flagPassed reports whether the flag was given on the command line
+func flagPassed(name string) bool {
+	passed := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+	return passed
+}
+
+// containerRuntimes returns the container runtimes to check, with Docker pointed at the
+// engine selected by --docker-context or --docker-host.
+func containerRuntimes() []dockerpkg.Runtime {
+	runtimes := dockerpkg.Runtimes()
+	for i, runtime := range runtimes {
+		if runtime.Name == dockerpkg.DockerRuntime.Name {
+			runtimes[i] = runtime.WithEngine(dockerContext, dockerHost)
+		}
+	}
+	return runtimes
+}
+
+func handleKubernetesPod(pod *kubernetes.PodInfo, processInfo *model.ProcessInfo, port int) {
+	name := pod.PodName
+	if name == "" {
+		name = pod.ShortID
+	}
+	pod.Protected = guard.Check(safety.Subject{Port: port, Container: name, Image: pod.Image})
 
-	if err == nil && isDocker && containerID != "" {
-		// Docker container detected - use Docker-specific workflow
-		handleDockerContainer(containerID, processInfo, port)
+	// Display the process info followed by the pod section
+	if dryRun != nil && jsonFlag {
+		dryRun.Process, dryRun.Pod = processInfo, pod
+	} else if jsonFlag {
+		displayer := displayjson.NewDisplayer()
+		if err := displayer.DisplayPod(processInfo, pod); err != nil {
+			fmt.Fprintf(os.Stderr, "%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+			exit(1)
+		}
 	} else {
-		// Regular process - use existing workflow
+		interactive.NewDisplayer().Display(processInfo, port)
+		displaykubernetes.NewDisplayer().Display(pod)
+	}
+
+	// Handle Kubernetes actions
+	actionHandler := kubernetes.NewActionHandler()
+	if dryRun != nil {
+		actionHandler.SetPlan(&dryRun.Plan)
+	}
+	target := audit.ProcessTarget(processInfo)
+	target.Container, target.ContainerName, target.Image = pod.ContainerID, pod.Namespace+"/"+pod.PodName, pod.Image
+	if pod.PodName == "" {
+		target.ContainerName = pod.ShortID
+	}
+	actionHandler.SetGuard(containerGuard(pod.Protected, audit.ActionPod, target, actionHandler.ConfirmTyped))
+	actionHandler.SetAuditor(commandAuditor(audit.ActionPod, target))
+
+	if restartFlag {
+		fmt.Printf("%s✗ --restart is not supported for Kubernetes pods:%s use kubectl rollout restart, or -k to delete the pod\n", terminal.ColorRed, terminal.ColorReset)
+		exit(1)
+	} else if stopFlag || flagPassed("grace") || stopTimeout >= 0 {
+		fmt.Printf("%s✗ --stop, --grace and --timeout are not supported for Kubernetes pods:%s use -t to stop the container, or -k to delete the pod\n", terminal.ColorRed, terminal.ColorReset)
+		exit(1)
+	} else if scopeName != "" {
+		fmt.Printf("%s✗ --scope is not supported for Kubernetes pods:%s the container runtime signals every process of the container\n", terminal.ColorRed, terminal.ColorReset)
+		exit(1)
+	} else if signalName != "" {
+		fmt.Printf("%s✗ --signal is not supported for Kubernetes pods:%s use -t to stop the container, or -k to delete the pod\n", terminal.ColorRed, terminal.ColorReset)
+		exit(1)
+	} else if killFlag || termFlag {
+		// Direct action without prompting: -t stops the container, -k deletes the pod
+		action := kubernetes.ActionStopContainer
+		if killFlag {
+			action = kubernetes.ActionDeletePod
+		}
+
+		actedAt := time.Now()
+		if err := actionHandler.ExecuteAction(action, pod); err != nil {
+			fmt.Printf("%s✗ Failed to execute action:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+			exit(1)
+		}
+		verifyReleased(processInfo, port, actedAt)
+	} else if !noInteractive {
+		if refuseProtected(pod.Protected, "pod") {
+			return
+		}
+		// Interactive mode - prompt for Kubernetes action
+		action := actionHandler.PromptAction(pod)
+		if action != kubernetes.ActionCancel {
+			actedAt := time.Now()
+			if err := actionHandler.ExecuteAction(action, pod); err != nil {
+				fmt.Printf("%s✗ Failed to execute action:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+				exit(1)
+			}
+			verifyReleased(processInfo, port, actedAt)
+		}
+	}
+}
+
+// handlePublishedPort looks for a container publishing a port that no host process holds.
+// Returns true if a container was found and handled.
+func handlePublishedPort(port int) bool {
+	for _, runtime := range containerRuntimes() {
+		detector := dockerpkg.NewDetectorForRuntime(runtime)
+		if containerID, forward := detector.FindPublishedPort(port); containerID != "" {
+			handleDockerContainer(runtime, containerID, nil, forward, port)
+			return true
+		}
+		if serviceID := detector.FindService(nil, port); serviceID != "" {
+			handleSwarmService(runtime, serviceID, nil, port)
+			return true
+		}
+	}
+	return false
+}
+
+// handleSwarmService shows a swarm service publishing the port and its actions.
+// processInfo is dockerd, or nil when no host process holds the port.
+func handleSwarmService(runtime dockerpkg.Runtime, serviceID string, processInfo *model.ProcessInfo, port int) {
+	retriever := dockerpkg.NewRetrieverForRuntime(runtime)
+	serviceInfo, err := retriever.GetServiceInfo(serviceID, processInfo)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%serror:%s Failed to retrieve service info: %v\n", terminal.ColorRed, terminal.ColorReset, err)
+		if processInfo == nil {
+			exit(1)
+		}
+		// Fall back to regular process handling
 		handleRegularProcess(processInfo, port)
+		return
+	}
+
+	serviceInfo.Protected = guard.Check(safety.Subject{Port: port, Container: serviceInfo.Name, Image: serviceInfo.Image})
+
+	// Display the service info
+	if dryRun != nil && jsonFlag {
+		dryRun.Process, dryRun.Service = processInfo, serviceInfo
+	} else if jsonFlag {
+		displayer := displayjson.NewDisplayer()
+		if err := displayer.DisplayService(processInfo, serviceInfo); err != nil {
+			fmt.Fprintf(os.Stderr, "%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+			exit(1)
+		}
+	} else {
+		docker.NewDisplayer().DisplayService(serviceInfo, port)
+	}
+
+	// Handle service actions; dockerd itself is never killed
+	actionHandler := dockerpkg.NewActionHandler()
+	target := audit.Target{Container: serviceInfo.ID, ContainerName: serviceInfo.Name, Image: serviceInfo.Image}
+	actionHandler.SetGuard(containerGuard(serviceInfo.Protected, audit.ActionService, target, actionHandler.ConfirmTyped))
+	actionHandler.SetAuditor(commandAuditor(audit.ActionService, target))
+	if dryRun != nil {
+		actionHandler.SetPlan(&dryRun.Plan)
+	}
+
+	if restartFlag {
+		fmt.Printf("%s✗ --restart is not supported for swarm services:%s use docker service update --force %s\n", terminal.ColorRed, terminal.ColorReset, serviceInfo.Name)
+		exit(1)
+	} else if stopFlag || flagPassed("grace") || stopTimeout >= 0 {
+		fmt.Printf("%s✗ --stop, --grace and --timeout are not supported for swarm services:%s use -t to scale the service to zero, or -k to remove it\n", terminal.ColorRed, terminal.ColorReset)
+		exit(1)
+	} else if scopeName != "" {
+		fmt.Printf("%s✗ --scope is not supported for swarm services:%s the engine stops every task of the service\n", terminal.ColorRed, terminal.ColorReset)
+		exit(1)
+	} else if signalName != "" {
+		fmt.Printf("%s✗ --signal is not supported for swarm services:%s use -t to scale the service to zero, or -k to remove it\n", terminal.ColorRed, terminal.ColorReset)
+		exit(1)
+	} else if killFlag || termFlag {
+		// Direct action without prompting: -t scales the service to zero, -k removes it
+		action := dockerpkg.ActionScaleService
+		if killFlag || serviceInfo.Global() {
+			action = dockerpkg.ActionRemoveService
+		}
+
+		actedAt := time.Now()
+		if err := actionHandler.ExecuteServiceAction(action, serviceInfo); err != nil {
+			fmt.Printf("%s✗ Failed to execute action:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+			exit(1)
+		}
+		verifyServiceReleased(action, processInfo, port, actedAt)
+	} else if !noInteractive {
+		if refuseProtected(serviceInfo.Protected, "service") {
+			return
+		}
+		// Interactive mode - prompt for service action
+		action := actionHandler.PromptServiceAction(serviceInfo)
+		if action != dockerpkg.ActionCancel {
+			actedAt := time.Now()
+			if err := actionHandler.ExecuteServiceAction(action, serviceInfo); err != nil {
+				fmt.Printf("%s✗ Failed to execute action:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+				exit(1)
+			}
+			verifyServiceReleased(action, processInfo, port, actedAt)
+		}
+	}
+}
+
+// verifyServiceReleased checks the port after a service action. The routing mesh keeps
+// the published ports of a service scaled to zero, so that is pointed out on failure.
+func verifyServiceReleased(a dockerpkg.Action, processInfo *model.ProcessInfo, port int, actedAt time.Time) {
+	if portReleased(processInfo, port, actedAt) {
+		return
 	}
+	if a == dockerpkg.ActionScaleService {
+		fmt.Printf("%s   A service scaled to zero keeps its published ports; -k removes the service%s\n", terminal.ColorYellow, terminal.ColorReset)
+	}
+	exit(1)
 }
 
-func handleDockerContainer(containerID string, processInfo *model.ProcessInfo, port int) {
+// handleDockerContainer shows a container and its actions. processInfo is nil when no
+// host process holds the port; forward is the netfilter rule forwarding it, if known.
+func handleDockerContainer(runtime dockerpkg.Runtime, containerID string, processInfo *model.ProcessInfo, forward *netfilter.Rule, port int) {
 	// Retrieve container information
-	retriever := dockerpkg.NewRetriever()
+	retriever := dockerpkg.NewRetrieverForRuntime(runtime)
 	containerInfo, err := retriever.GetContainerInfo(containerID, processInfo)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%serror:%s Failed to retrieve container info: %v\n", terminal.ColorRed, terminal.ColorReset, err)
+		if processInfo == nil {
+			exit(1)
+		}
 		// Fall back to regular process handling
 		handleRegularProcess(processInfo, port)
 		return
 	}
+	containerInfo.Forward = forward
+
+	// Find the application behind docker-proxy and friends (best effort)
+	_ = dockerpkg.ResolveApplication(containerInfo, port)
+
+	containerInfo.Protected = guard.Check(safety.Subject{Port: port, Container: containerInfo.Name, Image: containerInfo.Image})
 
 	// Display the container info
-	if jsonFlag {
-		// For JSON mode, we could output the container info as JSON
-		// For now, fall back to process info JSON
+	if dryRun != nil && jsonFlag {
+		dryRun.Process, dryRun.Container = processInfo, containerInfo
+	} else if jsonFlag {
 		displayer := displayjson.NewDisplayer()
-		if err := displayer.Display(processInfo); err != nil {
+		if err := displayer.DisplayContainer(processInfo, containerInfo); err != nil {
 			fmt.Fprintf(os.Stderr, "%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
-			os.Exit(1)
+			exit(1)
 		}
 	} else {
 		displayer := docker.NewDisplayer()
@@ -137,40 +596,90 @@ func handleDockerContainer(containerID string, processInfo *model.ProcessInfo, p
 
 	// Handle Docker actions
 	actionHandler := dockerpkg.NewActionHandler()
+	actionHandler.SetStopTimeout(stopTimeout)
+	target := audit.ProcessTarget(processInfo)
+	target.Container, target.ContainerName, target.Image = containerInfo.ID, containerInfo.Name, containerInfo.Image
+	actionHandler.SetGuard(containerGuard(containerInfo.Protected, audit.ActionContainer, target, actionHandler.ConfirmTyped))
+	actionHandler.SetAuditor(commandAuditor(audit.ActionContainer, target))
+	if dryRun != nil {
+		actionHandler.SetPlan(&dryRun.Plan)
+	}
 
-	if killFlag || termFlag {
+	if scopeName != "" {
+		fmt.Printf("%s✗ --scope is not supported for containers:%s the engine stops every process of the container\n", terminal.ColorRed, terminal.ColorReset)
+		exit(1)
+	} else if restartFlag {
+		// The runtime restarts the container with its own configuration
+		if err := actionHandler.ExecuteAction(dockerpkg.ActionRestart, containerInfo); err != nil {
+			fmt.Printf("%s✗ Failed to execute action:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+			exit(1)
+		}
+	} else if killFlag || termFlag || stopFlag {
 		// Direct action without prompting (equivalent to -k/-t flags)
-		// For Docker, -k/-t means stop and remove the container
-		var action dockerpkg.Action
+		// For containers, -k stops and removes the container, -t and --stop stop it.
+		// --timeout N and --stop --grace give it N seconds before the engine kills it (docker stop -t N).
+		if stopFlag || flagPassed("grace") {
+			actionHandler.SetStopTimeout(int(math.Ceil(grace.Seconds())))
+		}
+		var err error
+		actedAt := time.Now()
 		if killFlag {
-			action = dockerpkg.ActionStopAndRemove
+			err = actionHandler.StopAndRemove(containerInfo)
 		} else {
-			action = dockerpkg.ActionStop
+			err = actionHandler.ExecuteAction(dockerpkg.ActionStop, containerInfo)
 		}
 
-		if err := actionHandler.ExecuteAction(action, containerInfo); err != nil {
+		if err != nil {
 			fmt.Printf("%s✗ Failed to execute action:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
-			os.Exit(1)
+			exit(1)
+		}
+		verifyReleased(processInfo, port, actedAt)
+	} else if signalName != "" {
+		// Send the signal given with --signal to the container's main process
+		actedAt := time.Now()
+		if err := actionHandler.KillContainer(containerInfo, signalFlag); err != nil {
+			fmt.Printf("%s✗ Failed to execute action:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+			exit(1)
+		}
+		if action.ExpectsExit(signalFlag, nil) {
+			verifyReleased(processInfo, port, actedAt)
 		}
 	} else if !noInteractive {
+		if refuseProtected(containerInfo.Protected, "container") {
+			return
+		}
 		// Interactive mode - prompt for Docker action
 		action := actionHandler.PromptAction(containerInfo)
 		if action != dockerpkg.ActionCancel {
+			actedAt := time.Now()
 			if err := actionHandler.ExecuteAction(action, containerInfo); err != nil {
 				fmt.Printf("%s✗ Failed to execute action:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
-				os.Exit(1)
+				exit(1)
+			}
+			if releasesPort(action) {
+				verifyReleased(processInfo, port, actedAt)
 			}
 		}
 	}
 }
 
 func handleRegularProcess(processInfo *model.ProcessInfo, port int) {
+	// -t --timeout N stops a process like docker stop -t N: SIGTERM, then SIGKILL after N seconds
+	if termFlag && stopTimeout >= 0 {
+		termFlag, stopFlag, grace = false, true, time.Duration(stopTimeout)*time.Second
+	}
+
+	processInfo.Protected = guard.Check(safety.ProcessSubject(processInfo, port))
+	processInfo.Hidden = privilege.Hidden(processInfo)
+
 	// Display the process info
-	if jsonFlag {
+	if dryRun != nil && jsonFlag {
+		dryRun.Process = processInfo
+	} else if jsonFlag {
 		displayer := displayjson.NewDisplayer()
 		if err := displayer.Display(processInfo); err != nil {
 			fmt.Fprintf(os.Stderr, "%serror:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
-			os.Exit(1)
+			exit(1)
 		}
 	} else {
 		displayer := interactive.NewDisplayer()
@@ -178,32 +687,533 @@ func handleRegularProcess(processInfo *model.ProcessInfo, port int) {
 	}
 
 	// Handle kill logic
-	killer := action.NewKiller()
 	if killFlag {
 		// Force kill without prompting (SIGKILL)
+		killer, _ := selectKiller(processInfo, nil)
+		actedAt := time.Now()
 		if err := killer.Kill(processInfo.ID, syscall.SIGKILL); err != nil {
-			fmt.Printf("%s✗ Failed to kill process:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
-			os.Exit(1)
+			actionFailed("kill process", err)
 		}
-		fmt.Printf("%s✓ Successfully killed process %d with SIGKILL%s\n", terminal.ColorGreen, processInfo.ID, terminal.ColorReset)
+		succeeded("%s✓ Successfully killed process %d with SIGKILL%s\n", terminal.ColorGreen, processInfo.ID, terminal.ColorReset)
+		warnSupervised(processInfo)
+		verifyPort(processInfo, port, actedAt)
 	} else if termFlag {
 		// Gracefully terminate without prompting (SIGTERM)
+		killer, _ := selectKiller(processInfo, nil)
+		actedAt := time.Now()
 		if err := killer.Kill(processInfo.ID, syscall.SIGTERM); err != nil {
-			fmt.Printf("%s✗ Failed to terminate process:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
-			os.Exit(1)
+			actionFailed("terminate process", err)
 		}
-		fmt.Printf("%s✓ Successfully terminated process %d with SIGTERM%s\n", terminal.ColorGreen, processInfo.ID, terminal.ColorReset)
+		succeeded("%s✓ Successfully terminated process %d with SIGTERM%s\n", terminal.ColorGreen, processInfo.ID, terminal.ColorReset)
+		warnSupervised(processInfo)
+		verifyPort(processInfo, port, actedAt)
+	} else if stopFlag {
+		// SIGTERM, escalating to SIGKILL after the grace period
+		killer, _ := selectKiller(processInfo, nil)
+		actedAt := time.Now()
+		gracefulStop(processInfo, port, killer)
+		verifyPort(processInfo, port, actedAt)
+	} else if restartFlag {
+		// Stop, then start the same command line again
+		restartProcess(processInfo, port, nil)
+	} else if signalName != "" {
+		// Send the signal given with --signal without prompting
+		killer, _ := selectKiller(processInfo, nil)
+		sendSignal(processInfo, port, killer, signalFlag)
 	} else if !noInteractive {
+		if refuseProtected(processInfo.Protected, "process") {
+			return
+		}
 		// Interactive mode - prompt for action in a single step
 		prompter := action.NewPrompter()
-		signal, shouldKill := prompter.PromptKillAction(processInfo)
-		if shouldKill {
+		selected, signal := prompter.PromptKillAction(processInfo)
+		switch selected {
+		case action.ActionGracefulStop:
+			escalateFlags = []string{"--stop"}
+			killer, ok := selectKiller(processInfo, prompter)
+			if !ok {
+				return
+			}
+			actedAt := time.Now()
+			gracefulStop(processInfo, port, killer)
+			verifyPort(processInfo, port, actedAt)
+		case action.ActionRestart:
+			escalateFlags = []string{"--restart"}
+			restartProcess(processInfo, port, prompter)
+		case action.ActionChooseSignal:
+			// Pick any signal, showing which ones the process handles
+			masks, _ := action.ReadSignalMasks(processInfo.ID)
+			chosen, err := prompter.PromptSignalFor(masks)
+			if err != nil {
+				return
+			}
+			escalateFlags = []string{"--signal", action.SignalName(chosen)}
+			killer, ok := selectKiller(processInfo, prompter)
+			if !ok {
+				return
+			}
+			sendSignal(processInfo, port, killer, chosen)
+		case action.ActionSignal:
 			// Send the selected signal
-			if err := killer.Kill(processInfo.ID, signal); err != nil {
-				fmt.Printf("%s✗ Failed to kill process:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
-				os.Exit(1)
+			escalateFlags = []string{"--signal", action.SignalName(signal)}
+			killer, ok := selectKiller(processInfo, prompter)
+			if !ok {
+				return
+			}
+			sendSignal(processInfo, port, killer, signal)
+		case action.ActionSupervisorStop:
+			// Stop through the supervisor so the process is not respawned
+			err := safety.Authorize(processInfo.Protected, processInfo.Command, forceFlag, confirmTyped(prompter.ConfirmTyped))
+			if err == nil && dryRun != nil {
+				dryRun.Add(action.Operation{Kind: action.OpCommand, Command: processInfo.Supervisor.StopCommand})
+				return
+			}
+			actedAt := time.Now()
+			if err == nil {
+				err = supervisor.Stop(processInfo.Supervisor)
+			}
+			logAction(auditEntry(audit.ActionSupervisor, audit.ProcessTarget(processInfo), processInfo.Supervisor.StopCommand, err))
+			if err != nil {
+				fmt.Printf("%s✗ Failed to stop process:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+				exit(1)
+			}
+			fmt.Printf("%s✓ Successfully stopped %s via %s%s\n", terminal.ColorGreen, processInfo.Command, processInfo.Supervisor.Kind, terminal.ColorReset)
+			verifyPort(processInfo, port, actedAt)
+		}
+	}
+}
+
+// restartWait is how long a restarted process gets to listen on the port again
+const restartWait = 10 * time.Second
+
+// restartProcess stops the process, makes sure the port was released and starts the
+// same command line again, detached, in the same directory with the same environment
+// and user. Nothing is stopped when the process could not be started again.
+func restartProcess(processInfo *model.ProcessInfo, port int, prompter *action.Prompter) {
+	restart, err := action.NewRestart(processInfo)
+	if err != nil {
+		fmt.Printf("%s✗ Cannot restart process %d:%s %v\n", terminal.ColorRed, processInfo.ID, terminal.ColorReset, err)
+		exit(1)
+	}
+	killer, ok := selectKiller(processInfo, prompter)
+	if !ok {
+		return
+	}
+	fmt.Printf("%s→ Will start again in %s: %s%s\n", terminal.ColorCyan, restart.Dir, restart, terminal.ColorReset)
+
+	actedAt := time.Now()
+	gracefulStop(processInfo, port, killer)
+	verifyPort(processInfo, port, actedAt)
+	if dryRun != nil {
+		dryRun.Add(action.Operation{Kind: action.OpCommand, Command: restart.Argv, Note: "started detached in " + restart.Dir})
+		return
+	}
+
+	launched, err := restart.Start(os.TempDir())
+	entry := auditEntry(audit.ActionRestart, audit.ProcessTarget(processInfo), restart.Argv, err)
+	if launched != nil {
+		entry.NewPID = launched.PID
+	}
+	logAction(entry)
+	if err != nil {
+		actionFailed("restart process", err)
+	}
+	fmt.Printf("%s✓ Restarted %s as process %d%s\n", terminal.ColorGreen, processInfo.Command, launched.PID, terminal.ColorReset)
+	fmt.Printf("%s   Output is logged to %s%s\n", terminal.ColorCyan, launched.LogFile, terminal.ColorReset)
+
+	// Wait for the new process to take the port again
+	retriever := process.NewDefaultRetriever()
+	for deadline := time.Now().Add(restartWait); time.Now().Before(deadline); time.Sleep(200 * time.Millisecond) {
+		if exited, err := launched.Exited(); exited {
+			fmt.Printf("%s✗ Process %d exited right away (%v); see %s%s\n", terminal.ColorRed, launched.PID, err, launched.LogFile, terminal.ColorReset)
+			exit(1)
+		}
+		if _, err := retriever.GetProcessByPort(port); err == nil {
+			fmt.Printf("%s✓ Port %d is in use again%s\n", terminal.ColorGreen, port, terminal.ColorReset)
+			return
+		}
+	}
+	fmt.Printf("%s⚠️  Process %d is running but not listening on port %d after %s%s\n", terminal.ColorYellow, launched.PID, port, restartWait, terminal.ColorReset)
+}
+
+// sendSignal sends a signal and, when the signal should end the process, verifies
+// the port is released. Signals such as SIGHUP that a service handles to reload
+// leave the process running, so the port is not checked.
+func sendSignal(processInfo *model.ProcessInfo, port int, killer action.Killer, signal syscall.Signal) {
+	masks, _ := action.ReadSignalMasks(processInfo.ID)
+	actedAt := time.Now()
+	if err := killer.Kill(processInfo.ID, signal); err != nil {
+		actionFailed("send "+action.SignalName(signal), err)
+	}
+	succeeded("%s✓ Successfully sent %s to process %d%s\n", terminal.ColorGreen, action.SignalName(signal), processInfo.ID, terminal.ColorReset)
+
+	if !action.ExpectsExit(signal, masks) {
+		return
+	}
+	warnSupervised(processInfo)
+	verifyPort(processInfo, port, actedAt)
+}
+
+// selectKiller returns the killer for the kill scope and previews the processes it
+// will signal. With --scope the scope is resolved directly; otherwise the prompter,
+// if any, offers the scopes that reach more than the single process. Returns false
+// when the user cancels.
+func selectKiller(processInfo *model.ProcessInfo, prompter *action.Prompter) (action.Killer, bool) {
+	resolver := action.NewScopeResolver()
+	if scopeName != "" {
+		target, err := resolver.Resolve(processInfo.ID, scope)
+		if err != nil {
+			fmt.Printf("%s✗ Cannot use scope %s:%s %v\n", terminal.ColorRed, scope, terminal.ColorReset, err)
+			exit(1)
+		}
+		fmt.Printf("%s→ Signalling %s: %s%s\n", terminal.ColorCyan, target.Describe(), target.Preview(scopePreviewMax), terminal.ColorReset)
+		return guardKiller(processInfo, scopedKiller(target), target, prompter), true
+	}
+	if prompter == nil {
+		return guardKiller(processInfo, holderKiller(), nil, nil), true
+	}
+
+	targets := resolver.ResolveAll(processInfo.ID)
+	for _, target := range targets {
+		if len(target.Processes) > 1 {
+			selected, ok := prompter.PromptScope(targets)
+			if !ok {
+				return nil, false
+			}
+			escalateFlags = append(escalateFlags, "--scope", selected.Scope.String())
+			return guardKiller(processInfo, scopedKiller(selected), selected, prompter), true
+		}
+	}
+	// Every scope reaches only the process itself
+	return guardKiller(processInfo, holderKiller(), nil, prompter), true
+}
+
+// guardKiller puts the protection check in front of killer. Every process a scope
+// reaches is checked, not only the port holder. The typed confirmation is read
+// through the prompter, or a new one in non-interactive mode. Signals, including
+// refused ones, are recorded in the audit log.
+func guardKiller(processInfo *model.ProcessInfo, killer action.Killer, target *action.Target, prompter *action.Prompter) action.Killer {
+	reasons := processInfo.Protected
+	if target != nil {
+		for _, p := range target.Processes {
+			if p.PID == processInfo.ID {
+				continue
 			}
-			fmt.Printf("%s✓ Successfully sent signal %v to process %d%s\n", terminal.ColorGreen, signal, processInfo.ID, terminal.ColorReset)
+			for _, reason := range guard.Check(safety.Subject{PID: p.PID, PPid: p.PPid, Command: p.Command}) {
+				reasons = append(reasons, fmt.Sprintf("PID %d in the scope: %s", p.PID, reason))
+			}
+		}
+	}
+	if prompter == nil {
+		prompter = action.NewPrompter()
+	}
+
+	guarded := safety.NewKiller(killer, reasons, processInfo.Command)
+	guarded.Force = forceFlag
+	guarded.Confirm = confirmTyped(prompter.ConfirmTyped)
+	if auditLog == nil {
+		return guarded
+	}
+
+	entry := auditBase
+	entry.Target = audit.ProcessTarget(processInfo)
+	if target != nil && target.Scope != action.ScopeProcess {
+		entry.Scope, entry.PIDs = target.Scope.String(), target.PIDs()
+	}
+	return audit.NewKiller(guarded, entry, logAction)
+}
+
+// containerGuard returns the check the Docker or Kubernetes action handler runs before
+// changing a protected container, service or pod. The name is typed only once per run; a refusal
+// is recorded in the audit log.
+func containerGuard(reasons []string, kind string, target audit.Target, confirm func(word string) bool) func() error {
+	confirmed := false
+	return func() error {
+		if confirmed {
+			return nil
 		}
+		if err := safety.Authorize(reasons, target.ContainerName, forceFlag, confirmTyped(confirm)); err != nil {
+			logAction(auditEntry(kind, target, nil, err))
+			return err
+		}
+		confirmed = true
+		return nil
+	}
+}
+
+// commandAuditor returns the hook that records the commands a Docker or Kubernetes
+// action handler runs against target
+func commandAuditor(kind string, target audit.Target) func(command []string, err error) {
+	return func(command []string, err error) {
+		actionRan = true
+		logAction(auditEntry(kind, target, command, err))
+	}
+}
+
+// auditEntry describes an action and its outcome for the audit log
+func auditEntry(kind string, target audit.Target, command []string, err error) audit.Entry {
+	entry := auditBase
+	entry.Time = time.Now()
+	entry.Action = kind
+	entry.Target = target
+	entry.Command = command
+	entry.Finish(err)
+	return entry
+}
+
+// logAction appends an entry to the audit log. A log that cannot be written is
+// reported, but does not undo or block the action.
+func logAction(entry audit.Entry) {
+	if auditLog == nil {
+		return
+	}
+	if err := auditLog.Log(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "%s⚠️  Could not write the audit log: %v%s\n", terminal.ColorYellow, err, terminal.ColorReset)
+	}
+}
+
+// refuseProtected tells the user why no actions are offered for a protected target
+// without --force. Returns true when the prompt should be skipped.
+func refuseProtected(reasons []string, kind string) bool {
+	if len(reasons) == 0 || forceFlag {
+		return false
+	}
+	fmt.Printf("\n%s🛡️  This %s is protected, so no actions are offered. Rerun with --force to act on it anyway.%s\n", terminal.ColorYellow, kind, terminal.ColorReset)
+	return true
+}
+
+// confirmTyped returns the typed confirmation to use. A dry run records that the
+// real run would ask for it instead of asking.
+func confirmTyped(confirm func(word string) bool) func(word string) bool {
+	if dryRun == nil {
+		return confirm
+	}
+	return func(word string) bool {
+		dryRun.Add(action.Operation{Kind: action.OpConfirm, Note: fmt.Sprintf("whoseport asks you to type %s to confirm", word)})
+		return true
+	}
+}
+
+// holderKiller returns a killer for the port holder alone that checks its identity.
+func holderKiller() action.Killer {
+	if dryRun != nil {
+		return action.NewPlanKiller(&dryRun.Plan, nil)
+	}
+	return action.NewKillerWithHandle(holderHandle)
+}
+
+// scopedKiller returns a killer for the target that checks the port holder's identity.
+func scopedKiller(target *action.Target) action.Killer {
+	if dryRun != nil {
+		return action.NewPlanKiller(&dryRun.Plan, target)
+	}
+	killer := action.NewScopedKiller(target)
+	killer.Handle = holderHandle
+	return killer
+}
+
+// verifyPort looks the port up again after an action: a delivered signal does not
+// mean the port is free, since children may hold the socket or a supervisor may
+// respawn the process. actedAt is when the first signal was sent, so a respawn during
+// a grace period is not mistaken for a child. Exits non-zero while the port is still occupied.
+func verifyPort(processInfo *model.ProcessInfo, port int, actedAt time.Time) {
+	if !checkPort(processInfo, port, actedAt) {
+		exit(1)
+	}
+}
+
+// checkPort reports whether the port was released after an action on processInfo taken at actedAt
+func checkPort(processInfo *model.ProcessInfo, port int, actedAt time.Time) bool {
+	if dryRun != nil {
+		return true
+	}
+	verifier := action.NewPortVerifier(process.NewDefaultRetriever())
+	result := verifier.Verify(port, processInfo, actedAt)
+	if result.Free() {
+		fmt.Printf("%s✓ Verified: %s%s\n", terminal.ColorGreen, result, terminal.ColorReset)
+		return true
+	}
+	fmt.Printf("%s✗ Verification failed: %s%s\n", terminal.ColorRed, result, terminal.ColorReset)
+	return false
+}
+
+// verifyReleased checks the port after a container, service or pod action and exits
+// non-zero if it is still in use
+func verifyReleased(processInfo *model.ProcessInfo, port int, actedAt time.Time) {
+	if !portReleased(processInfo, port, actedAt) {
+		exit(1)
+	}
+}
+
+// portReleased reports whether a container, service or pod action freed the port.
+// processInfo is nil when no host process held the port; the engines are then asked
+// whether a container or service still publishes it. Nothing is checked when no
+// command ran, e.g. after a cancelled prompt or in a dry run.
+func portReleased(processInfo *model.ProcessInfo, port int, actedAt time.Time) bool {
+	if !actionRan || dryRun != nil {
+		return true
+	}
+	if processInfo != nil {
+		return checkPort(processInfo, port, actedAt)
+	}
+
+	for _, runtime := range containerRuntimes() {
+		detector := dockerpkg.NewDetectorForRuntime(runtime)
+		owner, _ := detector.FindPublishedPort(port)
+		if owner == "" {
+			owner = detector.FindService(nil, port)
+		}
+		if owner != "" {
+			if len(owner) > 12 {
+				owner = owner[:12]
+			}
+			fmt.Printf("%s✗ Verification failed: port %d is still published by %s %s%s\n", terminal.ColorRed, port, runtime.Name, owner, terminal.ColorReset)
+			return false
+		}
+	}
+	fmt.Printf("%s✓ Verified: port %d is no longer published%s\n", terminal.ColorGreen, port, terminal.ColorReset)
+	return true
+}
+
+// releasesPort reports whether a container action should free the port. Restarts,
+// pauses, signals, logs and shells leave the container holding it.
+func releasesPort(a dockerpkg.Action) bool {
+	switch a {
+	case dockerpkg.ActionStop, dockerpkg.ActionStopAndRemove, dockerpkg.ActionRemove,
+		dockerpkg.ActionStopPod, dockerpkg.ActionRemovePod, dockerpkg.ActionStopService, dockerpkg.ActionStopProject:
+		return true
+	}
+	return false
+}
+
+// gracefulStop sends SIGTERM, waits for the process to exit and free the port, and
+// kills it with SIGKILL once the grace period expires.
+func gracefulStop(processInfo *model.ProcessInfo, port int, signaller action.Killer) {
+	if dryRun != nil {
+		if err := signaller.Kill(processInfo.ID, syscall.SIGTERM); err != nil {
+			actionFailed("stop process", err)
+		}
+		dryRun.Annotate(fmt.Sprintf("then wait up to %s for the process to exit", grace))
+		if err := signaller.Kill(processInfo.ID, syscall.SIGKILL); err != nil {
+			actionFailed("stop process", err)
+		}
+		dryRun.Annotate("only if the process is still running after the grace period")
+		return
+	}
+	retriever := process.NewDefaultRetriever()
+	killer := action.NewGracefulKiller(grace, os.Stdout)
+	killer.Killer = signaller
+	killer.PortHeld = func() bool {
+		_, err := retriever.GetProcessByPort(port)
+		return err == nil
+	}
+
+	result, err := killer.Stop(processInfo.ID)
+	if err != nil {
+		actionFailed("stop process", err)
+	}
+
+	switch result.Outcome {
+	case action.OutcomeExited:
+		fmt.Printf("%s✓ Process %d exited after SIGTERM in %s%s\n", terminal.ColorGreen, processInfo.ID, result.Elapsed.Round(100*time.Millisecond), terminal.ColorReset)
+	case action.OutcomeKilled:
+		fmt.Printf("%s✓ Process %d did not exit within %s and was killed with SIGKILL%s\n", terminal.ColorGreen, processInfo.ID, grace, terminal.ColorReset)
+	default:
+		fmt.Printf("%s✗ Process %d is still alive after SIGKILL (uninterruptible I/O or a kernel wait)%s\n", terminal.ColorRed, processInfo.ID, terminal.ColorReset)
+		exit(1)
+	}
+	warnSupervised(processInfo)
+}
+
+// actionFailed reports a failed action and exits. When the action was refused for
+// lack of permission, it first offers to run it again as root.
+func actionFailed(what string, err error) {
+	fmt.Printf("%s✗ Failed to %s:%s %v\n", terminal.ColorRed, what, terminal.ColorReset, err)
+	if errors.Is(err, syscall.EPERM) {
+		offerEscalation("You are not allowed to signal this process", escalateFlags...)
+	}
+	exit(1)
+}
+
+// exit ends the run with code. Deferred calls do not run on os.Exit, so a dry run
+// prints its plan here, including the steps recorded before a failure or refusal.
+func exit(code int) {
+	if dryRun != nil {
+		printDryRun(code != 0)
+	}
+	os.Exit(code)
+}
+
+// offerEscalation explains what permissions prevented and runs the same command line
+// again under sudo, doas or pkexec if the user agrees. extra flags repeat an action
+// chosen interactively. Without a terminal to ask on, it only suggests --sudo.
+// Returns when escalation is declined or impossible.
+func offerEscalation(reason string, extra ...string) {
+	if privilege.Elevated() {
+		return
+	}
+	tool, err := privilege.Tool()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s🔒 %s, and %v.%s\n", terminal.ColorYellow, reason, err, terminal.ColorReset)
+		return
+	}
+	if noInteractive || jsonFlag || !privilege.IsTerminal(os.Stdin) {
+		fmt.Fprintf(os.Stderr, "%s🔒 %s. Rerun with --sudo to use %s.%s\n", terminal.ColorYellow, reason, tool, terminal.ColorReset)
+		return
+	}
+	if !action.NewPrompter().PromptEscalate(reason, tool) {
+		return
+	}
+	if err := privilege.Reexec(tool, privilege.Args(os.Args[1:], flag.NArg(), extra...)); err != nil {
+		fmt.Printf("%s✗ Failed to run as root:%s %v\n", terminal.ColorRed, terminal.ColorReset, err)
+	}
+}
+
+// succeeded reports an action that went through; a dry run performed nothing, so
+// there is nothing to report until the plan is printed.
+func succeeded(format string, args ...any) {
+	if dryRun == nil {
+		fmt.Printf(format, args...)
+	}
+}
+
+// warnSupervised reminds the user that a supervisor may bring a killed process back.
+func warnSupervised(processInfo *model.ProcessInfo) {
+	s := processInfo.Supervisor
+	if s == nil {
+		return
+	}
+	fmt.Printf("%s⚠️  %s%s\n", terminal.ColorYellow, supervisor.Describe(s), terminal.ColorReset)
+	if len(s.StopCommand) > 0 {
+		fmt.Printf("%s   To stop it for good, run: %s%s\n", terminal.ColorYellow, strings.Join(s.StopCommand, " "), terminal.ColorReset)
+	}
+}
+
+// printDryRun prints the operations recorded by --dry-run, as a list of equivalent
+// shell commands or as the JSON report. failed is set when the run ends in an error
+// or a refusal.
+func printDryRun(failed bool) {
+	if jsonFlag {
+		j, err := json.MarshalIndent(dryRun, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%serror:%s failed to marshal JSON: %v\n", terminal.ColorRed, terminal.ColorReset, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", j)
+		return
+	}
+
+	ops := dryRun.Operations
+	if len(ops) == 0 && failed {
+		fmt.Printf("\n%s🧪 Dry run: the action would not go ahead, so nothing would change.%s\n", terminal.ColorCyan, terminal.ColorReset)
+		return
+	}
+	if len(ops) == 0 {
+		fmt.Printf("\n%s🧪 Dry run: no action was selected, so nothing would change.%s\n", terminal.ColorCyan, terminal.ColorReset)
+		return
+	}
+	fmt.Printf("\n%s%s🧪 Dry run: nothing was changed. whoseport would run:%s\n", terminal.ColorBold, terminal.ColorCyan, terminal.ColorReset)
+	for i, op := range ops {
+		fmt.Printf("  %s%d.%s %s\n", terminal.ColorYellow, i+1, terminal.ColorReset, op)
 	}
 }
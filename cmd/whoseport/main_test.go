@@ -11,6 +11,7 @@ import (
 
 	"github.com/bluehoodie/whoseport/internal/display/format"
 	displayjson "github.com/bluehoodie/whoseport/internal/display/json"
+	dockerpkg "github.com/bluehoodie/whoseport/internal/docker"
 	"github.com/bluehoodie/whoseport/internal/model"
 	"github.com/bluehoodie/whoseport/internal/process"
 	"github.com/bluehoodie/whoseport/internal/procfs"
@@ -571,3 +572,94 @@ func TestIsNoServiceError(t *testing.T) {
 		})
 	}
 }
+
+// TestReleasesPort tests which container actions are followed by a port check
+func TestReleasesPort(t *testing.T) {
+	tests := []struct {
+		action dockerpkg.Action
+		want   bool
+	}{
+		{dockerpkg.ActionStop, true},
+		{dockerpkg.ActionStopAndRemove, true},
+		{dockerpkg.ActionRemove, true},
+		{dockerpkg.ActionStopProject, true},
+		{dockerpkg.ActionRestart, false},
+		{dockerpkg.ActionPause, false},
+		{dockerpkg.ActionKill, false},
+		{dockerpkg.ActionLogs, false},
+		{dockerpkg.ActionCancel, false},
+	}
+
+	for _, tt := range tests {
+		if got := releasesPort(tt.action); got != tt.want {
+			t.Errorf("releasesPort(%v) = %v, want %v", tt.action, got, tt.want)
+		}
+	}
+}
+
+// TestPortReleasedWithoutAction tests that nothing is checked when no command ran
+func TestPortReleasedWithoutAction(t *testing.T) {
+	actionRan = false
+	if !portReleased(nil, 8080, time.Now()) {
+		t.Error("portReleased() should not check the port when no command ran")
+	}
+}
+
+// TestParsePort tests that the port is the only positional argument
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    int
+		wantErr string
+	}{
+		{[]string{"8080"}, 8080, ""},
+		{nil, 0, "missing port"},
+		{[]string{"http"}, 0, "integer"},
+		{[]string{"30", "8080"}, 0, `unexpected argument "8080"`}, // whoseport -t 30 8080
+		{[]string{"8080", "-k"}, 0, `unexpected argument "-k"`},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePort(tt.args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parsePort(%q) error = %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parsePort(%q) = %d, %v, want %d", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseLogArgs(t *testing.T) {
+	tests := []struct {
+		args      []string
+		wantPort  int
+		wantSince time.Duration
+		wantErr   string
+	}{
+		{[]string{"8080"}, 8080, 0, ""},
+		{[]string{"--since", "24h", "8080"}, 8080, 24 * time.Hour, ""},
+		{[]string{"8080", "--since", "24h"}, 8080, 24 * time.Hour, ""},
+		{[]string{"http"}, 0, 0, "integer"},
+		{[]string{"8080", "9090"}, 0, 0, `unexpected argument "9090"`},
+	}
+
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("whoseport log", flag.ContinueOnError)
+		since := fs.Duration("since", 0, "")
+		port := 0
+		err := parseLogArgs(fs, tt.args, &port)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseLogArgs(%q) error = %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || port != tt.wantPort || *since != tt.wantSince {
+			t.Errorf("parseLogArgs(%q) = port %d, since %s, %v, want port %d, since %s", tt.args, port, *since, err, tt.wantPort, tt.wantSince)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/terminal"
+)
+
+// DefaultGrace is how long a process gets to exit after SIGTERM before it is killed
+const DefaultGrace = 10 * time.Second
+
+// Polling intervals of the graceful stop
+const (
+	pollInterval     = 100 * time.Millisecond
+	progressInterval = time.Second
+	killWait         = 2 * time.Second // How long to wait for the exit after SIGKILL
+)
+
+// Outcome describes how a graceful stop ended
+type Outcome int
+
+const (
+	OutcomeExited Outcome = iota // The process exited within the grace period
+	OutcomeKilled                // The process was killed with SIGKILL after the grace period
+	OutcomeAlive                 // The process survived SIGKILL (uninterruptible sleep, or not ours to kill)
+)
+
+// String returns the outcome as a word, e.g. "exited"
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeExited:
+		return "exited"
+	case OutcomeKilled:
+		return "killed"
+	default:
+		return "still alive"
+	}
+}
+
+// StopResult reports what a graceful stop did
+type StopResult struct {
+	Outcome      Outcome
+	Signal       syscall.Signal // Signal that ended the process (SIGTERM or SIGKILL)
+	Elapsed      time.Duration  // Time from the first signal until the process was gone
+	PortReleased bool           // The port was seen free while the process was stopping
+}
+
+// GracefulKiller sends SIGTERM, waits for the process to exit and escalates to
+// SIGKILL when the grace period expires.
+// Following Open/Closed Principle: a new Killer strategy, the plain killer is unchanged
+type GracefulKiller struct {
+	Grace    time.Duration
+	Killer   Killer      // Sends the signals, e.g. a ScopedKiller; defaults to the single-PID killer
+	PortHeld func() bool // Reports whether the port is still held; nil skips the port check
+	progress io.Writer
+	now      func() time.Time
+	sleep    func(time.Duration)
+}
+
+// NewGracefulKiller creates a GracefulKiller that reports its progress to w. A zero
+// grace sends SIGKILL right after the first signal, like docker stop -t 0; a
+// negative one uses DefaultGrace.
+func NewGracefulKiller(grace time.Duration, w io.Writer) *GracefulKiller {
+	if grace < 0 {
+		grace = DefaultGrace
+	}
+	return &GracefulKiller{
+		Grace:    grace,
+		Killer:   NewKiller(),
+		progress: w,
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+}
+
+// survivorKiller is a Killer that signals more than the port holder, e.g. a
+// ScopedKiller; the graceful stop then waits for all of its processes
+type survivorKiller interface {
+	Survivors() []int
+}
+
+// wrappedKiller is a Killer that passes signals on to another, e.g. the protection
+// check or the audit log
+type wrappedKiller interface {
+	Unwrap() Killer
+}
+
+// Kill stops the process, starting with the given signal instead of SIGTERM.
+// It returns an error if the process is still alive afterwards.
+func (g *GracefulKiller) Kill(pid int, signal syscall.Signal) error {
+	result, err := g.stop(pid, signal)
+	if err != nil {
+		return err
+	}
+	if result.Outcome == OutcomeAlive {
+		return fmt.Errorf("process %d is still alive after SIGKILL", pid)
+	}
+	return nil
+}
+
+// Stop sends SIGTERM and escalates to SIGKILL if the process outlives the grace period
+func (g *GracefulKiller) Stop(pid int) (StopResult, error) {
+	return g.stop(pid, syscall.SIGTERM)
+}
+
+func (g *GracefulKiller) stop(pid int, signal syscall.Signal) (StopResult, error) {
+	if err := g.Killer.Kill(pid, signal); err != nil {
+		return StopResult{}, err
+	}
+
+	start := g.now()
+	result := StopResult{Signal: signal}
+	g.printf("%s→ Sent %s to process %d, waiting up to %s for it to exit%s\n",
+		terminal.ColorCyan, SignalName(signal), pid, g.Grace, terminal.ColorReset)
+
+	if g.waitExit(pid, start, g.Grace, &result) {
+		result.Outcome = OutcomeExited
+		result.Elapsed = g.now().Sub(start)
+		return result, nil
+	}
+
+	survivors := g.survivors(pid)
+	if len(survivors) > 1 {
+		g.printf("%s→ %d processes are still running after %s (%s), sending SIGKILL%s\n",
+			terminal.ColorYellow, len(survivors), g.Grace, joinPIDs(survivors), terminal.ColorReset)
+	} else {
+		running := pid
+		if len(survivors) == 1 {
+			running = survivors[0]
+		}
+		g.printf("%s→ Process %d is still running after %s, sending SIGKILL%s\n",
+			terminal.ColorYellow, running, g.Grace, terminal.ColorReset)
+	}
+	// The process may exit between the last check and SIGKILL
+	if err := g.Killer.Kill(pid, syscall.SIGKILL); err != nil && len(g.survivors(pid)) > 0 {
+		return result, err
+	}
+	result.Signal = syscall.SIGKILL
+
+	if g.waitExit(pid, g.now(), killWait, &result) {
+		result.Outcome = OutcomeKilled
+	} else {
+		result.Outcome = OutcomeAlive
+	}
+	result.Elapsed = g.now().Sub(start)
+	return result, nil
+}
+
+// survivors returns the processes still running: those of the killer's scope, found
+// through any wrappers, or the process alone
+func (g *GracefulKiller) survivors(pid int) []int {
+	for k := g.Killer; k != nil; {
+		if s, ok := k.(survivorKiller); ok {
+			return s.Survivors()
+		}
+		w, ok := k.(wrappedKiller)
+		if !ok {
+			break
+		}
+		k = w.Unwrap()
+	}
+	if processAlive(pid) {
+		return []int{pid}
+	}
+	return nil
+}
+
+// waitExit polls until the process and the rest of the killer's scope are gone or
+// the timeout expires, reporting progress once a second. The port check forks lsof, so it runs with the progress
+// report rather than on every poll.
+func (g *GracefulKiller) waitExit(pid int, start time.Time, timeout time.Duration, result *StopResult) bool {
+	lastProgress := start
+	for {
+		if len(g.survivors(pid)) == 0 {
+			return true
+		}
+
+		now := g.now()
+		if now.Sub(start) >= timeout {
+			return false
+		}
+		if now.Sub(lastProgress) >= progressInterval {
+			lastProgress = now
+			if !result.PortReleased && g.PortHeld != nil && !g.PortHeld() {
+				result.PortReleased = true
+				g.printf("%s  ✓ Port released after %s, waiting for the process to exit%s\n",
+					terminal.ColorGreen, formatElapsed(now.Sub(start)), terminal.ColorReset)
+			}
+			g.printf("%s  … still running (%s / %s)%s\n",
+				terminal.ColorDim, formatElapsed(now.Sub(start)), timeout, terminal.ColorReset)
+		}
+		g.sleep(pollInterval)
+	}
+}
+
+func (g *GracefulKiller) printf(format string, args ...any) {
+	if g.progress != nil {
+		fmt.Fprintf(g.progress, format, args...)
+	}
+}
+
+// processAlive reports whether the process exists and is not a zombie.
+// A zombie has exited; it only waits for its parent to collect the status.
+func processAlive(pid int) bool {
+	if err := syscall.Kill(pid, 0); err != nil && err != syscall.EPERM {
+		return false
+	}
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		// No procfs (macOS): the signal check is all there is
+		return true
+	}
+	// The state follows the command name, which is in parentheses and may contain spaces
+	stat := string(data)
+	if i := strings.LastIndexByte(stat, ')'); i >= 0 && i+2 < len(stat) {
+		return stat[i+2] != 'Z'
+	}
+	return true
+}
+
+// joinPIDs lists PIDs for progress output, e.g. "4240, 4242"
+func joinPIDs(pids []int) string {
+	parts := make([]string, len(pids))
+	for i, pid := range pids {
+		parts[i] = strconv.Itoa(pid)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatElapsed rounds a duration for progress output, e.g. "1.2s"
+func formatElapsed(d time.Duration) string {
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,194 @@
+package action
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startProcess starts a test process and reaps it in the background, so it does
+// not linger as a zombie once it is killed
+func startProcess(t *testing.T, name string, args ...string) int {
+	t.Helper()
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start test process: %v", err)
+	}
+	t.Cleanup(func() { cmd.Process.Kill() })
+	go cmd.Wait()
+	return cmd.Process.Pid
+}
+
+// TestGracefulKillerExited tests a process that exits on SIGTERM
+func TestGracefulKillerExited(t *testing.T) {
+	pid := startProcess(t, "sleep", "30")
+	output := &bytes.Buffer{}
+
+	result, err := NewGracefulKiller(5*time.Second, output).Stop(pid)
+	if err != nil {
+		t.Fatalf("Stop() failed: %v", err)
+	}
+	if result.Outcome != OutcomeExited {
+		t.Errorf("Expected outcome exited, got %v", result.Outcome)
+	}
+	if result.Signal != syscall.SIGTERM {
+		t.Errorf("Expected the process to end with SIGTERM, got %v", result.Signal)
+	}
+	if !strings.Contains(output.String(), "Sent SIGTERM") {
+		t.Errorf("Progress should report the SIGTERM, got %q", output.String())
+	}
+}
+
+// TestGracefulKillerKilled tests escalation to SIGKILL for a process that ignores SIGTERM
+func TestGracefulKillerKilled(t *testing.T) {
+	pid := startProcess(t, "sh", "-c", "trap '' TERM; while :; do sleep 0.1; done")
+	time.Sleep(100 * time.Millisecond) // Let the shell install the trap
+	output := &bytes.Buffer{}
+
+	result, err := NewGracefulKiller(300*time.Millisecond, output).Stop(pid)
+	if err != nil {
+		t.Fatalf("Stop() failed: %v", err)
+	}
+	if result.Outcome != OutcomeKilled {
+		t.Errorf("Expected outcome killed, got %v", result.Outcome)
+	}
+	if result.Signal != syscall.SIGKILL {
+		t.Errorf("Expected the process to end with SIGKILL, got %v", result.Signal)
+	}
+	if !strings.Contains(output.String(), "sending SIGKILL") {
+		t.Errorf("Progress should report the escalation, got %q", output.String())
+	}
+}
+
+// TestGracefulKillerZeroGrace tests that a zero grace period kills at once instead
+// of falling back to the default
+func TestGracefulKillerZeroGrace(t *testing.T) {
+	pid := startProcess(t, "sh", "-c", "trap '' TERM; while :; do sleep 0.1; done")
+	time.Sleep(100 * time.Millisecond)
+
+	begin := time.Now()
+	result, err := NewGracefulKiller(0, nil).Stop(pid)
+	if err != nil {
+		t.Fatalf("Stop() failed: %v", err)
+	}
+	if result.Outcome != OutcomeKilled {
+		t.Errorf("Expected outcome killed, got %v", result.Outcome)
+	}
+	if elapsed := time.Since(begin); elapsed >= DefaultGrace/2 {
+		t.Errorf("Stop() took %s, want SIGKILL without waiting", elapsed)
+	}
+}
+
+// TestGracefulKillerPortReleased tests that the port release is reported while waiting,
+// and that the port is checked once per progress report rather than on every poll
+func TestGracefulKillerPortReleased(t *testing.T) {
+	pid := startProcess(t, "sh", "-c", "trap '' TERM; while :; do sleep 0.1; done")
+	time.Sleep(100 * time.Millisecond)
+	output := &bytes.Buffer{}
+
+	killer := NewGracefulKiller(3*time.Second, output)
+	clock := time.Now()
+	killer.now = func() time.Time { return clock }
+	killer.sleep = func(d time.Duration) {
+		clock = clock.Add(d)
+		time.Sleep(5 * time.Millisecond) // Leave the process time to die after SIGKILL
+	}
+	checks := 0
+	killer.PortHeld = func() bool {
+		checks++
+		return checks < 2
+	}
+
+	result, err := killer.Stop(pid)
+	if err != nil {
+		t.Fatalf("Stop() failed: %v", err)
+	}
+	if !result.PortReleased {
+		t.Error("Expected the port release to be recorded")
+	}
+	if !strings.Contains(output.String(), "Port released after 2s") {
+		t.Errorf("Progress should report the port release, got %q", output.String())
+	}
+	if checks != 2 {
+		t.Errorf("PortHeld() called %d times, want once per second until the port was released", checks)
+	}
+}
+
+// passKiller wraps a Killer like the protection check and the audit log do
+type passKiller struct {
+	Killer
+}
+
+func (k passKiller) Unwrap() Killer {
+	return k.Killer
+}
+
+// TestGracefulKillerScopeSurvivors tests that a process of the scope that ignores
+// SIGTERM is killed with SIGKILL even though the port holder exited, also when the
+// scope's killer is wrapped
+func TestGracefulKillerScopeSurvivors(t *testing.T) {
+	holder := startProcess(t, "sleep", "30")
+	survivor := startProcess(t, "sh", "-c", "trap '' TERM; while :; do sleep 0.1; done")
+	time.Sleep(100 * time.Millisecond)
+	output := &bytes.Buffer{}
+
+	target := &Target{Scope: ScopeDescendants, PID: holder, Processes: []ProcEntry{{PID: holder}, {PID: survivor}}}
+	killer := NewGracefulKiller(300*time.Millisecond, output)
+	killer.Killer = passKiller{NewScopedKiller(target)}
+
+	result, err := killer.Stop(holder)
+	if err != nil {
+		t.Fatalf("Stop() failed: %v", err)
+	}
+	if result.Outcome != OutcomeKilled {
+		t.Errorf("Expected outcome killed, got %v", result.Outcome)
+	}
+	if processAlive(survivor) {
+		t.Error("The process that ignored SIGTERM should have been killed")
+	}
+	if !strings.Contains(output.String(), fmt.Sprintf("Process %d is still running", survivor)) {
+		t.Errorf("Progress should name the survivors, got %q", output.String())
+	}
+}
+
+// TestGracefulKillerInvalidPID tests error handling for non-existent PIDs
+func TestGracefulKillerInvalidPID(t *testing.T) {
+	if _, err := NewGracefulKiller(time.Second, nil).Stop(999999); err == nil {
+		t.Error("Stop() should return error for invalid PID")
+	}
+}
+
+// TestGracefulKillerImplementsKiller tests the strategy through the Killer interface
+func TestGracefulKillerImplementsKiller(t *testing.T) {
+	pid := startProcess(t, "sleep", "30")
+
+	var killer Killer = NewGracefulKiller(time.Second, nil)
+	if err := killer.Kill(pid, syscall.SIGTERM); err != nil {
+		t.Errorf("Kill() failed: %v", err)
+	}
+	if processAlive(pid) {
+		t.Error("Process should be gone after Kill()")
+	}
+}
+
+// TestOutcomeString tests the outcome names used in reports
+func TestOutcomeString(t *testing.T) {
+	tests := []struct {
+		outcome  Outcome
+		expected string
+	}{
+		{OutcomeExited, "exited"},
+		{OutcomeKilled, "killed"},
+		{OutcomeAlive, "still alive"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.outcome.String(); got != tt.expected {
+			t.Errorf("Outcome(%d).String() = %q, want %q", tt.outcome, got, tt.expected)
+		}
+	}
+}
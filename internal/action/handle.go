@@ -0,0 +1,88 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/procfs"
+)
+
+// ErrProcessChanged reports that a PID no longer belongs to the process that was looked up
+var ErrProcessChanged = errors.New("process identity changed")
+
+// Handle pins the identity of a process at lookup time, so that a signal sent
+// seconds later cannot reach a different process that reused the PID.
+// On Linux 5.3+ it holds a pidfd; elsewhere the start time is re-checked before
+// every signal.
+type Handle struct {
+	PID       int
+	StartedAt time.Time // Zero when the start time could not be read
+	pidfd     int       // -1 when no pidfd is held
+}
+
+// OpenHandle captures the identity of a process. It fails only if the process is
+// already gone; a missing pidfd or start time weakens the check but is not an error.
+func OpenHandle(pid int) (*Handle, error) {
+	h := &Handle{PID: pid, pidfd: -1}
+	if started, err := procfs.StartedAt(pid); err == nil {
+		h.StartedAt = started
+	}
+
+	fd, err := pidfdOpen(pid)
+	switch {
+	case err == nil:
+		h.pidfd = fd
+	case err == syscall.ESRCH:
+		return nil, fmt.Errorf("process with PID %d does not exist: %w", pid, err)
+	}
+	return h, nil
+}
+
+// Verify checks that the PID still belongs to the captured process
+func (h *Handle) Verify() error {
+	if h.pidfd >= 0 {
+		return h.Signal(0)
+	}
+	if h.StartedAt.IsZero() {
+		return nil
+	}
+
+	started, err := procfs.StartedAt(h.PID)
+	if err != nil {
+		return fmt.Errorf("process %d has exited since it was looked up: %w", h.PID, ErrProcessChanged)
+	}
+	if !started.Equal(h.StartedAt) {
+		return fmt.Errorf("refusing to signal PID %d: it was reused by a process started %s, the one looked up started %s: %w",
+			h.PID, started.Format(time.DateTime), h.StartedAt.Format(time.DateTime), ErrProcessChanged)
+	}
+	return nil
+}
+
+// Signal sends a signal to the captured process, through the pidfd when one is held
+func (h *Handle) Signal(signal syscall.Signal) error {
+	if h.pidfd < 0 {
+		if err := h.Verify(); err != nil {
+			return err
+		}
+		return syscall.Kill(h.PID, signal)
+	}
+
+	err := pidfdSendSignal(h.pidfd, signal)
+	if err == syscall.ESRCH {
+		// The pidfd refers to the original process only, even if the PID was reused
+		return fmt.Errorf("process %d has exited since it was looked up: %w", h.PID, ErrProcessChanged)
+	}
+	return err
+}
+
+// Close releases the pidfd
+func (h *Handle) Close() error {
+	if h.pidfd < 0 {
+		return nil
+	}
+	err := syscall.Close(h.pidfd)
+	h.pidfd = -1
+	return err
+}
@@ -0,0 +1,91 @@
+package action
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestHandleSignal tests signalling a live process through its handle
+func TestHandleSignal(t *testing.T) {
+	pid := startProcess(t, "sleep", "30")
+
+	handle, err := OpenHandle(pid)
+	if err != nil {
+		t.Fatalf("OpenHandle() failed: %v", err)
+	}
+	defer handle.Close()
+
+	if err := handle.Verify(); err != nil {
+		t.Errorf("Verify() failed for a live process: %v", err)
+	}
+	if err := NewKillerWithHandle(handle).Kill(pid, syscall.SIGKILL); err != nil {
+		t.Errorf("Kill() through the handle failed: %v", err)
+	}
+}
+
+// TestHandleRefusesExitedProcess tests that a handle never signals a process that
+// exited after the lookup, whatever now uses its PID
+func TestHandleRefusesExitedProcess(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start test process: %v", err)
+	}
+	pid := cmd.Process.Pid
+
+	handle, err := OpenHandle(pid)
+	if err != nil {
+		t.Fatalf("OpenHandle() failed: %v", err)
+	}
+	defer handle.Close()
+
+	cmd.Process.Kill()
+	cmd.Wait()
+
+	err = NewKillerWithHandle(handle).Kill(pid, syscall.SIGTERM)
+	if !errors.Is(err, ErrProcessChanged) {
+		t.Errorf("Kill() should refuse with ErrProcessChanged, got %v", err)
+	}
+}
+
+// TestHandleStartTimeFallback tests the identity check used without a pidfd
+func TestHandleStartTimeFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		startedAt time.Time
+		changed   bool
+	}{
+		{"unknown start time", time.Time{}, false},
+		{"reused PID", time.Unix(1, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handle := &Handle{PID: os.Getpid(), StartedAt: tt.startedAt, pidfd: -1}
+			err := handle.Verify()
+			if errors.Is(err, ErrProcessChanged) != tt.changed {
+				t.Errorf("Verify() = %v, want changed=%v", err, tt.changed)
+			}
+		})
+	}
+
+	// The start time captured now matches, so the process is accepted
+	handle, err := OpenHandle(os.Getpid())
+	if err != nil {
+		t.Fatalf("OpenHandle() failed: %v", err)
+	}
+	handle.Close()
+	if err := handle.Verify(); err != nil {
+		t.Errorf("Verify() failed for an unchanged process: %v", err)
+	}
+}
+
+// TestKillerWithNilHandle tests that a nil handle keeps the plain behavior
+func TestKillerWithNilHandle(t *testing.T) {
+	if err := NewKillerWithHandle(nil).Kill(999999, syscall.SIGTERM); err == nil {
+		t.Error("Kill() should return error for invalid PID")
+	}
+}
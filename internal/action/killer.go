@@ -1,6 +1,7 @@
 package action
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"syscall"
@@ -13,16 +14,29 @@ type Killer interface {
 }
 
 // killer implements the Killer interface
-type killer struct{}
+type killer struct {
+	handle *Handle
+}
 
 // NewKiller creates a new Killer implementation
 func NewKiller() Killer {
 	return &killer{}
 }
 
+// NewKillerWithHandle creates a Killer that signals the handle's PID through the
+// handle, refusing if the PID now belongs to a different process. A nil handle
+// behaves like NewKiller.
+func NewKillerWithHandle(handle *Handle) Killer {
+	return &killer{handle: handle}
+}
+
 // Kill terminates a process by PID with the specified signal
 // Follows Single Responsibility Principle: only sends signals to processes
 func (k *killer) Kill(pid int, signal syscall.Signal) error {
+	if k.handle != nil && k.handle.PID == pid {
+		return k.killHandle(signal)
+	}
+
 	process, err := os.FindProcess(pid)
 	if err != nil {
 		return fmt.Errorf("failed to find process: %w", err)
@@ -42,3 +56,17 @@ func (k *killer) Kill(pid int, signal syscall.Signal) error {
 
 	return nil
 }
+
+// killHandle signals the process pinned by the handle
+func (k *killer) killHandle(signal syscall.Signal) error {
+	if err := k.handle.Signal(syscall.Signal(0)); err != nil {
+		if errors.Is(err, ErrProcessChanged) {
+			return err
+		}
+		return fmt.Errorf("process with PID %d does not exist or is not accessible: %w", k.handle.PID, err)
+	}
+	if err := k.handle.Signal(signal); err != nil {
+		return fmt.Errorf("failed to send signal %v: %w", signal, err)
+	}
+	return nil
+}
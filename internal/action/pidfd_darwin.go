@@ -0,0 +1,15 @@
+//go:build darwin
+
+package action
+
+import "syscall"
+
+// pidfdOpen is not available on macOS; handles fall back to start time checks
+func pidfdOpen(pid int) (int, error) {
+	return -1, syscall.ENOSYS
+}
+
+// pidfdSendSignal is never called without a pidfd
+func pidfdSendSignal(fd int, signal syscall.Signal) error {
+	return syscall.ENOSYS
+}
@@ -0,0 +1,24 @@
+//go:build linux
+
+package action
+
+import "syscall"
+
+// pidfdOpen returns a file descriptor referring to the process (Linux 5.3+)
+func pidfdOpen(pid int) (int, error) {
+	fd, _, errno := syscall.Syscall(sysPidfdOpen, uintptr(pid), 0, 0)
+	if errno != 0 {
+		return -1, errno
+	}
+	syscall.CloseOnExec(int(fd))
+	return int(fd), nil
+}
+
+// pidfdSendSignal sends a signal to the process a pidfd refers to (Linux 5.1+)
+func pidfdSendSignal(fd int, signal syscall.Signal) error {
+	_, _, errno := syscall.Syscall6(sysPidfdSendSignal, uintptr(fd), uintptr(signal), 0, 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build linux && !mips && !mipsle && !mips64 && !mips64le
+
+package action
+
+// Syscall numbers of pidfd_send_signal and pidfd_open in the unified table used by
+// every architecture except mips, which offsets its tables per ABI
+const (
+	sysPidfdSendSignal = 424
+	sysPidfdOpen       = 434
+)
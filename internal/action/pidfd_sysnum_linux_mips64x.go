@@ -0,0 +1,9 @@
+//go:build linux && (mips64 || mips64le)
+
+package action
+
+// Syscall numbers of pidfd_send_signal and pidfd_open for the mips n64 ABI (5000 + n)
+const (
+	sysPidfdSendSignal = 5424
+	sysPidfdOpen       = 5434
+)
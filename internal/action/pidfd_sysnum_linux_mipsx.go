@@ -0,0 +1,9 @@
+//go:build linux && (mips || mipsle)
+
+package action
+
+// Syscall numbers of pidfd_send_signal and pidfd_open for the mips o32 ABI (4000 + n)
+const (
+	sysPidfdSendSignal = 4424
+	sysPidfdOpen       = 4434
+)
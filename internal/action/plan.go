@@ -0,0 +1,127 @@
+package action
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Kinds of operations recorded by a dry run
+const (
+	OpSignal  = "signal"  // kill(2) of one or more processes, or of a process group
+	OpWrite   = "write"   // A write to a kernel control file, e.g. cgroup.kill
+	OpCommand = "command" // An external command, e.g. docker stop
+	OpConfirm = "confirm" // A typed confirmation the real run would ask for
+)
+
+// Operation is one side effect an action would have
+type Operation struct {
+	Kind    string   `json:"kind"`
+	Signal  string   `json:"signal,omitempty"`  // Signal name, e.g. SIGTERM
+	PIDs    []int    `json:"pids,omitempty"`    // Processes the signal reaches
+	PGID    int      `json:"pgid,omitempty"`    // Process group signalled with kill(-pgid)
+	Path    string   `json:"path,omitempty"`    // File written
+	Command []string `json:"command,omitempty"` // Command and its arguments
+	Note    string   `json:"note,omitempty"`    // When or why it happens
+}
+
+// String returns the operation as the equivalent shell command, e.g. "kill -TERM 4242"
+func (o Operation) String() string {
+	var s string
+	switch o.Kind {
+	case OpSignal:
+		signal := "-" + strings.TrimPrefix(o.Signal, "SIG")
+		if o.PGID != 0 {
+			s = fmt.Sprintf("kill %s -- -%d", signal, o.PGID)
+		} else {
+			pids := make([]string, len(o.PIDs))
+			for i, pid := range o.PIDs {
+				pids[i] = strconv.Itoa(pid)
+			}
+			s = fmt.Sprintf("kill %s %s", signal, strings.Join(pids, " "))
+		}
+	case OpWrite:
+		s = "echo 1 > " + o.Path
+	case OpCommand:
+		s = ShellJoin(o.Command)
+	case OpConfirm:
+		return "# " + o.Note
+	default:
+		s = o.Kind
+	}
+	if o.Note != "" {
+		s += "  # " + o.Note
+	}
+	return s
+}
+
+// Plan collects the operations an action would perform instead of performing them
+type Plan struct {
+	Operations []Operation `json:"operations"`
+}
+
+// Add records an operation
+func (p *Plan) Add(op Operation) {
+	p.Operations = append(p.Operations, op)
+}
+
+// Annotate sets the note of the last recorded operation
+func (p *Plan) Annotate(note string) {
+	if n := len(p.Operations); n > 0 {
+		p.Operations[n-1].Note = note
+	}
+}
+
+// PlanKiller records the signals it is asked to send in a Plan
+// Following Open/Closed Principle: stands in for any Killer, callers are unchanged
+type PlanKiller struct {
+	Plan   *Plan
+	Target *Target // Scope the signal reaches; nil for the port holder alone
+}
+
+// NewPlanKiller creates a Killer that records into plan; target may be nil
+func NewPlanKiller(plan *Plan, target *Target) *PlanKiller {
+	return &PlanKiller{Plan: plan, Target: target}
+}
+
+// Kill records how the signal would be delivered. It fails like a real kill when
+// the process is gone or may not be signalled.
+func (k *PlanKiller) Kill(pid int, signal syscall.Signal) error {
+	if err := syscall.Kill(pid, 0); err != nil {
+		return fmt.Errorf("process with PID %d does not exist or is not accessible: %w", pid, err)
+	}
+	if k.Target == nil {
+		k.Plan.Add(Operation{Kind: OpSignal, Signal: SignalName(signal), PIDs: []int{pid}})
+		return nil
+	}
+	k.Plan.Add(k.Target.operation(signal, cgroupRoot))
+	return nil
+}
+
+// operation describes how ScopedKiller.Kill delivers the signal to the target
+func (t *Target) operation(signal syscall.Signal, root string) Operation {
+	op := Operation{Kind: OpSignal, Signal: SignalName(signal), PIDs: t.PIDs()}
+	switch {
+	case t.Scope == ScopeGroup && t.Excluded == 0:
+		op.PGID = t.ID
+	case t.Scope == ScopeCgroup && t.Excluded == 0 && signal == syscall.SIGKILL:
+		op = Operation{Kind: OpWrite, PIDs: t.PIDs(), Path: filepath.Join(root, t.Cgroup, "cgroup.kill"),
+			Note: "kills every process of " + t.Describe()}
+	}
+	return op
+}
+
+// ShellJoin joins a command for display, quoting arguments that the shell would
+// split or expand, so the line can be pasted back into a shell
+func ShellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`*?[]{}()<>|&;#~!") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,87 @@
+package action
+
+import (
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestOperationString(t *testing.T) {
+	tests := []struct {
+		op   Operation
+		want string
+	}{
+		{Operation{Kind: OpSignal, Signal: "SIGTERM", PIDs: []int{4242}}, "kill -TERM 4242"},
+		{Operation{Kind: OpSignal, Signal: "SIGKILL", PIDs: []int{201, 202}}, "kill -KILL 201 202"},
+		{Operation{Kind: OpSignal, Signal: "SIGTERM", PIDs: []int{201, 200}, PGID: 200}, "kill -TERM -- -200"},
+		{Operation{Kind: OpWrite, Path: "/sys/fs/cgroup/dev/cgroup.kill"}, "echo 1 > /sys/fs/cgroup/dev/cgroup.kill"},
+		{Operation{Kind: OpCommand, Command: []string{"docker", "stop", "web"}}, "docker stop web"},
+		{Operation{Kind: OpCommand, Command: []string{"docker", "compose", "-p", "my app", "stop"}}, "docker compose -p 'my app' stop"},
+		{Operation{Kind: OpCommand, Command: []string{"echo", "it's"}}, `echo 'it'\''s'`},
+		{Operation{Kind: OpSignal, Signal: "SIGKILL", PIDs: []int{7}, Note: "after 10s"}, "kill -KILL 7  # after 10s"},
+		{Operation{Kind: OpConfirm, Note: "whoseport asks you to type nginx to confirm"}, "# whoseport asks you to type nginx to confirm"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.op.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	got := ShellJoin([]string{"docker", "run", "-e", "GREETING=hello world", "-e", "Q=it's", "", "nginx", "-g", "daemon off;"})
+	if want := `docker run -e 'GREETING=hello world' -e 'Q=it'\''s' '' nginx -g 'daemon off;'`; got != want {
+		t.Errorf("ShellJoin() = %s, want %s", got, want)
+	}
+}
+
+func TestTargetOperation(t *testing.T) {
+	tests := []struct {
+		scope  Scope
+		pid    int
+		signal syscall.Signal
+		want   Operation
+	}{
+		{ScopeProcess, 201, syscall.SIGTERM, Operation{Kind: OpSignal, Signal: "SIGTERM", PIDs: []int{201}}},
+		{ScopeGroup, 201, syscall.SIGTERM, Operation{Kind: OpSignal, Signal: "SIGTERM", PIDs: []int{201, 200, 202}, PGID: 200}},
+		{ScopeSession, 201, syscall.SIGKILL, Operation{Kind: OpSignal, Signal: "SIGKILL", PIDs: []int{201, 200, 202, 203}}},
+		{ScopeCgroup, 201, syscall.SIGTERM, Operation{Kind: OpSignal, Signal: "SIGTERM", PIDs: []int{201, 200, 202, 203}}},
+		{ScopeCgroup, 201, syscall.SIGKILL, Operation{Kind: OpWrite, PIDs: []int{201, 200, 202, 203},
+			Path: "/sys/fs/cgroup/user.slice/session-3.scope/dev/cgroup.kill", Note: "kills every process of cgroup /user.slice/session-3.scope/dev"}},
+	}
+
+	resolver := NewScopeResolverWithTable(testTable, 900)
+	for _, tt := range tests {
+		target, err := resolver.Resolve(tt.pid, tt.scope)
+		if err != nil {
+			t.Fatalf("Resolve(%v) failed: %v", tt.scope, err)
+		}
+		if got := target.operation(tt.signal, "/sys/fs/cgroup"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("operation(%v, %v) = %+v, want %+v", tt.scope, tt.signal, got, tt.want)
+		}
+	}
+}
+
+func TestPlanKiller(t *testing.T) {
+	plan := &Plan{}
+	killer := NewPlanKiller(plan, nil)
+
+	// The test process itself is signalled only on paper
+	if err := killer.Kill(os.Getpid(), syscall.SIGKILL); err != nil {
+		t.Fatalf("Kill() failed: %v", err)
+	}
+	plan.Annotate("recorded")
+	want := []Operation{{Kind: OpSignal, Signal: "SIGKILL", PIDs: []int{os.Getpid()}, Note: "recorded"}}
+	if !reflect.DeepEqual(plan.Operations, want) {
+		t.Errorf("Operations = %+v, want %+v", plan.Operations, want)
+	}
+
+	if err := killer.Kill(999999, syscall.SIGTERM); err == nil {
+		t.Error("Kill() should fail for a process that does not exist")
+	}
+	if len(plan.Operations) != 1 {
+		t.Errorf("a failed Kill() should not be recorded, got %d operations", len(plan.Operations))
+	}
+}
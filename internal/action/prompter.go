@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
@@ -16,6 +17,7 @@ import (
 // Following Dependency Inversion Principle: depends on io interfaces
 type Prompter struct {
 	reader      io.Reader
+	scanner     *bufio.Scanner // Shared by all prompts so buffered input is not lost between them
 	writer      io.Writer
 	colorBold   string
 	colorYellow string
@@ -48,41 +50,148 @@ func NewPrompterWithIO(reader io.Reader, writer io.Writer) *Prompter {
 	}
 }
 
+// input returns the scanner reading the user's answers
+func (p *Prompter) input() *bufio.Scanner {
+	if p.scanner == nil {
+		p.scanner = bufio.NewScanner(p.reader)
+	}
+	return p.scanner
+}
+
+// SignalOption describes a signal offered in signal menus
+type SignalOption struct {
+	Signal      syscall.Signal
+	Name        string
+	Description string
+}
+
+// CommonSignals are the signals offered by PromptSignal, in menu order
+// Shared with the container action menus so both offer the same choices
+var CommonSignals = []SignalOption{
+	{syscall.SIGTERM, "SIGTERM", "Graceful termination"},
+	{syscall.SIGKILL, "SIGKILL", "Force kill (cannot be caught)"},
+	{syscall.SIGHUP, "SIGHUP", "Hangup (reload config)"},
+	{syscall.SIGINT, "SIGINT", "Interrupt (Ctrl+C)"},
+}
+
+// FormatSignalOption renders a signal as a menu label, e.g. "SIGKILL (9)  - Force kill"
+func FormatSignalOption(s SignalOption) string {
+	return fmt.Sprintf("%-12s - %s", fmt.Sprintf("%s (%d)", s.Name, int(s.Signal)), s.Description)
+}
+
+// Action represents the action selected for a process in PromptKillAction.
+type Action int
+
+const (
+	ActionCancel Action = iota
+	ActionSignal
+	ActionSupervisorStop
+	ActionGracefulStop
+	ActionChooseSignal
+	ActionRestart
+)
+
 // PromptKillAction prompts the user to select an action for the process
-// Returns the selected signal and true if user wants to kill, or nil and false if cancelled
-func (p *Prompter) PromptKillAction(info *model.ProcessInfo) (syscall.Signal, bool) {
+// Returns ActionGracefulStop for SIGTERM with escalation to SIGKILL, ActionSignal
+// with the selected signal, ActionSupervisorStop when the user prefers the
+// supervisor-native stop, ActionChooseSignal when the user wants to pick another
+// signal with PromptSignalFor, ActionRestart to stop the process and start it again,
+// or ActionCancel if cancelled
+func (p *Prompter) PromptKillAction(info *model.ProcessInfo) (Action, syscall.Signal) {
 	fmt.Fprintf(p.writer, "%s%s⚠️  Process %d (%s) - Select action:%s\n",
 		p.colorBold, p.colorYellow, info.ID, info.Command, p.colorReset)
-	fmt.Fprintf(p.writer, "  [1] SIGTERM (15) - Graceful termination\n")
-	fmt.Fprintf(p.writer, "  [2] SIGKILL (9)  - Force kill (cannot be caught)\n")
-	fmt.Fprintf(p.writer, "  [3] Cancel\n")
-	fmt.Fprintf(p.writer, "%sChoice [3]:%s ", p.colorBold, p.colorReset)
+	fmt.Fprintf(p.writer, "  [1] %-12s - SIGTERM, then SIGKILL if still running after the grace period\n", "Stop")
+	fmt.Fprintf(p.writer, "  [2] %s\n", FormatSignalOption(CommonSignals[0]))
+	fmt.Fprintf(p.writer, "  [3] %s\n", FormatSignalOption(CommonSignals[1]))
+
+	// A supervised process is restarted by its supervisor, so the two never show together
+	supervised := info.Supervisor != nil && len(info.Supervisor.StopCommand) > 0
+	_, err := NewRestart(info)
+	restartable := err == nil
+	other, cancel := "4", "5"
+	if supervised {
+		fmt.Fprintf(p.writer, "  [4] Stop via %s (%s)\n", info.Supervisor.Kind, strings.Join(info.Supervisor.StopCommand, " "))
+		other, cancel = "5", "6"
+	} else if restartable {
+		fmt.Fprintf(p.writer, "  [4] %-12s - Stop, then start the same command line again\n", "Restart")
+		other, cancel = "5", "6"
+	}
+	fmt.Fprintf(p.writer, "  [%s] %-12s - Send another signal (SIGHUP, SIGUSR1, realtime, ...)\n", other, "Other")
+	fmt.Fprintf(p.writer, "  [%s] Cancel\n", cancel)
+	fmt.Fprintf(p.writer, "%sChoice [%s]:%s ", p.colorBold, cancel, p.colorReset)
 
-	scanner := bufio.NewScanner(p.reader)
+	scanner := p.input()
 
 	for {
 		if !scanner.Scan() {
-			return 0, false
+			return ActionCancel, 0
 		}
 
 		choice := strings.TrimSpace(scanner.Text())
 
 		// Default to Cancel if empty
 		if choice == "" {
-			choice = "3"
+			choice = cancel
 		}
 
-		switch choice {
-		case "1":
-			return syscall.SIGTERM, true
-		case "2":
-			return syscall.SIGKILL, true
-		case "3":
-			return 0, false
+		switch {
+		case choice == "1":
+			return ActionGracefulStop, syscall.SIGTERM
+		case choice == "2":
+			return ActionSignal, syscall.SIGTERM
+		case choice == "3":
+			return ActionSignal, syscall.SIGKILL
+		case choice == "4" && supervised:
+			return ActionSupervisorStop, 0
+		case choice == "4" && restartable:
+			return ActionRestart, 0
+		case choice == other:
+			return ActionChooseSignal, 0
+		case choice == cancel:
+			return ActionCancel, 0
 		default:
-			fmt.Fprintf(p.writer, "%sInvalid choice. Please enter 1-3.%s\n", p.colorYellow, p.colorReset)
-			fmt.Fprintf(p.writer, "%sChoice [3]:%s ", p.colorBold, p.colorReset)
+			fmt.Fprintf(p.writer, "%sInvalid choice. Please enter 1-%s.%s\n", p.colorYellow, cancel, p.colorReset)
+			fmt.Fprintf(p.writer, "%sChoice [%s]:%s ", p.colorBold, cancel, p.colorReset)
+		}
+	}
+}
+
+// scopePreviewMax is how many processes a scope menu entry lists
+const scopePreviewMax = 6
+
+// PromptScope asks which processes the signal should reach, previewing the PIDs of
+// each scope. Returns the selected target, or false if cancelled
+func (p *Prompter) PromptScope(targets []*Target) (*Target, bool) {
+	fmt.Fprintf(p.writer, "\n%s%sSelect scope (processes that receive the signal):%s\n", p.colorBold, p.colorCyan, p.colorReset)
+	for i, t := range targets {
+		fmt.Fprintf(p.writer, "  [%d] %-14s - %s: %s\n", i+1, t.Scope.Label(), t.Describe(), t.Preview(scopePreviewMax))
+	}
+	cancel := strconv.Itoa(len(targets) + 1)
+	fmt.Fprintf(p.writer, "  [%s] Cancel\n", cancel)
+	fmt.Fprintf(p.writer, "%sChoice [1]:%s ", p.colorBold, p.colorReset)
+
+	scanner := p.input()
+
+	for {
+		if !scanner.Scan() {
+			return nil, false
+		}
+
+		choice := strings.TrimSpace(scanner.Text())
+
+		// Default to the single process if empty
+		if choice == "" {
+			choice = "1"
+		}
+
+		if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(targets) {
+			return targets[n-1], true
+		}
+		if choice == cancel {
+			return nil, false
 		}
+		fmt.Fprintf(p.writer, "%sInvalid choice. Please enter 1-%s.%s\n", p.colorYellow, cancel, p.colorReset)
+		fmt.Fprintf(p.writer, "%sChoice [1]:%s ", p.colorBold, p.colorReset)
 	}
 }
 
@@ -92,7 +201,7 @@ func (p *Prompter) PromptKill(info *model.ProcessInfo) bool {
 	fmt.Fprintf(p.writer, "%s%s⚠️  Do you want to kill process %d (%s)?%s [y/N]: ",
 		p.colorBold, p.colorYellow, info.ID, info.Command, p.colorReset)
 
-	scanner := bufio.NewScanner(p.reader)
+	scanner := p.input()
 	if !scanner.Scan() {
 		return false
 	}
@@ -101,20 +210,95 @@ func (p *Prompter) PromptKill(info *model.ProcessInfo) bool {
 	return response == "y" || response == "yes"
 }
 
+// ConfirmTyped asks the user to type word to go ahead with an action on a protected
+// process. Anything else, including an empty answer, declines.
+func (p *Prompter) ConfirmTyped(word string) bool {
+	fmt.Fprintf(p.writer, "%s%s🛡️  Type %s to confirm:%s ", p.colorBold, p.colorYellow, word, p.colorReset)
+
+	scanner := p.input()
+	if !scanner.Scan() {
+		return false
+	}
+	return strings.TrimSpace(scanner.Text()) == word
+}
+
+// PromptEscalate explains what permissions prevented and asks whether to run
+// whoseport again under tool (sudo, doas or pkexec)
+func (p *Prompter) PromptEscalate(reason, tool string) bool {
+	fmt.Fprintf(p.writer, "%s%s🔒 %s.%s Run again with %s? [y/N]: ", p.colorBold, p.colorYellow, reason, p.colorReset, tool)
+
+	scanner := p.input()
+	if !scanner.Scan() {
+		return false
+	}
+
+	response := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	return response == "y" || response == "yes"
+}
+
+// pickerSignals are the signals listed by PromptSignalFor, in menu order: the
+// common ones first, so both signal menus start alike, then every other named
+// signal of the platform by number. Realtime signals are entered as Custom.
+var pickerSignals = pickerOrder([]syscall.Signal{
+	syscall.SIGTERM, syscall.SIGKILL, syscall.SIGHUP, syscall.SIGINT,
+	syscall.SIGQUIT, syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGSTOP, syscall.SIGCONT,
+})
+
+// pickerOrder appends the signals of signalTable missing from first, in numeric order
+func pickerOrder(first []syscall.Signal) []syscall.Signal {
+	var rest []syscall.Signal
+	for _, s := range signalTable {
+		if !slices.Contains(first, s.Signal) {
+			rest = append(rest, s.Signal)
+		}
+	}
+	slices.Sort(rest)
+	return append(first, rest...)
+}
+
 // PromptSignal prompts the user to select a signal to send
-// Supports common signals (SIGTERM, SIGKILL, SIGHUP, SIGINT) and custom numeric input
+// Supports common signals and any other signal by name or number
 // Following Single Responsibility Principle: only handles signal selection
 func (p *Prompter) PromptSignal() (syscall.Signal, error) {
-	// Display signal menu
+	return p.PromptSignalFor(nil)
+}
+
+// PromptSignalFor prompts the user to select a signal, showing each signal's default
+// action and, when masks is not nil, which signals the process handles, ignores or blocks
+func (p *Prompter) PromptSignalFor(masks *SignalMasks) (syscall.Signal, error) {
 	fmt.Fprintf(p.writer, "\n%s%sSelect signal to send:%s\n", p.colorBold, p.colorCyan, p.colorReset)
-	fmt.Fprintf(p.writer, "  [1] SIGTERM (15) - Graceful termination\n")
-	fmt.Fprintf(p.writer, "  [2] SIGKILL (9)  - Force kill (cannot be caught)\n")
-	fmt.Fprintf(p.writer, "  [3] SIGHUP (1)   - Hangup (reload config)\n")
-	fmt.Fprintf(p.writer, "  [4] SIGINT (2)   - Interrupt (Ctrl+C)\n")
-	fmt.Fprintf(p.writer, "  [5] Custom       - Enter signal number (1-31)\n")
+	if masks != nil {
+		if handled := masks.Handled(); len(handled) > 0 {
+			names := make([]string, len(handled))
+			for i, s := range handled {
+				names[i] = SignalName(s)
+			}
+			fmt.Fprintf(p.writer, "  Process has handlers for: %s\n", strings.Join(names, ", "))
+		} else {
+			fmt.Fprintf(p.writer, "  Process has no signal handlers: every signal takes its default action\n")
+		}
+	}
+
+	// Columns are wide enough for the longest names, e.g. "SIGVTALRM (26)"
+	for i, signal := range pickerSignals {
+		info, _ := LookupSignal(signal)
+		line := fmt.Sprintf("%-14s - %-36s  default: %s", fmt.Sprintf("%s (%d)", info.Name, int(info.Signal)), info.Description, info.Default)
+		if masks != nil {
+			if disposition := masks.Disposition(signal); disposition != "" {
+				line += fmt.Sprintf(" %s(%s)%s", p.colorYellow, disposition, p.colorReset)
+			}
+		}
+		fmt.Fprintf(p.writer, "  %-4s %s\n", fmt.Sprintf("[%d]", i+1), line)
+	}
+	custom := strconv.Itoa(len(pickerSignals) + 1)
+	hint := "Enter a signal name or number"
+	if rtMin > 0 {
+		hint = "Enter a realtime signal (RTMIN+n, RTMAX-n) or a number"
+	}
+	fmt.Fprintf(p.writer, "  %-4s %-14s - %s (1-%d)\n", "["+custom+"]", "Custom", hint, maxSignal)
 	fmt.Fprintf(p.writer, "%sChoice [1]:%s ", p.colorBold, p.colorReset)
 
-	scanner := bufio.NewScanner(p.reader)
+	scanner := p.input()
 
 	for {
 		if !scanner.Scan() {
@@ -128,47 +312,36 @@ func (p *Prompter) PromptSignal() (syscall.Signal, error) {
 			choice = "1"
 		}
 
+		if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(pickerSignals) {
+			return pickerSignals[n-1], nil
+		}
+
 		switch choice {
-		case "1":
-			return syscall.SIGTERM, nil
-		case "2":
-			return syscall.SIGKILL, nil
-		case "3":
-			return syscall.SIGHUP, nil
-		case "4":
-			return syscall.SIGINT, nil
-		case "5":
+		case custom:
 			return p.promptCustomSignal(scanner)
 		default:
-			fmt.Fprintf(p.writer, "%sInvalid choice. Please enter 1-5.%s\n", p.colorYellow, p.colorReset)
+			fmt.Fprintf(p.writer, "%sInvalid choice. Please enter 1-%s.%s\n", p.colorYellow, custom, p.colorReset)
 			fmt.Fprintf(p.writer, "%sChoice [1]:%s ", p.colorBold, p.colorReset)
 		}
 	}
 }
 
-// promptCustomSignal prompts for a custom signal number
-// Validates range 1-31 (standard Unix signal range)
+// promptCustomSignal prompts for a signal by name or number
+// Accepts anything ParseSignal does: HUP, SIGUSR1, 15, RTMIN+3
 func (p *Prompter) promptCustomSignal(scanner *bufio.Scanner) (syscall.Signal, error) {
-	fmt.Fprintf(p.writer, "%sEnter signal number (1-31):%s ", p.colorBold, p.colorReset)
+	fmt.Fprintf(p.writer, "%sEnter signal name or number (1-%d, e.g. USR1, RTMIN+3):%s ", p.colorBold, maxSignal, p.colorReset)
 
 	for {
 		if !scanner.Scan() {
-			return 0, fmt.Errorf("failed to read signal number: %w", scanner.Err())
+			return 0, fmt.Errorf("failed to read signal: %w", scanner.Err())
 		}
 
-		input := strings.TrimSpace(scanner.Text())
-		signalNum, err := strconv.Atoi(input)
-
+		signal, err := ParseSignal(scanner.Text())
 		if err != nil {
-			fmt.Fprintf(p.writer, "%sInvalid number. Please enter a number between 1-31:%s ", p.colorYellow, p.colorReset)
-			continue
-		}
-
-		if signalNum < 1 || signalNum > 31 {
-			fmt.Fprintf(p.writer, "%sSignal must be between 1-31. Please try again:%s ", p.colorYellow, p.colorReset)
+			fmt.Fprintf(p.writer, "%sInvalid signal: %v. Please try again:%s ", p.colorYellow, err, p.colorReset)
 			continue
 		}
 
-		return syscall.Signal(signalNum), nil
+		return signal, nil
 	}
 }
@@ -2,6 +2,8 @@ package action
 
 import (
 	"bytes"
+	"os"
+	"strconv"
 	"strings"
 	"syscall"
 	"testing"
@@ -11,7 +13,7 @@ import (
 
 // TestPromptKillActionSIGTERM tests selecting SIGTERM from the combined menu
 func TestPromptKillActionSIGTERM(t *testing.T) {
-	input := "1\n" // User selects option 1 (SIGTERM)
+	input := "2\n" // User selects option 2 (SIGTERM)
 	reader := strings.NewReader(input)
 	output := &bytes.Buffer{}
 
@@ -21,10 +23,10 @@ func TestPromptKillActionSIGTERM(t *testing.T) {
 		Command: "test-process",
 	}
 
-	signal, shouldKill := prompter.PromptKillAction(info)
+	action, signal := prompter.PromptKillAction(info)
 
-	if !shouldKill {
-		t.Error("PromptKillAction() should return true for SIGTERM selection")
+	if action != ActionSignal {
+		t.Error("PromptKillAction() should return ActionSignal for SIGTERM selection")
 	}
 	if signal != syscall.SIGTERM {
 		t.Errorf("Expected SIGTERM, got %v", signal)
@@ -45,7 +47,7 @@ func TestPromptKillActionSIGTERM(t *testing.T) {
 
 // TestPromptKillActionSIGKILL tests selecting SIGKILL from the combined menu
 func TestPromptKillActionSIGKILL(t *testing.T) {
-	input := "2\n" // User selects option 2 (SIGKILL)
+	input := "3\n" // User selects option 3 (SIGKILL)
 	reader := strings.NewReader(input)
 	output := &bytes.Buffer{}
 
@@ -55,10 +57,10 @@ func TestPromptKillActionSIGKILL(t *testing.T) {
 		Command: "test-process",
 	}
 
-	signal, shouldKill := prompter.PromptKillAction(info)
+	action, signal := prompter.PromptKillAction(info)
 
-	if !shouldKill {
-		t.Error("PromptKillAction() should return true for SIGKILL selection")
+	if action != ActionSignal {
+		t.Error("PromptKillAction() should return ActionSignal for SIGKILL selection")
 	}
 	if signal != syscall.SIGKILL {
 		t.Errorf("Expected SIGKILL, got %v", signal)
@@ -67,7 +69,7 @@ func TestPromptKillActionSIGKILL(t *testing.T) {
 
 // TestPromptKillActionCancel tests selecting Cancel from the combined menu
 func TestPromptKillActionCancel(t *testing.T) {
-	input := "3\n" // User selects option 3 (Cancel)
+	input := "5\n" // User selects option 5 (Cancel)
 	reader := strings.NewReader(input)
 	output := &bytes.Buffer{}
 
@@ -77,9 +79,9 @@ func TestPromptKillActionCancel(t *testing.T) {
 		Command: "test-process",
 	}
 
-	_, shouldKill := prompter.PromptKillAction(info)
+	action, _ := prompter.PromptKillAction(info)
 
-	if shouldKill {
+	if action != ActionCancel {
 		t.Error("PromptKillAction() should return false when user cancels")
 	}
 }
@@ -96,16 +98,16 @@ func TestPromptKillActionDefaultCancel(t *testing.T) {
 		Command: "test-process",
 	}
 
-	_, shouldKill := prompter.PromptKillAction(info)
+	action, _ := prompter.PromptKillAction(info)
 
-	if shouldKill {
+	if action != ActionCancel {
 		t.Error("PromptKillAction() should default to Cancel when user presses Enter")
 	}
 }
 
 // TestPromptKillActionInvalidOption tests handling of invalid menu selection
 func TestPromptKillActionInvalidOption(t *testing.T) {
-	input := "99\n1\n" // Invalid option first, then valid option (SIGTERM)
+	input := "99\n2\n" // Invalid option first, then valid option (SIGTERM)
 	reader := strings.NewReader(input)
 	output := &bytes.Buffer{}
 
@@ -115,10 +117,10 @@ func TestPromptKillActionInvalidOption(t *testing.T) {
 		Command: "test-process",
 	}
 
-	signal, shouldKill := prompter.PromptKillAction(info)
+	action, signal := prompter.PromptKillAction(info)
 
-	if !shouldKill {
-		t.Error("PromptKillAction() should return true after recovering from invalid input")
+	if action != ActionSignal {
+		t.Error("PromptKillAction() should return ActionSignal after recovering from invalid input")
 	}
 	if signal != syscall.SIGTERM {
 		t.Errorf("Expected SIGTERM after recovery, got %v", signal)
@@ -143,13 +145,101 @@ func TestPromptKillActionEOF(t *testing.T) {
 		Command: "test-process",
 	}
 
-	_, shouldKill := prompter.PromptKillAction(info)
+	action, _ := prompter.PromptKillAction(info)
 
-	if shouldKill {
+	if action != ActionCancel {
 		t.Error("PromptKillAction() should return false on EOF")
 	}
 }
 
+// TestPromptKillActionSupervisorStop tests the extra supervisor option for supervised processes
+func TestPromptKillActionSupervisorStop(t *testing.T) {
+	input := "4\n" // User selects option 4 (Stop via supervisor)
+	reader := strings.NewReader(input)
+	output := &bytes.Buffer{}
+
+	prompter := NewPrompterWithIO(reader, output)
+	info := &model.ProcessInfo{
+		ID:      12345,
+		Command: "node",
+		Supervisor: &model.SupervisorInfo{
+			Kind:        "pm2",
+			PID:         100,
+			Program:     "api",
+			Restarts:    true,
+			StopCommand: []string{"pm2", "stop", "api"},
+		},
+	}
+
+	action, _ := prompter.PromptKillAction(info)
+
+	if action != ActionSupervisorStop {
+		t.Errorf("Expected ActionSupervisorStop, got %v", action)
+	}
+
+	outputStr := output.String()
+	if !strings.Contains(outputStr, "pm2 stop api") {
+		t.Error("Menu should show the supervisor stop command")
+	}
+	if !strings.Contains(outputStr, "[6] Cancel") {
+		t.Error("Cancel should move to option 6 when a supervisor option is shown")
+	}
+}
+
+// TestPromptKillActionRestart tests the restart option for a process that can be started again
+func TestPromptKillActionRestart(t *testing.T) {
+	input := "4\n" // User selects option 4 (Restart)
+	reader := strings.NewReader(input)
+	output := &bytes.Buffer{}
+
+	prompter := NewPrompterWithIO(reader, output)
+	info := &model.ProcessInfo{
+		ID:         12345,
+		Command:    "sh",
+		Argv:       []string{"sh", "-c", "sleep 60"},
+		Environ:    []string{"PATH=/usr/bin:/bin"},
+		WorkingDir: t.TempDir(),
+		UID:        os.Geteuid(),
+	}
+
+	action, _ := prompter.PromptKillAction(info)
+
+	if action != ActionRestart {
+		t.Errorf("Expected ActionRestart, got %v", action)
+	}
+	if !strings.Contains(output.String(), "[6] Cancel") {
+		t.Error("Cancel should move to option 6 when the restart option is shown")
+	}
+}
+
+// TestPromptKillActionGracefulStop tests that the graceful stop is the first choice
+func TestPromptKillActionGracefulStop(t *testing.T) {
+	input := "1\n" // User selects option 1 (Stop)
+	reader := strings.NewReader(input)
+	output := &bytes.Buffer{}
+
+	prompter := NewPrompterWithIO(reader, output)
+	info := &model.ProcessInfo{
+		ID:      12345,
+		Command: "test-process",
+	}
+
+	action, signal := prompter.PromptKillAction(info)
+
+	if action != ActionGracefulStop {
+		t.Errorf("Expected ActionGracefulStop, got %v", action)
+	}
+	if signal != syscall.SIGTERM {
+		t.Errorf("Expected SIGTERM, got %v", signal)
+	}
+	if !strings.Contains(output.String(), "then SIGKILL") {
+		t.Error("Menu should explain the escalation to SIGKILL")
+	}
+}
+
+// customChoice is the menu entry for entering any signal, after the listed ones
+var customChoice = strconv.Itoa(len(pickerSignals) + 1)
+
 // TestPromptSignalSIGTERM tests selecting SIGTERM from the menu
 func TestPromptSignalSIGTERM(t *testing.T) {
 	input := "1\n" // User selects option 1 (SIGTERM)
@@ -226,7 +316,7 @@ func TestPromptSignalSIGINT(t *testing.T) {
 
 // TestPromptSignalCustomNumeric tests entering a custom signal number
 func TestPromptSignalCustomNumeric(t *testing.T) {
-	input := "5\n10\n" // User selects option 5 (Custom), then enters 10 (SIGUSR1)
+	input := customChoice + "\n10\n" // User selects Custom, then enters 10 (SIGUSR1)
 	reader := strings.NewReader(input)
 	output := &bytes.Buffer{}
 
@@ -242,8 +332,8 @@ func TestPromptSignalCustomNumeric(t *testing.T) {
 
 	// Verify custom prompt was displayed
 	outputStr := output.String()
-	if !strings.Contains(outputStr, "Enter signal number") || !strings.Contains(outputStr, "1-31") {
-		t.Error("Custom signal prompt should ask for signal number (1-31)")
+	if !strings.Contains(outputStr, "Enter signal name or number") {
+		t.Error("Custom signal prompt should ask for a signal name or number")
 	}
 }
 
@@ -276,10 +366,10 @@ func TestPromptSignalInvalidCustomNumber(t *testing.T) {
 		name  string
 		input string
 	}{
-		{"zero", "5\n0\n15\n"},          // 0 is invalid, then enter valid 15 (SIGTERM)
-		{"negative", "5\n-1\n15\n"},     // negative is invalid
-		{"too high", "5\n99\n15\n"},     // >31 is invalid
-		{"non-numeric", "5\nabc\n15\n"}, // non-number is invalid
+		{"zero", customChoice + "\n0\n15\n"},           // 0 is invalid, then enter valid 15 (SIGTERM)
+		{"negative", customChoice + "\n-1\n15\n"},      // negative is invalid
+		{"too high", customChoice + "\n999\n15\n"},     // beyond the last signal is invalid
+		{"unknown name", customChoice + "\nabc\n15\n"}, // not a signal name
 	}
 
 	for _, tt := range tests {
@@ -393,3 +483,131 @@ func TestPromptKillNo(t *testing.T) {
 		t.Error("PromptKill() should return false for 'n' input")
 	}
 }
+
+// TestPromptScope tests selecting a scope from the preview menu
+func TestPromptScope(t *testing.T) {
+	targets := []*Target{
+		{Scope: ScopeProcess, PID: 201, Processes: []ProcEntry{{PID: 201, Command: "node"}}},
+		{Scope: ScopeGroup, PID: 201, ID: 200, Processes: []ProcEntry{{PID: 201, Command: "node"}, {PID: 200, Command: "npm"}}},
+	}
+
+	tests := []struct {
+		input    string
+		expected *Target
+		ok       bool
+	}{
+		{"2\n", targets[1], true},
+		{"\n", targets[0], true},     // Enter selects the single process
+		{"9\n1\n", targets[0], true}, // Invalid choice, then the single process
+		{"3\n", nil, false},          // Cancel
+		{"", nil, false},             // EOF
+	}
+
+	for _, tt := range tests {
+		output := &bytes.Buffer{}
+		prompter := NewPrompterWithIO(strings.NewReader(tt.input), output)
+
+		target, ok := prompter.PromptScope(targets)
+		if target != tt.expected || ok != tt.ok {
+			t.Errorf("PromptScope(%q) = %v, %v, want %v, %v", tt.input, target, ok, tt.expected, tt.ok)
+		}
+		if !strings.Contains(output.String(), "process group 200: 201 (node), 200 (npm)") {
+			t.Errorf("Menu should preview the PIDs of each scope, got %q", output.String())
+		}
+	}
+}
+
+// TestPromptKillActionOtherSignal tests the entry that leads to the full signal picker
+func TestPromptKillActionOtherSignal(t *testing.T) {
+	prompter := NewPrompterWithIO(strings.NewReader("4\n"), &bytes.Buffer{})
+	info := &model.ProcessInfo{ID: 12345, Command: "test-process"}
+
+	if action, _ := prompter.PromptKillAction(info); action != ActionChooseSignal {
+		t.Errorf("Expected ActionChooseSignal, got %v", action)
+	}
+}
+
+// TestPromptSignalForShowsHandlers tests the decoded signal masks in the picker
+func TestPromptSignalForShowsHandlers(t *testing.T) {
+	output := &bytes.Buffer{}
+	prompter := NewPrompterWithIO(strings.NewReader("3\n"), output)
+	masks := &SignalMasks{caught: sigset{1<<(uint(syscall.SIGHUP)-1) | 1<<(uint(syscall.SIGTERM)-1)}}
+
+	signal, err := prompter.PromptSignalFor(masks)
+	if err != nil {
+		t.Fatalf("PromptSignalFor() failed: %v", err)
+	}
+	if signal != syscall.SIGHUP {
+		t.Errorf("Expected SIGHUP, got %v", signal)
+	}
+
+	outputStr := output.String()
+	if !strings.Contains(outputStr, "Process has handlers for: SIGHUP, SIGTERM") {
+		t.Errorf("Picker should list the handled signals, got %q", outputStr)
+	}
+	if !strings.Contains(outputStr, "(handled)") {
+		t.Error("Picker should mark handled signals")
+	}
+	if !strings.Contains(outputStr, "default: stop") {
+		t.Error("Picker should show each signal's default action")
+	}
+}
+
+// TestPromptSignalCustomName tests entering a custom signal by name
+func TestPromptSignalCustomName(t *testing.T) {
+	prompter := NewPrompterWithIO(strings.NewReader(customChoice+"\nWINCH\n"), &bytes.Buffer{})
+
+	signal, err := prompter.PromptSignal()
+	if err != nil {
+		t.Fatalf("PromptSignal() failed: %v", err)
+	}
+	if signal != syscall.SIGWINCH {
+		t.Errorf("Expected SIGWINCH, got %v", signal)
+	}
+}
+
+// TestPromptSignalListsAllSignals tests that every named signal of the platform is in the picker
+func TestPromptSignalListsAllSignals(t *testing.T) {
+	output := &bytes.Buffer{}
+	prompter := NewPrompterWithIO(strings.NewReader("1\n"), output)
+	if _, err := prompter.PromptSignal(); err != nil {
+		t.Fatalf("PromptSignal() failed: %v", err)
+	}
+
+	outputStr := output.String()
+	for _, s := range signalTable {
+		if !strings.Contains(outputStr, s.Name+" ") {
+			t.Errorf("Picker should list %s", s.Name)
+		}
+	}
+	if !strings.Contains(outputStr, "["+customChoice+"] Custom") {
+		t.Errorf("Custom should follow the %d listed signals", len(pickerSignals))
+	}
+	if pickerSignals[0] != syscall.SIGTERM || len(pickerSignals) != len(signalTable) {
+		t.Errorf("pickerSignals = %v, want SIGTERM first and every signal once", pickerSignals)
+	}
+}
+
+func TestConfirmTyped(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"sshd\n", true},
+		{"  sshd \n", true},
+		{"y\n", false},
+		{"\n", false},
+		{"", false}, // EOF
+	}
+
+	for _, tt := range tests {
+		var output bytes.Buffer
+		prompter := NewPrompterWithIO(strings.NewReader(tt.input), &output)
+		if got := prompter.ConfirmTyped("sshd"); got != tt.want {
+			t.Errorf("ConfirmTyped(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if !strings.Contains(output.String(), "Type sshd to confirm") {
+			t.Errorf("Prompt should name the word to type, got %q", output.String())
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+// Restart starts a stopped process again the way it was started: with the same
+// argv, working directory, environment and user
+type Restart struct {
+	Path       string              // Executable to run
+	Argv       []string            // Arguments, including argv[0]
+	Dir        string              // Working directory
+	Env        []string            // Environment (KEY=value)
+	Credential *syscall.Credential // User and groups to run as; nil for the current user
+	name       string              // Command name, for log file names
+}
+
+// NewRestart captures how the process in info was started. It fails when the
+// process could not be started again the same way, so that it is never stopped
+// for nothing.
+func NewRestart(info *model.ProcessInfo) (*Restart, error) {
+	if info.Supervisor != nil {
+		return nil, fmt.Errorf("it is managed by %s, which starts it again by itself", info.Supervisor.Kind)
+	}
+	if len(info.Argv) == 0 {
+		return nil, errors.New("its command line could not be read")
+	}
+	if info.Environ == nil {
+		return nil, errors.New("its environment could not be read")
+	}
+	if info.WorkingDir == "" || strings.HasSuffix(info.WorkingDir, " (deleted)") {
+		return nil, errors.New("its working directory is unknown or was deleted")
+	}
+
+	r := &Restart{Argv: info.Argv, Dir: info.WorkingDir, Env: info.Environ, name: info.Command}
+	path, err := r.executable(info.ExePath)
+	if err != nil {
+		return nil, err
+	}
+	r.Path = path
+
+	if info.UID != os.Geteuid() {
+		if os.Geteuid() != 0 {
+			return nil, fmt.Errorf("it runs as UID %d; starting it as that user needs root", info.UID)
+		}
+		r.Credential = &syscall.Credential{Uid: uint32(info.UID), Gid: uint32(info.GID), Groups: parseGroups(info.Groups)}
+	}
+	return r, nil
+}
+
+// String returns the command line that is started again
+func (r *Restart) String() string {
+	return ShellJoin(r.Argv)
+}
+
+// executable finds the program to run: the process's executable, also when it was
+// rebuilt since it started, or else argv[0] resolved like the shell would
+func (r *Restart) executable(exe string) (string, error) {
+	if exe = strings.TrimSuffix(exe, " (deleted)"); exe != "" && isExecutable(exe) {
+		return exe, nil
+	}
+
+	name := r.Argv[0]
+	if strings.Contains(name, "/") {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(r.Dir, name)
+		}
+		if isExecutable(name) {
+			return name, nil
+		}
+		return "", fmt.Errorf("its executable %s no longer exists", name)
+	}
+	for _, dir := range filepath.SplitList(r.getenv("PATH")) {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(r.Dir, dir)
+		}
+		if path := filepath.Join(dir, name); isExecutable(path) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("its executable %s is not in its PATH", name)
+}
+
+// getenv returns a variable of the process's environment
+func (r *Restart) getenv(key string) string {
+	for _, kv := range r.Env {
+		if v, ok := strings.CutPrefix(kv, key+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// Launched is a process started by Restart
+type Launched struct {
+	PID     int
+	LogFile string // Where its output goes
+	exited  chan struct{}
+	err     error
+}
+
+// Start starts the process detached from whoseport, in a new session with its
+// output appended to a new log file in logDir
+func (r *Restart) Start(logDir string) (*Launched, error) {
+	logFile, err := os.CreateTemp(logDir, fmt.Sprintf("whoseport-%s-*.log", sanitize(r.name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	defer logFile.Close()
+	if r.Credential != nil {
+		_ = logFile.Chown(int(r.Credential.Uid), int(r.Credential.Gid))
+	}
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		return nil, err
+	}
+	defer devNull.Close()
+
+	cmd := &exec.Cmd{
+		Path:        r.Path,
+		Args:        r.Argv,
+		Dir:         r.Dir,
+		Env:         r.Env,
+		Stdin:       devNull,
+		Stdout:      logFile,
+		Stderr:      logFile,
+		SysProcAttr: &syscall.SysProcAttr{Setsid: true, Credential: r.Credential},
+	}
+	if err := cmd.Start(); err != nil {
+		os.Remove(logFile.Name())
+		return nil, fmt.Errorf("failed to start %s: %w", r.Path, err)
+	}
+
+	launched := &Launched{PID: cmd.Process.Pid, LogFile: logFile.Name(), exited: make(chan struct{})}
+	go func() {
+		launched.err = cmd.Wait()
+		close(launched.exited)
+	}()
+	return launched, nil
+}
+
+// Exited reports whether the process has already exited, and how. A process
+// still running when whoseport exits keeps running.
+func (l *Launched) Exited() (bool, error) {
+	select {
+	case <-l.exited:
+		return true, l.err
+	default:
+		return false, nil
+	}
+}
+
+// parseGroups parses the supplementary groups of /proc/[pid]/status
+func parseGroups(groups string) []uint32 {
+	var gids []uint32
+	for _, field := range strings.Fields(groups) {
+		if gid, err := strconv.ParseUint(field, 10, 32); err == nil {
+			gids = append(gids, uint32(gid))
+		}
+	}
+	return gids
+}
+
+// sanitize makes a command name safe to use in a file name
+func sanitize(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == os.PathSeparator || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" {
+		return "process"
+	}
+	return name
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && info.Mode()&0o111 != 0
+}
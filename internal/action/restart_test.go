@@ -0,0 +1,131 @@
+package action
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+// launchInfo describes a process started as "sh -c script" in dir
+func launchInfo(dir, script string) *model.ProcessInfo {
+	return &model.ProcessInfo{
+		ID:         4242,
+		Command:    "sh",
+		Argv:       []string{"sh", "-c", script},
+		Environ:    []string{"PATH=/usr/bin:/bin", "GREETING=hello there"},
+		WorkingDir: dir,
+		UID:        os.Geteuid(),
+	}
+}
+
+func TestNewRestartRefuses(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		modify func(*model.ProcessInfo)
+		want   string
+	}{
+		{"supervised", func(i *model.ProcessInfo) { i.Supervisor = &model.SupervisorInfo{Kind: "pm2"} }, "managed by pm2"},
+		{"no command line", func(i *model.ProcessInfo) { i.Argv = nil }, "command line"},
+		{"no environment", func(i *model.ProcessInfo) { i.Environ = nil }, "environment"},
+		{"deleted directory", func(i *model.ProcessInfo) { i.WorkingDir = dir + " (deleted)" }, "working directory"},
+		{"missing executable", func(i *model.ProcessInfo) { i.Argv = []string{"./not-built-yet"} }, "no longer exists"},
+		{"not in PATH", func(i *model.ProcessInfo) { i.Argv = []string{"whoseport-no-such-command"} }, "not in its PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := launchInfo(dir, "true")
+			tt.modify(info)
+			if _, err := NewRestart(info); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewRestart() = %v, want an error about %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartExecutable(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "bin", "serve")
+	if err := os.MkdirAll(filepath.Dir(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		exe  string
+		argv string
+		path string // PATH of the process
+		want string
+	}{
+		{"executable path", script, "serve", "", script},
+		{"rebuilt executable", script + " (deleted)", "serve", "", script},
+		{"relative argv[0]", "", "./bin/serve", "", script},
+		{"argv[0] in its PATH", "", "serve", "/nonexistent:" + filepath.Join(dir, "bin"), script},
+		{"relative PATH entry", "", "serve", "bin", script},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Restart{Argv: []string{tt.argv}, Dir: dir, Env: []string{"PATH=" + tt.path}}
+			got, err := r.executable(tt.exe)
+			if err != nil || got != tt.want {
+				t.Errorf("executable() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartStart(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	restart, err := NewRestart(launchInfo(dir, `echo "$GREETING from $(pwd -P)"; exit 3`))
+	if err != nil {
+		t.Fatalf("NewRestart() failed: %v", err)
+	}
+	if restart.Credential != nil {
+		t.Error("NewRestart() should keep the current user for its own process")
+	}
+
+	launched, err := restart.Start(t.TempDir())
+	if err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	if launched.PID <= 0 || !strings.HasPrefix(filepath.Base(launched.LogFile), "whoseport-sh-") {
+		t.Errorf("Start() = %+v, want a PID and a log file named after the command", launched)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	exited, exitErr := launched.Exited()
+	for !exited && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		exited, exitErr = launched.Exited()
+	}
+	if !exited || exitErr == nil {
+		t.Fatalf("Exited() = %v, %v; want the exit status 3", exited, exitErr)
+	}
+
+	output, err := os.ReadFile(launched.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "hello there from " + dir + "\n"; string(output) != want {
+		t.Errorf("log = %q, want %q (environment and working directory of the original)", output, want)
+	}
+}
+
+func TestRestartString(t *testing.T) {
+	r := &Restart{Argv: []string{"node", "server.js", "--name", "my app"}}
+	if got, want := r.String(), "node server.js --name 'my app'"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,421 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/procfs"
+)
+
+// cgroupRoot is where the unified (v2) cgroup hierarchy is mounted
+const cgroupRoot = "/sys/fs/cgroup"
+
+// Scope selects which processes a signal reaches
+type Scope int
+
+const (
+	ScopeProcess     Scope = iota // The process holding the port
+	ScopeGroup                    // Its process group (kill(-pgid))
+	ScopeSession                  // Every process of its session
+	ScopeDescendants              // The process and all of its descendants
+	ScopeCgroup                   // Every process of its cgroup (cgroup.kill on v2)
+)
+
+// Scopes lists the scopes in menu order
+var Scopes = []Scope{ScopeProcess, ScopeGroup, ScopeSession, ScopeDescendants, ScopeCgroup}
+
+var scopeNames = map[Scope]string{
+	ScopeProcess:     "pid",
+	ScopeGroup:       "pgroup",
+	ScopeSession:     "session",
+	ScopeDescendants: "descendants",
+	ScopeCgroup:      "cgroup",
+}
+
+var scopeLabels = map[Scope]string{
+	ScopeProcess:     "Process",
+	ScopeGroup:       "Process group",
+	ScopeSession:     "Session",
+	ScopeDescendants: "Descendants",
+	ScopeCgroup:      "Cgroup",
+}
+
+// String returns the name used by --scope, e.g. "pgroup"
+func (s Scope) String() string {
+	return scopeNames[s]
+}
+
+// Label returns the menu label of the scope, e.g. "Process group"
+func (s Scope) Label() string {
+	return scopeLabels[s]
+}
+
+// ParseScope parses a --scope value
+func ParseScope(name string) (Scope, error) {
+	for _, s := range Scopes {
+		if s.String() == strings.ToLower(name) {
+			return s, nil
+		}
+	}
+	names := make([]string, len(Scopes))
+	for i, s := range Scopes {
+		names[i] = s.String()
+	}
+	return 0, fmt.Errorf("unknown scope %q (expected one of %s)", name, strings.Join(names, ", "))
+}
+
+// ProcEntry is a row of the process table
+type ProcEntry struct {
+	PID       int
+	PPid      int
+	PGID      int
+	SID       int       // Session ID (0 when unknown)
+	Command   string    // Short command name
+	Cgroup    string    // cgroup v2 path, e.g. /system.slice/app.service ("" on v1 or macOS)
+	StartedAt time.Time // Zero when unknown (macOS)
+}
+
+// ProcessTable lists the processes of the system
+type ProcessTable interface {
+	List() ([]ProcEntry, error)
+}
+
+// Target is a resolved scope: the exact processes a signal will reach
+type Target struct {
+	Scope     Scope
+	PID       int         // The process holding the port
+	ID        int         // PGID or SID for group and session scopes
+	Cgroup    string      // cgroup path for the cgroup scope
+	Processes []ProcEntry // Processes the signal reaches, the port holder first
+	Excluded  int         // Processes of the scope skipped because they are whoseport or its ancestors
+}
+
+// PIDs returns the PIDs the signal reaches
+func (t *Target) PIDs() []int {
+	pids := make([]int, len(t.Processes))
+	for i, p := range t.Processes {
+		pids[i] = p.PID
+	}
+	return pids
+}
+
+// Describe names the scope and its ID, e.g. "process group 4240"
+func (t *Target) Describe() string {
+	switch t.Scope {
+	case ScopeGroup:
+		return fmt.Sprintf("process group %d", t.ID)
+	case ScopeSession:
+		return fmt.Sprintf("session %d", t.ID)
+	case ScopeDescendants:
+		return fmt.Sprintf("process %d and its descendants", t.PID)
+	case ScopeCgroup:
+		return "cgroup " + t.Cgroup
+	default:
+		return fmt.Sprintf("process %d", t.PID)
+	}
+}
+
+// Preview lists the processes the signal reaches, e.g. "4240 (npm), 4242 (node)",
+// shortened to max entries
+func (t *Target) Preview(max int) string {
+	var parts []string
+	for i, p := range t.Processes {
+		if i == max {
+			parts = append(parts, fmt.Sprintf("+%d more", len(t.Processes)-max))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%d (%s)", p.PID, p.Command))
+	}
+	preview := strings.Join(parts, ", ")
+	if t.Excluded > 0 {
+		preview += fmt.Sprintf("; skipping %d (whoseport and its parents)", t.Excluded)
+	}
+	return preview
+}
+
+// ScopeResolver finds the processes of each scope
+type ScopeResolver struct {
+	table ProcessTable
+	self  int
+}
+
+// NewScopeResolver creates a ScopeResolver backed by the platform process table
+func NewScopeResolver() *ScopeResolver {
+	return NewScopeResolverWithTable(newProcessTable(), os.Getpid())
+}
+
+// NewScopeResolverWithTable creates a ScopeResolver with a custom process table (for testing)
+// self is whoseport's PID: it and its ancestors are never signalled
+func NewScopeResolverWithTable(table ProcessTable, self int) *ScopeResolver {
+	return &ScopeResolver{table: table, self: self}
+}
+
+// Resolve returns the processes a scope reaches from pid
+func (r *ScopeResolver) Resolve(pid int, scope Scope) (*Target, error) {
+	entries, err := r.table.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	return resolveScope(entries, pid, scope, r.self)
+}
+
+// ResolveAll returns the targets of every scope available for pid, in menu order
+func (r *ScopeResolver) ResolveAll(pid int) []*Target {
+	entries, err := r.table.List()
+	if err != nil {
+		return nil
+	}
+	var targets []*Target
+	for _, scope := range Scopes {
+		if target, err := resolveScope(entries, pid, scope, r.self); err == nil {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+func resolveScope(entries []ProcEntry, pid int, scope Scope, self int) (*Target, error) {
+	byPID := make(map[int]ProcEntry, len(entries))
+	for _, e := range entries {
+		byPID[e.PID] = e
+	}
+	root, ok := byPID[pid]
+	if !ok {
+		return nil, fmt.Errorf("process %d does not exist", pid)
+	}
+
+	target := &Target{Scope: scope, PID: pid}
+	var member func(e ProcEntry) bool
+	switch scope {
+	case ScopeProcess:
+		target.Processes = []ProcEntry{root}
+		return target, nil
+	case ScopeGroup:
+		target.ID = root.PGID
+		member = func(e ProcEntry) bool { return e.PGID == root.PGID }
+	case ScopeSession:
+		if root.SID <= 0 {
+			return nil, fmt.Errorf("the session of process %d is unknown", pid)
+		}
+		target.ID = root.SID
+		member = func(e ProcEntry) bool { return e.SID == root.SID }
+	case ScopeDescendants:
+		tree := descendants(entries, pid)
+		member = func(e ProcEntry) bool { return tree[e.PID] }
+	case ScopeCgroup:
+		if root.Cgroup == "" {
+			return nil, fmt.Errorf("the cgroup scope requires cgroup v2")
+		}
+		if root.Cgroup == "/" {
+			return nil, fmt.Errorf("process %d is in the root cgroup", pid)
+		}
+		target.Cgroup = root.Cgroup
+		member = func(e ProcEntry) bool {
+			return e.Cgroup == root.Cgroup || strings.HasPrefix(e.Cgroup, root.Cgroup+"/")
+		}
+	default:
+		return nil, fmt.Errorf("unknown scope %d", scope)
+	}
+
+	protected := ancestors(byPID, self)
+	target.Processes = []ProcEntry{root}
+	for _, e := range entries {
+		if e.PID == pid || !member(e) {
+			continue
+		}
+		if protected[e.PID] {
+			target.Excluded++
+			continue
+		}
+		target.Processes = append(target.Processes, e)
+	}
+	sort.Slice(target.Processes[1:], func(i, j int) bool {
+		return target.Processes[i+1].PID < target.Processes[j+1].PID
+	})
+	return target, nil
+}
+
+// descendants returns pid and every process below it in the process tree
+func descendants(entries []ProcEntry, pid int) map[int]bool {
+	children := make(map[int][]int)
+	for _, e := range entries {
+		children[e.PPid] = append(children[e.PPid], e.PID)
+	}
+	tree := map[int]bool{pid: true}
+	queue := []int{pid}
+	for len(queue) > 0 {
+		for _, child := range children[queue[0]] {
+			if !tree[child] {
+				tree[child] = true
+				queue = append(queue, child)
+			}
+		}
+		queue = queue[1:]
+	}
+	return tree
+}
+
+// ancestors returns pid and its parent chain up to init
+func ancestors(byPID map[int]ProcEntry, pid int) map[int]bool {
+	chain := make(map[int]bool)
+	for pid > 0 && !chain[pid] {
+		chain[pid] = true
+		pid = byPID[pid].PPid
+	}
+	return chain
+}
+
+// ScopedKiller signals every process of a resolved Target
+// Following Open/Closed Principle: another Killer strategy, callers are unchanged
+type ScopedKiller struct {
+	Target     *Target
+	Handle     *Handle // Pins the identity of the port holder, if captured
+	cgroupRoot string
+	members    map[int]*Handle // Pins the identity of the other processes by start time
+	sent       bool            // A signal was delivered; the holder may since have exited
+}
+
+// NewScopedKiller creates a Killer for the processes of target
+func NewScopedKiller(target *Target) *ScopedKiller {
+	return &ScopedKiller{Target: target, cgroupRoot: cgroupRoot, members: make(map[int]*Handle)}
+}
+
+// Kill sends the signal to the target's processes. Process groups are signalled with
+// kill(-pgid) and cgroups killed through cgroup.kill when nothing had to be excluded;
+// otherwise each previewed process is signalled, skipping any whose PID was reused.
+// Once a signal was delivered, a holder that exited meanwhile is not an error, so a
+// second signal still reaches the rest of the scope.
+func (k *ScopedKiller) Kill(pid int, signal syscall.Signal) error {
+	t := k.Target
+	if pid != t.PID {
+		return fmt.Errorf("scope was resolved for process %d, not %d", t.PID, pid)
+	}
+	// Checks that the port holder still exists and, with a handle, is the same process
+	root := NewKillerWithHandle(k.Handle)
+	holderGone := false
+	if err := root.Kill(pid, syscall.Signal(0)); err != nil {
+		if !k.sent {
+			return err
+		}
+		holderGone = true
+	}
+
+	if t.Scope == ScopeProcess {
+		if holderGone {
+			return nil
+		}
+		return root.Kill(pid, signal)
+	}
+	switch op := t.operation(signal, k.cgroupRoot); {
+	case op.PGID != 0:
+		if err := syscall.Kill(-op.PGID, signal); err != nil && !(k.sent && errors.Is(err, syscall.ESRCH)) {
+			return fmt.Errorf("failed to send signal %v to process group %d: %w", signal, op.PGID, err)
+		}
+		k.sent = true
+		return nil
+	case op.Kind == OpWrite:
+		// cgroup.kill (Linux 5.14+) kills the whole subtree, including processes forked meanwhile
+		if err := os.WriteFile(op.Path, []byte("1"), 0); err == nil {
+			k.sent = true
+			return nil
+		}
+	}
+
+	var errs []error
+	for _, p := range t.Processes {
+		var err error
+		switch {
+		case p.PID != pid:
+			err = k.member(p).Signal(signal)
+		case !holderGone:
+			err = root.Kill(pid, signal)
+		}
+		// A process that exited, or whose PID now belongs to another process, is skipped
+		if err != nil && !errors.Is(err, syscall.ESRCH) && !errors.Is(err, ErrProcessChanged) {
+			errs = append(errs, fmt.Errorf("PID %d: %w", p.PID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send signal %v to %s: %w", signal, t.Describe(), errors.Join(errs...))
+	}
+	k.sent = true
+	return nil
+}
+
+// Survivors returns the processes of the target that are still running, the port
+// holder first. Processes whose PID was reused by another process are not counted.
+func (k *ScopedKiller) Survivors() []int {
+	var pids []int
+	for _, p := range k.Target.Processes {
+		var err error
+		if p.PID == k.Target.PID {
+			if k.Handle != nil {
+				err = k.Handle.Verify()
+			}
+		} else {
+			err = k.member(p).Verify()
+		}
+		if err == nil && processAlive(p.PID) {
+			pids = append(pids, p.PID)
+		}
+	}
+	return pids
+}
+
+// member returns the handle of a process of the scope other than the port holder.
+// It pins the start time read with the process table, or on macOS, where the table
+// has none, the one read when the process is first signalled.
+func (k *ScopedKiller) member(p ProcEntry) *Handle {
+	if h, ok := k.members[p.PID]; ok {
+		return h
+	}
+	h := &Handle{PID: p.PID, StartedAt: p.StartedAt, pidfd: -1}
+	if h.StartedAt.IsZero() {
+		h.StartedAt, _ = procfs.StartedAt(p.PID)
+	}
+	k.members[p.PID] = h
+	return h
+}
+
+// parseStatEntry reads the PID, command, parent, process group and session from a
+// /proc/<pid>/stat line. The command is in parentheses and may contain spaces.
+func parseStatEntry(stat string) (ProcEntry, error) {
+	open := strings.IndexByte(stat, '(')
+	end := strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return ProcEntry{}, fmt.Errorf("malformed stat line")
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 4 {
+		return ProcEntry{}, fmt.Errorf("malformed stat line")
+	}
+
+	var e ProcEntry
+	var err error
+	if e.PID, err = strconv.Atoi(strings.TrimSpace(stat[:open])); err != nil {
+		return ProcEntry{}, fmt.Errorf("malformed stat line: %w", err)
+	}
+	e.Command = stat[open+1 : end]
+	// fields: state, ppid, pgrp, session
+	e.PPid, _ = strconv.Atoi(fields[1])
+	e.PGID, _ = strconv.Atoi(fields[2])
+	e.SID, _ = strconv.Atoi(fields[3])
+	return e, nil
+}
+
+// parseCgroupV2 returns the unified hierarchy path from /proc/<pid>/cgroup, or ""
+// when the process is not on cgroup v2
+func parseCgroupV2(data string) string {
+	for _, line := range strings.Split(data, "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return strings.TrimSpace(path)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,266 @@
+package action
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/procfs"
+)
+
+// fakeTable is a fixed process table
+type fakeTable []ProcEntry
+
+func (f fakeTable) List() ([]ProcEntry, error) {
+	return f, nil
+}
+
+// testTable models a terminal session running whoseport (900) next to an npm dev
+// server (200) with two node workers, one of which forked a helper
+var testTable = fakeTable{
+	{PID: 1, PPid: 0, PGID: 1, SID: 1, Command: "systemd", Cgroup: "/init.scope"},
+	{PID: 100, PPid: 1, PGID: 100, SID: 100, Command: "bash", Cgroup: "/user.slice/session-3.scope"},
+	{PID: 200, PPid: 100, PGID: 200, SID: 100, Command: "npm", Cgroup: "/user.slice/session-3.scope/dev"},
+	{PID: 201, PPid: 200, PGID: 200, SID: 100, Command: "node", Cgroup: "/user.slice/session-3.scope/dev"},
+	{PID: 202, PPid: 200, PGID: 200, SID: 100, Command: "node", Cgroup: "/user.slice/session-3.scope/dev"},
+	{PID: 203, PPid: 202, PGID: 203, SID: 100, Command: "esbuild", Cgroup: "/user.slice/session-3.scope/dev/esbuild"},
+	{PID: 900, PPid: 100, PGID: 900, SID: 100, Command: "whoseport", Cgroup: "/user.slice/session-3.scope"},
+}
+
+func TestResolveScope(t *testing.T) {
+	tests := []struct {
+		scope    Scope
+		pid      int
+		pids     []int
+		excluded int
+		describe string
+	}{
+		{ScopeProcess, 201, []int{201}, 0, "process 201"},
+		{ScopeGroup, 201, []int{201, 200, 202}, 0, "process group 200"},
+		{ScopeSession, 201, []int{201, 200, 202, 203}, 2, "session 100"}, // bash and whoseport are skipped
+		{ScopeDescendants, 200, []int{200, 201, 202, 203}, 0, "process 200 and its descendants"},
+		{ScopeDescendants, 202, []int{202, 203}, 0, "process 202 and its descendants"},
+		{ScopeCgroup, 201, []int{201, 200, 202, 203}, 0, "cgroup /user.slice/session-3.scope/dev"},
+	}
+
+	resolver := NewScopeResolverWithTable(testTable, 900)
+	for _, tt := range tests {
+		t.Run(tt.scope.String(), func(t *testing.T) {
+			target, err := resolver.Resolve(tt.pid, tt.scope)
+			if err != nil {
+				t.Fatalf("Resolve() failed: %v", err)
+			}
+			if !reflect.DeepEqual(target.PIDs(), tt.pids) {
+				t.Errorf("PIDs() = %v, want %v", target.PIDs(), tt.pids)
+			}
+			if target.Excluded != tt.excluded {
+				t.Errorf("Excluded = %d, want %d", target.Excluded, tt.excluded)
+			}
+			if got := target.Describe(); got != tt.describe {
+				t.Errorf("Describe() = %q, want %q", got, tt.describe)
+			}
+		})
+	}
+}
+
+func TestResolveScopeErrors(t *testing.T) {
+	table := fakeTable{
+		{PID: 1, PPid: 0, PGID: 1, Command: "launchd"},
+		{PID: 50, PPid: 1, PGID: 50, Command: "node", Cgroup: "/"},
+	}
+	resolver := NewScopeResolverWithTable(table, 1)
+
+	tests := []struct {
+		pid   int
+		scope Scope
+	}{
+		{999, ScopeProcess}, // no such process
+		{50, ScopeSession},  // session unknown (macOS)
+		{50, ScopeCgroup},   // root cgroup
+		{1, ScopeCgroup},    // no cgroup v2
+	}
+
+	for _, tt := range tests {
+		if _, err := resolver.Resolve(tt.pid, tt.scope); err == nil {
+			t.Errorf("Resolve(%d, %v) should fail", tt.pid, tt.scope)
+		}
+	}
+}
+
+func TestResolveAllSkipsUnavailableScopes(t *testing.T) {
+	table := fakeTable{
+		{PID: 1, PPid: 0, PGID: 1, Command: "launchd"},
+		{PID: 50, PPid: 1, PGID: 50, Command: "node"},
+	}
+
+	var scopes []Scope
+	for _, target := range NewScopeResolverWithTable(table, 1).ResolveAll(50) {
+		scopes = append(scopes, target.Scope)
+	}
+	if expected := []Scope{ScopeProcess, ScopeGroup, ScopeDescendants}; !reflect.DeepEqual(scopes, expected) {
+		t.Errorf("ResolveAll() scopes = %v, want %v", scopes, expected)
+	}
+}
+
+func TestTargetPreview(t *testing.T) {
+	target, err := NewScopeResolverWithTable(testTable, 900).Resolve(201, ScopeSession)
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+
+	if got, want := target.Preview(2), "201 (node), 200 (npm), +2 more; skipping 2 (whoseport and its parents)"; got != want {
+		t.Errorf("Preview() = %q, want %q", got, want)
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	for _, scope := range Scopes {
+		got, err := ParseScope(strings.ToUpper(scope.String()))
+		if err != nil || got != scope {
+			t.Errorf("ParseScope(%q) = %v, %v", scope.String(), got, err)
+		}
+	}
+	if _, err := ParseScope("cluster"); err == nil {
+		t.Error("ParseScope() should reject unknown scopes")
+	}
+}
+
+func TestParseStatEntry(t *testing.T) {
+	entry, err := parseStatEntry("4242 (my (odd) server) S 4240 4240 4100 34816 4240 4194560 5042 0 0 0")
+	if err != nil {
+		t.Fatalf("parseStatEntry() failed: %v", err)
+	}
+	expected := ProcEntry{PID: 4242, PPid: 4240, PGID: 4240, SID: 4100, Command: "my (odd) server"}
+	if entry != expected {
+		t.Errorf("parseStatEntry() = %+v, want %+v", entry, expected)
+	}
+
+	if _, err := parseStatEntry("4242 (node"); err == nil {
+		t.Error("parseStatEntry() should reject truncated lines")
+	}
+}
+
+func TestParseCgroupV2(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"0::/user.slice/user-1000.slice/session-3.scope\n", "/user.slice/user-1000.slice/session-3.scope"},
+		{"12:pids:/user.slice\n1:name=systemd:/user.slice\n0::/init.scope\n", "/init.scope"},
+		{"12:pids:/docker/abc\n11:memory:/docker/abc\n", ""}, // cgroup v1 only
+	}
+
+	for _, tt := range tests {
+		if got := parseCgroupV2(tt.data); got != tt.want {
+			t.Errorf("parseCgroupV2(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+// startGroup starts a shell in its own process group that forks a sleeping child,
+// and returns the shell's PID and its resolved process group
+func startGroup(t *testing.T) (int, *Target) {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", "sleep 30 & wait")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start test process: %v", err)
+	}
+	pid := cmd.Process.Pid
+	t.Cleanup(func() { syscall.Kill(-pid, syscall.SIGKILL) })
+	go cmd.Wait()
+
+	// Wait for the shell to fork its child
+	resolver := NewScopeResolver()
+	for i := 0; i < 50; i++ {
+		if target, err := resolver.Resolve(pid, ScopeGroup); err == nil && len(target.Processes) == 2 {
+			return pid, target
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Skip("process table does not show the test process group")
+	return 0, nil
+}
+
+func TestScopedKillerGroup(t *testing.T) {
+	pid, target := startGroup(t)
+	child := target.Processes[1].PID
+
+	if err := NewScopedKiller(target).Kill(pid, syscall.SIGKILL); err != nil {
+		t.Fatalf("Kill() failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for processAlive(child) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if processAlive(child) {
+		t.Error("The child in the process group should have been killed")
+	}
+}
+
+func TestScopedKillerRejectsOtherPID(t *testing.T) {
+	target := &Target{Scope: ScopeProcess, PID: 4242}
+	if err := NewScopedKiller(target).Kill(4243, syscall.SIGTERM); err == nil {
+		t.Error("Kill() should refuse a PID the scope was not resolved for")
+	}
+}
+
+func TestScopedKillerCgroupKill(t *testing.T) {
+	pid := startProcess(t, "sleep", "30")
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "app.service"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	killFile := filepath.Join(root, "app.service", "cgroup.kill")
+	if err := os.WriteFile(killFile, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	target := &Target{Scope: ScopeCgroup, PID: pid, Cgroup: "/app.service", Processes: []ProcEntry{{PID: pid}}}
+	killer := NewScopedKiller(target)
+	killer.cgroupRoot = root
+
+	if err := killer.Kill(pid, syscall.SIGKILL); err != nil {
+		t.Fatalf("Kill() failed: %v", err)
+	}
+	if data, _ := os.ReadFile(killFile); string(data) != "1" {
+		t.Errorf("cgroup.kill should have been written, got %q", data)
+	}
+}
+
+// TestScopedKillerSkipsReusedPID tests that a process of the preview whose PID now
+// belongs to a different process is not signalled
+func TestScopedKillerSkipsReusedPID(t *testing.T) {
+	holder := startProcess(t, "sleep", "30")
+	other := startProcess(t, "sleep", "30")
+	started, err := procfs.StartedAt(other)
+	if err != nil {
+		t.Skipf("start time not available: %v", err)
+	}
+
+	// The preview saw a process that started an hour before the one now at its PID
+	target := &Target{Scope: ScopeDescendants, PID: holder, Processes: []ProcEntry{
+		{PID: holder},
+		{PID: other, StartedAt: started.Add(-time.Hour)},
+	}}
+	if err := NewScopedKiller(target).Kill(holder, syscall.SIGKILL); err != nil {
+		t.Fatalf("Kill() failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for processAlive(holder) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if processAlive(holder) {
+		t.Error("The port holder should have been killed")
+	}
+	if !processAlive(other) {
+		t.Error("The process that reused the PID should not have been signalled")
+	}
+}
@@ -0,0 +1,285 @@
+package action
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Default actions of signals, as listed in signal(7)
+const (
+	DefaultTerminate = "terminate"
+	DefaultCore      = "core dump"
+	DefaultIgnore    = "ignore"
+	DefaultStop      = "stop"
+	DefaultContinue  = "continue"
+)
+
+// SignalInfo describes a signal: its name, what it is for and what it does to a
+// process that has no handler for it
+type SignalInfo struct {
+	Signal      syscall.Signal
+	Name        string
+	Description string
+	Default     string
+}
+
+// signalTable lists the standard signals of every platform; platformSignals adds
+// the ones specific to the current OS
+var signalTable = append([]SignalInfo{
+	{syscall.SIGHUP, "SIGHUP", "Hangup (reload config)", DefaultTerminate},
+	{syscall.SIGINT, "SIGINT", "Interrupt (Ctrl+C)", DefaultTerminate},
+	{syscall.SIGQUIT, "SIGQUIT", "Quit with core dump (Ctrl+\\)", DefaultCore},
+	{syscall.SIGILL, "SIGILL", "Illegal instruction", DefaultCore},
+	{syscall.SIGTRAP, "SIGTRAP", "Trace/breakpoint trap", DefaultCore},
+	{syscall.SIGABRT, "SIGABRT", "Abort", DefaultCore},
+	{syscall.SIGBUS, "SIGBUS", "Bus error", DefaultCore},
+	{syscall.SIGFPE, "SIGFPE", "Floating-point exception", DefaultCore},
+	{syscall.SIGKILL, "SIGKILL", "Force kill (cannot be caught)", DefaultTerminate},
+	{syscall.SIGUSR1, "SIGUSR1", "User-defined signal 1", DefaultTerminate},
+	{syscall.SIGSEGV, "SIGSEGV", "Segmentation fault", DefaultCore},
+	{syscall.SIGUSR2, "SIGUSR2", "User-defined signal 2", DefaultTerminate},
+	{syscall.SIGPIPE, "SIGPIPE", "Broken pipe", DefaultTerminate},
+	{syscall.SIGALRM, "SIGALRM", "Timer expired", DefaultTerminate},
+	{syscall.SIGTERM, "SIGTERM", "Graceful termination", DefaultTerminate},
+	{syscall.SIGCHLD, "SIGCHLD", "Child stopped or exited", DefaultIgnore},
+	{syscall.SIGCONT, "SIGCONT", "Resume a stopped process", DefaultContinue},
+	{syscall.SIGSTOP, "SIGSTOP", "Pause the process (cannot be caught)", DefaultStop},
+	{syscall.SIGTSTP, "SIGTSTP", "Terminal stop (Ctrl+Z)", DefaultStop},
+	{syscall.SIGTTIN, "SIGTTIN", "Background read from terminal", DefaultStop},
+	{syscall.SIGTTOU, "SIGTTOU", "Background write to terminal", DefaultStop},
+	{syscall.SIGURG, "SIGURG", "Urgent socket data", DefaultIgnore},
+	{syscall.SIGXCPU, "SIGXCPU", "CPU time limit exceeded", DefaultCore},
+	{syscall.SIGXFSZ, "SIGXFSZ", "File size limit exceeded", DefaultCore},
+	{syscall.SIGVTALRM, "SIGVTALRM", "Virtual timer expired", DefaultTerminate},
+	{syscall.SIGPROF, "SIGPROF", "Profiling timer expired", DefaultTerminate},
+	{syscall.SIGWINCH, "SIGWINCH", "Window resized", DefaultIgnore},
+	{syscall.SIGIO, "SIGIO", "I/O possible", DefaultTerminate},
+	{syscall.SIGSYS, "SIGSYS", "Bad system call", DefaultCore},
+}, platformSignals...)
+
+// LookupSignal returns the description of a signal, including realtime signals
+func LookupSignal(signal syscall.Signal) (SignalInfo, bool) {
+	for _, s := range signalTable {
+		if s.Signal == signal {
+			return s, true
+		}
+	}
+	if rtMin > 0 && signal >= rtMin && signal <= rtMax {
+		return SignalInfo{signal, SignalName(signal), "Realtime signal (application-defined)", DefaultTerminate}, true
+	}
+	return SignalInfo{}, false
+}
+
+// SignalName returns the conventional name of a signal, e.g. "SIGTERM" or "SIGRTMIN+3"
+func SignalName(signal syscall.Signal) string {
+	for _, s := range signalTable {
+		if s.Signal == signal {
+			return s.Name
+		}
+	}
+	if rtMin > 0 && signal >= rtMin && signal <= rtMax {
+		switch n := int(signal - rtMin); {
+		case signal == rtMax:
+			return "SIGRTMAX"
+		case n == 0:
+			return "SIGRTMIN"
+		case signal > rtMin+(rtMax-rtMin)/2:
+			return fmt.Sprintf("SIGRTMAX-%d", int(rtMax-signal))
+		default:
+			return fmt.Sprintf("SIGRTMIN+%d", n)
+		}
+	}
+	return fmt.Sprintf("signal %d", int(signal))
+}
+
+// ParseSignal parses a signal given by name or number: "HUP", "SIGUSR1", "15",
+// "RTMIN+3" or "SIGRTMAX-1". Names are case-insensitive.
+func ParseSignal(value string) (syscall.Signal, error) {
+	name := strings.ToUpper(strings.TrimSpace(value))
+	if name == "" {
+		return 0, fmt.Errorf("empty signal")
+	}
+
+	if n, err := strconv.Atoi(name); err == nil {
+		if n < 1 || n > maxSignal {
+			return 0, fmt.Errorf("signal number %d out of range (1-%d)", n, maxSignal)
+		}
+		return syscall.Signal(n), nil
+	}
+
+	name = strings.TrimPrefix(name, "SIG")
+	for _, s := range signalTable {
+		if strings.TrimPrefix(s.Name, "SIG") == name {
+			return s.Signal, nil
+		}
+	}
+
+	if strings.HasPrefix(name, "RTMIN") || strings.HasPrefix(name, "RTMAX") {
+		return parseRealtime(name)
+	}
+	return 0, fmt.Errorf("unknown signal %q", value)
+}
+
+// parseRealtime parses RTMIN, RTMIN+n, RTMAX and RTMAX-n
+func parseRealtime(name string) (syscall.Signal, error) {
+	if rtMin == 0 {
+		return 0, fmt.Errorf("realtime signals are not supported on this platform")
+	}
+
+	base, offset, sign := rtMin, name[len("RTMIN"):], 1
+	if strings.HasPrefix(name, "RTMAX") {
+		base, offset, sign = rtMax, name[len("RTMAX"):], -1
+	}
+
+	n := 0
+	if offset != "" {
+		if (sign > 0 && offset[0] != '+') || (sign < 0 && offset[0] != '-') {
+			return 0, fmt.Errorf("invalid realtime signal %q (use RTMIN+n or RTMAX-n)", name)
+		}
+		var err error
+		if n, err = strconv.Atoi(offset[1:]); err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid realtime signal %q (use RTMIN+n or RTMAX-n)", name)
+		}
+	}
+
+	signal := base + syscall.Signal(sign*n)
+	if signal < rtMin || signal > rtMax {
+		return 0, fmt.Errorf("realtime signal %q out of range (RTMIN+0 to RTMIN+%d)", name, int(rtMax-rtMin))
+	}
+	return signal, nil
+}
+
+// ExpectsExit reports whether sending the signal should end the process, so the
+// port is expected to be released. Termination signals are handled by shutting
+// down; other signals end the process only through their default action.
+func ExpectsExit(signal syscall.Signal, masks *SignalMasks) bool {
+	switch signal {
+	case syscall.SIGKILL, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT:
+		return true
+	}
+	info, ok := LookupSignal(signal)
+	if !ok || (info.Default != DefaultTerminate && info.Default != DefaultCore) {
+		return false
+	}
+	if masks == nil {
+		// Signals such as SIGHUP and SIGUSR1 usually ask a service to reload
+		return signal != syscall.SIGHUP && signal != syscall.SIGUSR1 && signal != syscall.SIGUSR2
+	}
+	return !masks.Caught(signal) && !masks.Ignored(signal)
+}
+
+// SignalMasks are the signal dispositions of a process, from /proc/<pid>/status
+type SignalMasks struct {
+	caught  sigset // SigCgt: signals with a handler
+	ignored sigset // SigIgn: signals set to SIG_IGN
+	blocked sigset // SigBlk: signals blocked by the main thread
+}
+
+// sigset is a signal mask: bit n-1 is signal n. The second word holds signals
+// 65-128, which only mips has.
+type sigset [2]uint64
+
+// Caught reports whether the process has a handler for the signal
+func (m *SignalMasks) Caught(signal syscall.Signal) bool {
+	return maskHas(m.caught, signal)
+}
+
+// Ignored reports whether the process ignores the signal
+func (m *SignalMasks) Ignored(signal syscall.Signal) bool {
+	return maskHas(m.ignored, signal)
+}
+
+// Blocked reports whether the process blocks the signal
+func (m *SignalMasks) Blocked(signal syscall.Signal) bool {
+	return maskHas(m.blocked, signal)
+}
+
+// Handled lists the signals the process has handlers for
+func (m *SignalMasks) Handled() []syscall.Signal {
+	return maskSignals(m.caught)
+}
+
+// Disposition describes how the process treats the signal, e.g. "handled", or "" for the default
+func (m *SignalMasks) Disposition(signal syscall.Signal) string {
+	var parts []string
+	if m.Caught(signal) {
+		parts = append(parts, "handled")
+	}
+	if m.Ignored(signal) {
+		parts = append(parts, "ignored")
+	}
+	if m.Blocked(signal) {
+		parts = append(parts, "blocked")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseSignalMasks reads SigCgt, SigIgn and SigBlk from the contents of /proc/<pid>/status
+func parseSignalMasks(status string) (*SignalMasks, error) {
+	masks := &SignalMasks{}
+	found := 0
+	for _, line := range strings.Split(status, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		var target *sigset
+		switch key {
+		case "SigCgt":
+			target = &masks.caught
+		case "SigIgn":
+			target = &masks.ignored
+		case "SigBlk":
+			target = &masks.blocked
+		default:
+			continue
+		}
+		mask, err := parseSigset(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s mask: %w", key, err)
+		}
+		*target = mask
+		found++
+	}
+	if found == 0 {
+		return nil, fmt.Errorf("no signal masks in process status")
+	}
+	return masks, nil
+}
+
+// parseSigset parses a mask as printed in /proc/<pid>/status: 16 hex digits, or
+// 32 on mips
+func parseSigset(hex string) (sigset, error) {
+	var mask sigset
+	if hex == "" || len(hex) > 32 {
+		return mask, fmt.Errorf("%q is not a signal mask", hex)
+	}
+	for i := 0; hex != ""; i++ {
+		cut := max(len(hex)-16, 0)
+		word, err := strconv.ParseUint(hex[cut:], 16, 64)
+		if err != nil {
+			return mask, err
+		}
+		mask[i], hex = word, hex[:cut]
+	}
+	return mask, nil
+}
+
+// maskHas reports whether signal n is set: bit n-1 of the mask
+func maskHas(mask sigset, signal syscall.Signal) bool {
+	n := int(signal) - 1
+	return n >= 0 && n < 128 && mask[n/64]&(1<<uint(n%64)) != 0
+}
+
+// maskSignals lists the signals set in a mask, in numeric order
+func maskSignals(mask sigset) []syscall.Signal {
+	var signals []syscall.Signal
+	for n := 1; n <= maxSignal; n++ {
+		if maskHas(mask, syscall.Signal(n)) {
+			signals = append(signals, syscall.Signal(n))
+		}
+	}
+	return signals
+}
@@ -0,0 +1,27 @@
+//go:build darwin
+
+package action
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// macOS has no realtime signals
+const (
+	rtMin     syscall.Signal = 0
+	rtMax     syscall.Signal = 0
+	maxSignal                = 31
+)
+
+// platformSignals are the macOS-specific standard signals
+var platformSignals = []SignalInfo{
+	{syscall.SIGEMT, "SIGEMT", "Emulator trap", DefaultCore},
+	{syscall.SIGINFO, "SIGINFO", "Status request (Ctrl+T)", DefaultIgnore},
+}
+
+// ReadSignalMasks is not supported on macOS: signal dispositions are not exposed
+// to other processes
+func ReadSignalMasks(pid int) (*SignalMasks, error) {
+	return nil, fmt.Errorf("signal dispositions are not available on macOS")
+}
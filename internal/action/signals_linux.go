@@ -0,0 +1,23 @@
+//go:build linux
+
+package action
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// rtMin is SIGRTMIN as seen by applications: glibc reserves 32 and 33 for its
+// threading implementation. SIGRTMAX depends on the architecture.
+const rtMin syscall.Signal = 34
+
+// ReadSignalMasks reads the signals a process handles, ignores and blocks
+// from SigCgt, SigIgn and SigBlk in /proc/<pid>/status
+func ReadSignalMasks(pid int) (*SignalMasks, error) {
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil, err
+	}
+	return parseSignalMasks(string(status))
+}
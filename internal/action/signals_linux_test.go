@@ -0,0 +1,60 @@
+//go:build linux
+
+package action
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestParseRealtimeSignal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected syscall.Signal
+		name     string
+		wantErr  bool
+	}{
+		{"RTMIN", 34, "SIGRTMIN", false},
+		{"RTMIN+3", 37, "SIGRTMIN+3", false},
+		{"sigrtmin+15", 49, "SIGRTMIN+15", false},
+		{"RTMAX-14", rtMax - 14, "SIGRTMAX-14", false},
+		{"RTMAX", rtMax, "SIGRTMAX", false},
+		{"40", 40, "SIGRTMIN+6", false},
+		{fmt.Sprintf("RTMIN+%d", rtMax-rtMin+1), 0, "", true},
+		{"RTMIN-1", 0, "", true},
+		{"RTMAX+1", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSignal(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSignal(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseSignal(%q) = %d, want %d", tt.input, got, tt.expected)
+		}
+		if name := SignalName(got); name != tt.name {
+			t.Errorf("SignalName(%d) = %q, want %q", got, name, tt.name)
+		}
+	}
+}
+
+func TestReadSignalMasks(t *testing.T) {
+	// The Go runtime installs handlers for most signals, including SIGURG for preemption
+	masks, err := ReadSignalMasks(os.Getpid())
+	if err != nil {
+		t.Fatalf("ReadSignalMasks() failed: %v", err)
+	}
+	if !masks.Caught(syscall.SIGURG) {
+		t.Error("The test process should have a SIGURG handler")
+	}
+	if masks.Caught(syscall.SIGKILL) {
+		t.Error("SIGKILL cannot be caught")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build linux && !mips && !mipsle && !mips64 && !mips64le
+
+package action
+
+import "syscall"
+
+// Linux has 64 signals on every architecture but mips
+const (
+	rtMax     syscall.Signal = 64
+	maxSignal                = 64
+)
+
+// platformSignals are the Linux-specific standard signals
+var platformSignals = []SignalInfo{
+	{syscall.SIGSTKFLT, "SIGSTKFLT", "Coprocessor stack fault", DefaultTerminate},
+	{syscall.SIGPWR, "SIGPWR", "Power failure", DefaultTerminate},
+}
@@ -0,0 +1,19 @@
+//go:build linux && (mips || mipsle || mips64 || mips64le)
+
+package action
+
+import "syscall"
+
+// mips has 128 signals; glibc makes SIGRTMAX 127, since 128 cannot be reported
+// in a wait status
+const (
+	rtMax     syscall.Signal = 127
+	maxSignal                = 127
+)
+
+// platformSignals are the Linux-specific standard signals on mips, which has
+// SIGEMT instead of SIGSTKFLT
+var platformSignals = []SignalInfo{
+	{syscall.SIGEMT, "SIGEMT", "Emulator trap", DefaultCore},
+	{syscall.SIGPWR, "SIGPWR", "Power failure", DefaultTerminate},
+}
@@ -0,0 +1,124 @@
+package action
+
+import (
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestParseSignal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected syscall.Signal
+		wantErr  bool
+	}{
+		{"HUP", syscall.SIGHUP, false},
+		{"sighup", syscall.SIGHUP, false},
+		{"SIGUSR1", syscall.SIGUSR1, false},
+		{" term ", syscall.SIGTERM, false},
+		{"15", syscall.Signal(15), false},
+		{"9", syscall.SIGKILL, false},
+		{"0", 0, true},
+		{"-1", 0, true},
+		{"99", 0, true},
+		{"", 0, true},
+		{"BOGUS", 0, true},
+		{"RTMIN+x", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSignal(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSignal(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseSignal(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestSignalNameRoundTrip(t *testing.T) {
+	for _, s := range signalTable {
+		parsed, err := ParseSignal(SignalName(s.Signal))
+		if err != nil || parsed != s.Signal {
+			t.Errorf("ParseSignal(SignalName(%d)) = %v, %v", int(s.Signal), parsed, err)
+		}
+	}
+}
+
+func TestParseSignalMasks(t *testing.T) {
+	// A service handling SIGHUP (1), SIGINT (2), SIGUSR1 (10) and SIGTERM (15),
+	// ignoring SIGPIPE (13) and blocking nothing
+	status := "Name:\tapi\nSigQ:\t0/63432\nSigPnd:\t0000000000000000\nShdPnd:\t0000000000000000\n" +
+		"SigBlk:\t0000000000000000\nSigIgn:\t0000000000001000\nSigCgt:\t0000000000004203\n"
+
+	masks, err := parseSignalMasks(status)
+	if err != nil {
+		t.Fatalf("parseSignalMasks() failed: %v", err)
+	}
+
+	expected := []syscall.Signal{1, 2, 10, 15}
+	if got := masks.Handled(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Handled() = %v, want %v", got, expected)
+	}
+	if !masks.Ignored(13) || masks.Ignored(15) {
+		t.Error("Only signal 13 should be ignored")
+	}
+	if masks.Blocked(15) {
+		t.Error("No signal should be blocked")
+	}
+	if got := masks.Disposition(15); got != "handled" {
+		t.Errorf("Disposition(15) = %q, want handled", got)
+	}
+	if got := masks.Disposition(syscall.SIGKILL); got != "" {
+		t.Errorf("Disposition(SIGKILL) = %q, want empty", got)
+	}
+
+	if _, err := parseSignalMasks("Name:\tapi\n"); err == nil {
+		t.Error("parseSignalMasks() should fail without masks")
+	}
+
+	// mips has 128 signals, printed as 32 hex digits
+	wide, err := parseSignalMasks("SigCgt:\t40000000000000000000000000004000\n")
+	if err != nil {
+		t.Fatalf("parseSignalMasks() failed on a 128-signal mask: %v", err)
+	}
+	if !wide.Caught(15) || !wide.Caught(127) || wide.Caught(64) {
+		t.Errorf("caught = %x, want signals 15 and 127", wide.caught)
+	}
+	if _, err := parseSignalMasks("SigCgt:\t1" + strings.Repeat("0", 32) + "\n"); err == nil {
+		t.Error("parseSignalMasks() should reject a mask wider than 128 signals")
+	}
+	if _, err := parseSignalMasks("SigCgt:\tzz\n"); err == nil {
+		t.Error("parseSignalMasks() should reject invalid masks")
+	}
+}
+
+func TestExpectsExit(t *testing.T) {
+	reloads := &SignalMasks{caught: sigset{1 << (uint(syscall.SIGHUP) - 1)}}
+	plain := &SignalMasks{}
+
+	tests := []struct {
+		name     string
+		signal   syscall.Signal
+		masks    *SignalMasks
+		expected bool
+	}{
+		{"SIGKILL", syscall.SIGKILL, nil, true},
+		{"handled SIGTERM", syscall.SIGTERM, &SignalMasks{caught: sigset{1 << (uint(syscall.SIGTERM) - 1)}}, true},
+		{"handled SIGHUP", syscall.SIGHUP, reloads, false},
+		{"unhandled SIGHUP", syscall.SIGHUP, plain, true},
+		{"SIGHUP without masks", syscall.SIGHUP, nil, false},
+		{"SIGSTOP", syscall.SIGSTOP, nil, false},
+		{"SIGWINCH", syscall.SIGWINCH, plain, false},
+		{"ignored SIGPIPE", syscall.SIGPIPE, &SignalMasks{ignored: sigset{1 << (uint(syscall.SIGPIPE) - 1)}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := ExpectsExit(tt.signal, tt.masks); got != tt.expected {
+			t.Errorf("ExpectsExit(%s) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+//go:build darwin
+
+package action
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// psTable lists processes using the ps command.
+// Session IDs and cgroups are not available, so only the process, process group
+// and descendant scopes can be resolved.
+type psTable struct{}
+
+func newProcessTable() ProcessTable {
+	return &psTable{}
+}
+
+// List reads the PID, parent, process group and command of every process.
+func (t *psTable) List() ([]ProcEntry, error) {
+	output, err := exec.Command("ps", "-axo", "pid=,ppid=,pgid=,comm=").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ProcEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		var e ProcEntry
+		if e.PID, err = strconv.Atoi(fields[0]); err != nil {
+			continue
+		}
+		e.PPid, _ = strconv.Atoi(fields[1])
+		e.PGID, _ = strconv.Atoi(fields[2])
+		e.Command = strings.Join(fields[3:], " ")
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
@@ -0,0 +1,49 @@
+//go:build linux
+
+package action
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bluehoodie/whoseport/internal/procfs"
+)
+
+// procTable lists processes from the /proc filesystem.
+type procTable struct{}
+
+func newProcessTable() ProcessTable {
+	return &procTable{}
+}
+
+// List reads the stat, start time and cgroup of every process. Processes that exit
+// while the table is read are skipped.
+func (t *procTable) List() ([]ProcEntry, error) {
+	dirs, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ProcEntry
+	for _, dir := range dirs {
+		pid, err := strconv.Atoi(dir.Name())
+		if err != nil {
+			continue
+		}
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		if err != nil {
+			continue
+		}
+		entry, err := parseStatEntry(string(stat))
+		if err != nil {
+			continue
+		}
+		entry.StartedAt, _ = procfs.StartedAt(pid)
+		if cgroup, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid)); err == nil {
+			entry.Cgroup = parseCgroupV2(string(cgroup))
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
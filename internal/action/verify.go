@@ -0,0 +1,127 @@
+package action
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+	"github.com/bluehoodie/whoseport/internal/process"
+	"github.com/bluehoodie/whoseport/internal/procfs"
+)
+
+// DefaultSettle is how long the port is watched after an action, so that a
+// supervisor respawning the process is noticed
+const DefaultSettle = 2 * time.Second
+
+// verifyInterval is the pause between port lookups; each one runs lsof
+const verifyInterval = 250 * time.Millisecond
+
+// PortStatus describes who holds a port after an action
+type PortStatus int
+
+const (
+	PortFree      PortStatus = iota // Nobody listens on the port
+	PortHeld                        // The original process still holds the port
+	PortChild                       // A process that existed before the action inherited the socket
+	PortRespawned                   // A process started after the action re-bound the port
+)
+
+// Verification is the state of a port after an action
+type Verification struct {
+	Port   int
+	Status PortStatus
+	Holder *model.ProcessInfo // Process holding the port, nil when free
+	After  time.Duration      // For PortRespawned: how long after the action the new process started
+}
+
+// Free reports whether the port was released
+func (v Verification) Free() bool {
+	return v.Status == PortFree
+}
+
+// String describes the verification, e.g. "port 8080 is now free"
+func (v Verification) String() string {
+	switch v.Status {
+	case PortFree:
+		return fmt.Sprintf("port %d is now free", v.Port)
+	case PortHeld:
+		return fmt.Sprintf("port %d is still held by PID %d (%s)", v.Port, v.Holder.ID, v.Holder.Command)
+	case PortChild:
+		return fmt.Sprintf("port %d is still held by PID %d (%s, child)", v.Port, v.Holder.ID, v.Holder.Command)
+	default:
+		return fmt.Sprintf("port %d was re-bound by new PID %d (%s) %s later (respawned)",
+			v.Port, v.Holder.ID, v.Holder.Command, formatElapsed(v.After))
+	}
+}
+
+// PortVerifier checks whether an action actually released a port
+// Following Dependency Inversion Principle: the lookup is the injected process.Retriever
+type PortVerifier struct {
+	Settle    time.Duration
+	retriever process.Retriever
+	enhance   func(info *model.ProcessInfo) error
+	startedAt func(pid int) (time.Time, error)
+	now       func() time.Time
+	sleep     func(time.Duration)
+}
+
+// NewPortVerifier creates a PortVerifier that looks the port up with retriever
+func NewPortVerifier(retriever process.Retriever) *PortVerifier {
+	return &PortVerifier{
+		Settle:    DefaultSettle,
+		retriever: retriever,
+		enhance:   procfs.NewProcessEnhancer().Enhance,
+		startedAt: procfs.StartedAt,
+		now:       time.Now,
+		sleep:     time.Sleep,
+	}
+}
+
+// Verify looks up the port until the settle period has passed. actedAt is when the
+// first signal was sent, which may be well before a graceful stop returned; holders
+// started after it are respawns. A new holder is reported as soon as it appears;
+// otherwise the last state counts, so a process that is still shutting down gets
+// the whole settle period to exit.
+func (v *PortVerifier) Verify(port int, original *model.ProcessInfo, actedAt time.Time) Verification {
+	deadline := v.now().Add(v.Settle)
+	for {
+		holder, err := v.retriever.GetProcessByPort(port)
+		now := v.now()
+
+		var result Verification
+		switch {
+		case err != nil:
+			result = Verification{Port: port, Status: PortFree}
+		case holder.ID == original.ID:
+			result = Verification{Port: port, Status: PortHeld, Holder: holder}
+		default:
+			return v.classify(port, holder, original, actedAt, now)
+		}
+
+		if !now.Before(deadline) {
+			return result
+		}
+		v.sleep(verifyInterval)
+	}
+}
+
+// classify tells an inherited socket from a respawned process by the holder's
+// parent and start time
+func (v *PortVerifier) classify(port int, holder, original *model.ProcessInfo, actedAt, seen time.Time) Verification {
+	// lsof does not report the parent
+	_ = v.enhance(holder)
+	started, err := v.startedAt(holder.ID)
+	switch {
+	case holder.PPid == original.ID:
+		return Verification{Port: port, Status: PortChild, Holder: holder}
+	case err == nil && started.Before(actedAt.Add(-time.Second)):
+		// Start times have second resolution on some systems; allow the slack
+		return Verification{Port: port, Status: PortChild, Holder: holder}
+	}
+
+	after := seen.Sub(actedAt)
+	if err == nil && started.After(actedAt) {
+		after = started.Sub(actedAt)
+	}
+	return Verification{Port: port, Status: PortRespawned, Holder: holder, After: after}
+}
@@ -0,0 +1,179 @@
+package action
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+// fakeRetriever returns the scripted holders of a port, one per lookup; nil means free.
+// The last holder repeats once the script is exhausted.
+type fakeRetriever struct {
+	holders []*model.ProcessInfo
+	calls   int
+}
+
+func (f *fakeRetriever) GetProcessByPort(port int) (*model.ProcessInfo, error) {
+	i := f.calls
+	if i >= len(f.holders) {
+		i = len(f.holders) - 1
+	}
+	f.calls++
+	if f.holders[i] == nil {
+		return nil, fmt.Errorf("no service found on port %d", port)
+	}
+	holder := *f.holders[i]
+	return &holder, nil
+}
+
+// newTestVerifier returns a verifier over a fake clock starting at start that advances on every sleep
+func newTestVerifier(retriever *fakeRetriever, start time.Time, started map[int]time.Time, ppids map[int]int) *PortVerifier {
+	clock := start
+	v := NewPortVerifier(retriever)
+	v.now = func() time.Time { return clock }
+	v.sleep = func(d time.Duration) { clock = clock.Add(d) }
+	v.enhance = func(info *model.ProcessInfo) error {
+		info.PPid = ppids[info.ID]
+		return nil
+	}
+	v.startedAt = func(pid int) (time.Time, error) {
+		if t, ok := started[pid]; ok {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("no such process")
+	}
+	return v
+}
+
+func TestPortVerifierVerify(t *testing.T) {
+	actedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	original := &model.ProcessInfo{ID: 100, Command: "node"}
+	worker := &model.ProcessInfo{ID: 101, Command: "node"}
+	respawned := &model.ProcessInfo{ID: 200, Command: "node"}
+
+	tests := []struct {
+		name      string
+		holders   []*model.ProcessInfo
+		started   map[int]time.Time
+		ppids     map[int]int
+		expected  PortStatus
+		holderPID int
+		after     time.Duration
+		message   string
+	}{
+		{
+			name:     "released immediately",
+			holders:  []*model.ProcessInfo{nil},
+			expected: PortFree,
+			message:  "port 8080 is now free",
+		},
+		{
+			name:     "released while shutting down",
+			holders:  []*model.ProcessInfo{original, original, nil},
+			expected: PortFree,
+			message:  "port 8080 is now free",
+		},
+		{
+			name:      "original survives",
+			holders:   []*model.ProcessInfo{original},
+			expected:  PortHeld,
+			holderPID: 100,
+			message:   "port 8080 is still held by PID 100 (node)",
+		},
+		{
+			name:      "worker inherited the socket",
+			holders:   []*model.ProcessInfo{worker},
+			ppids:     map[int]int{101: 100},
+			expected:  PortChild,
+			holderPID: 101,
+			message:   "port 8080 is still held by PID 101 (node, child)",
+		},
+		{
+			name:      "reparented worker started before the action",
+			holders:   []*model.ProcessInfo{worker},
+			started:   map[int]time.Time{101: actedAt.Add(-time.Hour)},
+			ppids:     map[int]int{101: 1},
+			expected:  PortChild,
+			holderPID: 101,
+			message:   "port 8080 is still held by PID 101 (node, child)",
+		},
+		{
+			name:      "supervisor respawned the process",
+			holders:   []*model.ProcessInfo{nil, nil, respawned},
+			started:   map[int]time.Time{200: actedAt.Add(1200 * time.Millisecond)},
+			ppids:     map[int]int{200: 1},
+			expected:  PortRespawned,
+			holderPID: 200,
+			after:     1200 * time.Millisecond,
+			message:   "port 8080 was re-bound by new PID 200 (node) 1.2s later (respawned)",
+		},
+		{
+			name:      "respawn without start time uses the lookup time",
+			holders:   []*model.ProcessInfo{nil, nil, respawned},
+			expected:  PortRespawned,
+			holderPID: 200,
+			after:     500 * time.Millisecond,
+			message:   "port 8080 was re-bound by new PID 200 (node) 500ms later (respawned)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestVerifier(&fakeRetriever{holders: tt.holders}, actedAt, tt.started, tt.ppids)
+			result := v.Verify(8080, original, actedAt)
+
+			if result.Status != tt.expected {
+				t.Fatalf("Status = %v, want %v", result.Status, tt.expected)
+			}
+			if tt.holderPID != 0 && (result.Holder == nil || result.Holder.ID != tt.holderPID) {
+				t.Errorf("Holder = %+v, want PID %d", result.Holder, tt.holderPID)
+			}
+			if result.After != tt.after {
+				t.Errorf("After = %v, want %v", result.After, tt.after)
+			}
+			if result.Free() != (tt.expected == PortFree) {
+				t.Errorf("Free() = %v for status %v", result.Free(), result.Status)
+			}
+			if got := result.String(); got != tt.message {
+				t.Errorf("String() = %q, want %q", got, tt.message)
+			}
+		})
+	}
+}
+
+// TestPortVerifierWatchesForRespawn tests that a free port is watched for the whole settle period
+func TestPortVerifierWatchesForRespawn(t *testing.T) {
+	actedAt := time.Now()
+	retriever := &fakeRetriever{holders: []*model.ProcessInfo{nil}}
+	v := newTestVerifier(retriever, actedAt, nil, nil)
+
+	v.Verify(8080, &model.ProcessInfo{ID: 100}, actedAt)
+
+	if expected := int(DefaultSettle/verifyInterval) + 1; retriever.calls != expected {
+		t.Errorf("Expected %d lookups over the settle period, got %d", expected, retriever.calls)
+	}
+}
+
+// TestPortVerifierRespawnDuringGrace tests that a process respawned while a graceful
+// stop was waiting counts from the first signal, not from the end of the stop
+func TestPortVerifierRespawnDuringGrace(t *testing.T) {
+	actedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	respawned := &model.ProcessInfo{ID: 200, Command: "node"}
+	retriever := &fakeRetriever{holders: []*model.ProcessInfo{nil, respawned}}
+	started := map[int]time.Time{200: actedAt.Add(3 * time.Second)}
+	v := newTestVerifier(retriever, actedAt.Add(10*time.Second), started, map[int]int{200: 1})
+
+	result := v.Verify(8080, &model.ProcessInfo{ID: 100, Command: "node"}, actedAt)
+
+	if result.Status != PortRespawned {
+		t.Fatalf("Status = %v, want PortRespawned", result.Status)
+	}
+	if result.After != 3*time.Second {
+		t.Errorf("After = %v, want 3s", result.After)
+	}
+	if retriever.calls != 2 {
+		t.Errorf("Expected the port to be watched after a late start, got %d lookups", retriever.calls)
+	}
+}
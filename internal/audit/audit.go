@@ -0,0 +1,399 @@
+// Package audit keeps a record of the signals and container actions whoseport
+// performs, one JSON object per line, so that on a shared machine it is known who
+// stopped what and when.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+	"github.com/bluehoodie/whoseport/internal/privilege"
+	"github.com/bluehoodie/whoseport/internal/safety"
+)
+
+// EnvPath overrides the audit log location, e.g. WHOSEPORT_AUDIT_LOG=/var/log/whoseport.jsonl
+const EnvPath = "WHOSEPORT_AUDIT_LOG"
+
+// Off as the audit log path disables the log
+const Off = "off"
+
+// LogName is the file name of the default audit log
+const LogName = "audit.jsonl"
+
+// SharedDir holds the machine-wide audit log. whoseport writes there when an
+// administrator has created the directory and the user may append to the log,
+// e.g. after install -d -m 2775 -g whoseport /var/log/whoseport for the members of
+// group whoseport
+const SharedDir = "/var/log/whoseport"
+
+// sharedDir is SharedDir, replaced in tests
+var sharedDir = SharedDir
+
+// Kinds of action recorded in an entry
+const (
+	ActionSignal     = "signal"     // A signal sent to a process, or to every process of a scope
+	ActionSupervisor = "supervisor" // A stop through the process's supervisor, e.g. systemctl stop
+	ActionRestart    = "restart"    // A stopped process started again with its command line
+	ActionContainer  = "container"  // A docker, podman or compose command on a container
+	ActionService    = "service"    // A docker service command on a swarm service
+	ActionPod        = "pod"        // A crictl or kubectl command on a Kubernetes pod
+)
+
+// Outcomes of an action
+const (
+	OutcomeOK      = "ok"
+	OutcomeFailed  = "failed"
+	OutcomeRefused = "refused" // Stopped by a protection rule or a declined confirmation
+)
+
+// Entry is one action in the audit log
+type Entry struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user"`             // Who ran whoseport (the caller under sudo)
+	RunAs   string    `json:"run_as,omitempty"` // Effective user, when it differs from User
+	TTY     string    `json:"tty,omitempty"`    // Terminal whoseport ran on, e.g. pts/3
+	From    string    `json:"from,omitempty"`   // SSH client address
+	Host    string    `json:"host,omitempty"`
+	Port    int       `json:"port"`
+	Action  string    `json:"action"`
+	Signal  string    `json:"signal,omitempty"`  // Signal sent (signal actions)
+	Scope   string    `json:"scope,omitempty"`   // Kill scope, when wider than the process
+	PIDs    []int     `json:"pids,omitempty"`    // Every process the scope reached
+	Command []string  `json:"command,omitempty"` // Command run (supervisor, restart and container actions)
+	NewPID  int       `json:"new_pid,omitempty"` // PID of the restarted process
+	Target  Target    `json:"target"`
+	Outcome string    `json:"outcome"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Target is what the action was aimed at
+type Target struct {
+	PID           int    `json:"pid,omitempty"`
+	Command       string `json:"command,omitempty"`
+	Cmdline       string `json:"cmdline,omitempty"`
+	Cwd           string `json:"cwd,omitempty"`
+	User          string `json:"user,omitempty"`
+	Container     string `json:"container,omitempty"`      // Container ID
+	ContainerName string `json:"container_name,omitempty"` // Container, service or namespace/pod name
+	Image         string `json:"image,omitempty"`
+}
+
+// ProcessTarget describes a process from its snapshot; info may be nil
+func ProcessTarget(info *model.ProcessInfo) Target {
+	if info == nil {
+		return Target{}
+	}
+	return Target{
+		PID:     info.ID,
+		Command: info.Command,
+		Cmdline: strings.TrimSpace(info.FullCommand),
+		Cwd:     info.WorkingDir,
+		User:    info.User,
+	}
+}
+
+// NewEntry starts an entry for an action on port, filled in with who is running
+// whoseport and from where
+func NewEntry(port int) Entry {
+	e := Entry{Time: time.Now(), Port: port, TTY: terminal(), From: sshClient()}
+	if u, err := user.Current(); err == nil {
+		e.User = u.Username
+	}
+	if caller := privilege.Invoker(); caller != nil && caller.Username != e.User {
+		e.User, e.RunAs = caller.Username, e.User
+	}
+	e.Host, _ = os.Hostname()
+	return e
+}
+
+// Finish sets the outcome of the action from its error
+func (e *Entry) Finish(err error) {
+	switch {
+	case err == nil:
+		e.Outcome = OutcomeOK
+	case errors.Is(err, safety.ErrProtected) || errors.Is(err, safety.ErrNotConfirmed):
+		e.Outcome, e.Error = OutcomeRefused, err.Error()
+	default:
+		e.Outcome, e.Error = OutcomeFailed, err.Error()
+	}
+}
+
+// Summary describes the action in one line, e.g. "SIGTERM to PID 4242 [node server.js]"
+func (e Entry) Summary() string {
+	what := strings.Join(e.Command, " ")
+	if what == "" {
+		what = e.Action // Refused before a command was chosen
+	}
+	if e.Action == ActionRestart {
+		what = fmt.Sprintf("restart of PID %d", e.Target.PID)
+		if e.NewPID != 0 {
+			what += fmt.Sprintf(" as PID %d", e.NewPID)
+		}
+	}
+	if e.Signal != "" {
+		what = fmt.Sprintf("%s to PID %d", e.Signal, e.Target.PID)
+		if len(e.PIDs) > 1 {
+			what = fmt.Sprintf("%s to %d processes (%s of PID %d)", e.Signal, len(e.PIDs), e.Scope, e.Target.PID)
+		}
+	}
+
+	target := e.Target.ContainerName
+	if target == "" {
+		target = e.Target.Cmdline
+	}
+	if target == "" {
+		target = e.Target.Command
+	}
+	if target != "" {
+		what += " [" + target + "]"
+	}
+	return what
+}
+
+// Path returns the audit log location: the given path, $WHOSEPORT_AUDIT_LOG, the
+// shared /var/log/whoseport/audit.jsonl when the user can write to it, or else
+// $XDG_STATE_HOME/whoseport/audit.jsonl (~/.local/state/whoseport/audit.jsonl).
+// Under sudo, doas or pkexec the invoking user's log is the fallback. "off"
+// disables the log and returns "".
+func Path(path string) string {
+	return resolve(path, writable)
+}
+
+// ReadPath returns the audit log to read: like Path, but the shared log is chosen
+// when the user can read it, so that a user who may not write to it still sees
+// everyone's entries.
+func ReadPath(path string) string {
+	return resolve(path, readable)
+}
+
+// resolve picks the log location, using the shared log when usable accepts it
+func resolve(path string, usable func(file string) bool) string {
+	if path == "" {
+		path = os.Getenv(EnvPath)
+	}
+	if path == Off {
+		return ""
+	}
+	if path != "" {
+		return path
+	}
+	if shared := filepath.Join(sharedDir, LogName); usable(shared) {
+		return shared
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "whoseport", LogName)
+	}
+	home, err := os.UserHomeDir()
+	if caller := privilege.Invoker(); caller != nil {
+		home, err = caller.HomeDir, nil
+	}
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "state", "whoseport", LogName)
+}
+
+// Modes of access(2)
+const (
+	rOK = 4
+	wOK = 2
+)
+
+// writable reports whether the log file can be appended to, or created when it does
+// not exist yet. The directory must exist already.
+func writable(file string) bool {
+	if _, err := os.Stat(file); err == nil {
+		return syscall.Access(file, wOK) == nil
+	}
+	info, err := os.Stat(filepath.Dir(file))
+	return err == nil && info.IsDir() && syscall.Access(filepath.Dir(file), wOK) == nil
+}
+
+// readable reports whether the log file exists and can be read
+func readable(file string) bool {
+	return syscall.Access(file, rOK) == nil
+}
+
+// Logger appends entries to an audit log
+type Logger struct {
+	path string
+}
+
+// NewLogger creates a Logger writing to path
+func NewLogger(path string) *Logger {
+	return &Logger{path: path}
+}
+
+// Path returns the file the logger writes to
+func (l *Logger) Path() string {
+	return l.path
+}
+
+// Log appends the entry as one line. Each entry is written with a single append,
+// so several users can share one log file. A new log in the shared directory is
+// writable for the group when the directory is, so that it stays open to its
+// members; other logs keep the mode the umask gives them.
+func (l *Logger) Log(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	dir := filepath.Dir(l.path)
+	created := missingDirs(dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	_, missing := os.Stat(l.path)
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	// The umask would strip the group write bit, so it is set explicitly. Others
+	// never get write access: anyone could then forge or erase entries.
+	if info, err := os.Stat(dir); err == nil && missing != nil && filepath.Clean(dir) == filepath.Clean(sharedDir) {
+		if info.Mode().Perm()&0o020 != 0 {
+			_ = file.Chmod(0o664)
+		}
+	}
+
+	// Root writing to the caller's own log leaves it writable by the caller
+	if caller := privilege.Invoker(); caller != nil && missing != nil {
+		for _, d := range append(created, l.path) {
+			chown(d, caller)
+		}
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// missingDirs lists dir and those of its parents that do not exist yet
+func missingDirs(dir string) []string {
+	var missing []string
+	for ; dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		missing = append(missing, dir)
+	}
+	return missing
+}
+
+// chown gives a file the caller created as root back to the caller, when it is in
+// the caller's home
+func chown(path string, caller *user.User) {
+	if !strings.HasPrefix(path, caller.HomeDir+string(filepath.Separator)) {
+		return
+	}
+	uid, err1 := strconv.Atoi(caller.Uid)
+	gid, err2 := strconv.Atoi(caller.Gid)
+	if err1 == nil && err2 == nil {
+		_ = os.Chown(path, uid, gid)
+	}
+}
+
+// Read returns the entries of an audit log, oldest first. A missing log has no
+// entries; lines that are not valid entries are skipped.
+func Read(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Action == "" {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Filter selects entries; zero fields match everything
+type Filter struct {
+	Port    int
+	Process string // PID, or a command name or part of the command line
+	User    string
+	Since   time.Time
+	Limit   int // Most recent entries to keep
+}
+
+// Query returns the entries that match the filter, most recent first
+func Query(entries []Entry, f Filter) []Entry {
+	var matched []Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if f.Limit > 0 && len(matched) == f.Limit {
+			break
+		}
+		if f.matches(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+func (f Filter) matches(e Entry) bool {
+	if f.Port != 0 && e.Port != f.Port {
+		return false
+	}
+	if f.User != "" && e.User != f.User && e.Target.User != f.User {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if f.Process != "" {
+		if pid, err := strconv.Atoi(f.Process); err == nil {
+			return e.Target.PID == pid || containsPID(e.PIDs, pid)
+		}
+		return e.Target.Command == f.Process || strings.Contains(e.Target.Cmdline, f.Process) ||
+			e.Target.ContainerName == f.Process || strings.HasPrefix(e.Target.Container, f.Process)
+	}
+	return true
+}
+
+func containsPID(pids []int, pid int) bool {
+	for _, p := range pids {
+		if p == pid {
+			return true
+		}
+	}
+	return false
+}
+
+// sshClient returns the address of the SSH client whoseport runs under, if any
+func sshClient() string {
+	for _, name := range []string{"SSH_CONNECTION", "SSH_CLIENT"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return ""
+}
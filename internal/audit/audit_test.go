@@ -0,0 +1,260 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/safety"
+)
+
+func TestPath(t *testing.T) {
+	sharedDir = filepath.Join(t.TempDir(), "missing")
+	t.Cleanup(func() { sharedDir = SharedDir })
+	t.Setenv(EnvPath, "")
+	t.Setenv("XDG_STATE_HOME", "/state")
+	if got := Path(""); got != "/state/whoseport/audit.jsonl" {
+		t.Errorf("Path() = %q, want the XDG state directory without a shared directory", got)
+	}
+
+	sharedDir = t.TempDir()
+	if got := Path(""); got != filepath.Join(sharedDir, LogName) {
+		t.Errorf("Path() = %q, want the shared log once its directory exists", got)
+	}
+
+	t.Setenv(EnvPath, "/var/log/whoseport.jsonl")
+	if got := Path(""); got != "/var/log/whoseport.jsonl" {
+		t.Errorf("Path() = %q, want $%s", got, EnvPath)
+	}
+	if got := Path("/tmp/mine.jsonl"); got != "/tmp/mine.jsonl" {
+		t.Errorf("Path() = %q, want the given path", got)
+	}
+
+	t.Setenv(EnvPath, Off)
+	if got := Path(""); got != "" {
+		t.Errorf("Path() = %q, want \"\" when the log is off", got)
+	}
+}
+
+func TestReadPath(t *testing.T) {
+	sharedDir = t.TempDir()
+	t.Cleanup(func() { sharedDir = SharedDir })
+	t.Setenv(EnvPath, "")
+	t.Setenv("XDG_STATE_HOME", "/state")
+	if got := ReadPath(""); got != "/state/whoseport/audit.jsonl" {
+		t.Errorf("ReadPath() = %q, want the XDG state directory while there is no shared log", got)
+	}
+
+	shared := filepath.Join(sharedDir, LogName)
+	if err := os.WriteFile(shared, nil, 0o444); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadPath(""); got != shared {
+		t.Errorf("ReadPath() = %q, want the shared log even when it cannot be written", got)
+	}
+	if got := Path(""); os.Geteuid() != 0 && got == shared {
+		t.Errorf("Path() = %q, want the private log when the shared one cannot be written", got)
+	}
+}
+
+// TestLogSharedMode tests that a log created in a group-writable shared directory
+// stays writable for the group, whatever the umask, and never for others
+func TestLogSharedMode(t *testing.T) {
+	sharedDir = t.TempDir()
+	t.Cleanup(func() { sharedDir = SharedDir })
+	other := t.TempDir()
+	umask := syscall.Umask(0o022)
+	t.Cleanup(func() { syscall.Umask(umask) })
+
+	tests := []struct {
+		name string
+		dir  string
+		perm os.FileMode
+		want os.FileMode
+	}{
+		{"shared directory", sharedDir, 0o2775, 0o664},
+		{"world-writable shared directory", sharedDir, 0o1777, 0o664},
+		{"other directory", other, 0o1777, 0o644},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.Chmod(tt.dir, tt.perm); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(tt.dir, LogName)
+			os.Remove(path)
+			if err := NewLogger(path).Log(Entry{Action: ActionSignal, Outcome: OutcomeOK}); err != nil {
+				t.Fatalf("Log() failed: %v", err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mode := info.Mode().Perm(); mode != tt.want {
+				t.Errorf("log mode = %o, want %o", mode, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "whoseport", LogName)
+	logger := NewLogger(path)
+
+	first := Entry{Time: time.Unix(1700000000, 0).UTC(), User: "alice", Port: 8080, Action: ActionSignal,
+		Signal: "SIGTERM", Target: Target{PID: 4242, Command: "node"}, Outcome: OutcomeOK}
+	second := Entry{Time: time.Unix(1700000060, 0).UTC(), User: "bob", Port: 5432, Action: ActionContainer,
+		Command: []string{"docker", "stop", "abc123"}, Target: Target{Container: "abc123", ContainerName: "db"}, Outcome: OutcomeOK}
+	for _, e := range []Entry{first, second} {
+		if err := logger.Log(e); err != nil {
+			t.Fatalf("Log() failed: %v", err)
+		}
+	}
+
+	// A damaged line is skipped, not fatal
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintln(f, `{"time": "not a`)
+	f.Close()
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if !reflect.DeepEqual(entries, []Entry{first, second}) {
+		t.Errorf("Read() = %+v, want the two logged entries", entries)
+	}
+
+	if entries, err := Read(filepath.Join(t.TempDir(), "missing.jsonl")); err != nil || entries != nil {
+		t.Errorf("Read() of a missing log = %v, %v; want no entries", entries, err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	now := time.Now()
+	entries := []Entry{
+		{Time: now.Add(-48 * time.Hour), User: "alice", Port: 8080, Action: ActionSignal, Target: Target{PID: 100, Command: "node", Cmdline: "node server.js"}},
+		{Time: now.Add(-2 * time.Hour), User: "bob", Port: 3000, Action: ActionSignal, Scope: "pgroup", PIDs: []int{200, 201}, Target: Target{PID: 200, Command: "npm", User: "carol"}},
+		{Time: now.Add(-1 * time.Hour), User: "alice", Port: 5432, Action: ActionContainer, Target: Target{Container: "abc123def", ContainerName: "db"}},
+		{Time: now, User: "bob", Port: 8080, Action: ActionSignal, Target: Target{PID: 300, Command: "python3"}},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int // Indices into entries, most recent first
+	}{
+		{"everything", Filter{}, []int{3, 2, 1, 0}},
+		{"limit", Filter{Limit: 2}, []int{3, 2}},
+		{"port", Filter{Port: 8080}, []int{3, 0}},
+		{"pid", Filter{Process: "100"}, []int{0}},
+		{"pid in scope", Filter{Process: "201"}, []int{1}},
+		{"command", Filter{Process: "npm"}, []int{1}},
+		{"command line", Filter{Process: "server.js"}, []int{0}},
+		{"container name", Filter{Process: "db"}, []int{2}},
+		{"container ID prefix", Filter{Process: "abc123"}, []int{2}},
+		{"invoking user", Filter{User: "alice"}, []int{2, 0}},
+		{"target user", Filter{User: "carol"}, []int{1}},
+		{"since", Filter{Since: now.Add(-3 * time.Hour)}, []int{3, 2, 1}},
+		{"port and limit", Filter{Port: 8080, Limit: 1}, []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want []Entry
+			for _, i := range tt.want {
+				want = append(want, entries[i])
+			}
+			if got := Query(entries, tt.filter); !reflect.DeepEqual(got, want) {
+				t.Errorf("Query() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestFinish(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, OutcomeOK},
+		{fmt.Errorf("%w: PID 1 is the init process", safety.ErrProtected), OutcomeRefused},
+		{safety.ErrNotConfirmed, OutcomeRefused},
+		{fmt.Errorf("failed to send signal: %w", syscall.EPERM), OutcomeFailed},
+	}
+
+	for _, tt := range tests {
+		var e Entry
+		e.Finish(tt.err)
+		if e.Outcome != tt.want {
+			t.Errorf("Finish(%v) outcome = %q, want %q", tt.err, e.Outcome, tt.want)
+		}
+		if (tt.err == nil) != (e.Error == "") {
+			t.Errorf("Finish(%v) error = %q", tt.err, e.Error)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		entry Entry
+		want  string
+	}{
+		{Entry{Signal: "SIGTERM", Target: Target{PID: 4242, Command: "node", Cmdline: "node server.js"}}, "SIGTERM to PID 4242 [node server.js]"},
+		{Entry{Signal: "SIGKILL", Scope: "pgroup", PIDs: []int{200, 201, 202}, Target: Target{PID: 201, Command: "node"}}, "SIGKILL to 3 processes (pgroup of PID 201) [node]"},
+		{Entry{Command: []string{"docker", "stop", "abc123"}, Target: Target{PID: 900, Command: "docker-proxy", ContainerName: "web"}}, "docker stop abc123 [web]"},
+		{Entry{Command: []string{"systemctl", "stop", "app.service"}, Target: Target{Command: "node"}}, "systemctl stop app.service [node]"},
+		{Entry{Action: ActionRestart, Command: []string{"node", "server.js"}, NewPID: 4300, Target: Target{PID: 4242, Cmdline: "node server.js"}}, "restart of PID 4242 as PID 4300 [node server.js]"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.entry.Summary(); got != tt.want {
+			t.Errorf("Summary() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// fakeKiller fails every signal with err
+type fakeKiller struct {
+	err error
+}
+
+func (f *fakeKiller) Kill(pid int, signal syscall.Signal) error {
+	return f.err
+}
+
+func TestKiller(t *testing.T) {
+	var logged []Entry
+	log := func(e Entry) { logged = append(logged, e) }
+	template := Entry{User: "alice", Port: 8080, Target: Target{PID: 4242, Command: "node"}}
+
+	killer := NewKiller(&fakeKiller{}, template, log)
+	if err := killer.Kill(4242, syscall.SIGTERM); err != nil {
+		t.Fatalf("Kill() failed: %v", err)
+	}
+	refused := NewKiller(&fakeKiller{err: safety.ErrNotConfirmed}, template, log)
+	if err := refused.Kill(4242, syscall.SIGKILL); !errors.Is(err, safety.ErrNotConfirmed) {
+		t.Errorf("Kill() = %v, want the wrapped killer's error", err)
+	}
+
+	if len(logged) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(logged))
+	}
+	for i, want := range []struct{ signal, outcome string }{{"SIGTERM", OutcomeOK}, {"SIGKILL", OutcomeRefused}} {
+		e := logged[i]
+		if e.Action != ActionSignal || e.Signal != want.signal || e.Outcome != want.outcome || e.User != "alice" || e.Target.PID != 4242 {
+			t.Errorf("entry %d = %+v, want %s %s", i, e, want.signal, want.outcome)
+		}
+		if e.Time.IsZero() || !strings.HasPrefix(e.Summary(), want.signal) {
+			t.Errorf("entry %d is incomplete: %+v", i, e)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"syscall"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/action"
+)
+
+// Killer records every signal it passes on, and its outcome
+// Following Open/Closed Principle: wraps any action.Killer, which stays unchanged
+type Killer struct {
+	Killer action.Killer
+	Entry  Entry       // Template for the entries: who, the port and the target
+	Log    func(Entry) // Writes an entry
+}
+
+// NewKiller records the signals killer sends to the target described by entry
+func NewKiller(killer action.Killer, entry Entry, log func(Entry)) *Killer {
+	return &Killer{Killer: killer, Entry: entry, Log: log}
+}
+
+// Kill passes the signal on and logs it. A graceful stop logs SIGTERM and SIGKILL
+// as separate entries.
+func (k *Killer) Kill(pid int, signal syscall.Signal) error {
+	err := k.Killer.Kill(pid, signal)
+
+	e := k.Entry
+	e.Time = time.Now()
+	e.Action = ActionSignal
+	e.Signal = action.SignalName(signal)
+	e.Finish(err)
+	k.Log(e)
+	return err
+}
+
+// Unwrap returns the logged killer
+func (k *Killer) Unwrap() action.Killer {
+	return k.Killer
+}
@@ -0,0 +1,24 @@
+//go:build darwin
+
+package audit
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// terminal returns the controlling terminal of whoseport, e.g. ttys003, as ps
+// reports it ("??" for none)
+func terminal() string {
+	output, err := exec.Command("ps", "-p", strconv.Itoa(os.Getpid()), "-o", "tty=").Output()
+	if err != nil {
+		return ""
+	}
+	tty := strings.TrimSpace(string(output))
+	if tty == "??" {
+		return ""
+	}
+	return tty
+}
@@ -0,0 +1,27 @@
+//go:build linux
+
+package audit
+
+import (
+	"os"
+	"strings"
+)
+
+// terminal returns the terminal whoseport runs on, e.g. pts/3, from whichever of
+// stdin, stdout and stderr is connected to one
+func terminal() string {
+	for _, fd := range []string{"0", "1", "2"} {
+		if tty := ttyName(os.Readlink("/proc/self/fd/" + fd)); tty != "" {
+			return tty
+		}
+	}
+	return ""
+}
+
+// ttyName returns the terminal name of a device path, or "" for anything else
+func ttyName(path string, err error) string {
+	if err != nil || !(strings.HasPrefix(path, "/dev/pts/") || strings.HasPrefix(path, "/dev/tty")) {
+		return ""
+	}
+	return strings.TrimPrefix(path, "/dev/")
+}
@@ -0,0 +1,29 @@
+//go:build linux
+
+package audit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTTYName(t *testing.T) {
+	tests := []struct {
+		path string
+		err  error
+		want string
+	}{
+		{"/dev/pts/3", nil, "pts/3"},
+		{"/dev/tty1", nil, "tty1"},
+		{"/dev/null", nil, ""},
+		{"pipe:[123456]", nil, ""},
+		{"socket:[98765]", nil, ""},
+		{"", errors.New("no such file"), ""},
+	}
+
+	for _, tt := range tests {
+		if got := ttyName(tt.path, tt.err); got != tt.want {
+			t.Errorf("ttyName(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
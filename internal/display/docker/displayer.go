@@ -25,8 +25,22 @@ func NewDisplayer() *Displayer {
 
 // Display outputs the ContainerInfo as interactive UI.
 func (d *Displayer) Display(info *docker.ContainerInfo, port int) {
-	// Banner - clearly indicate this is a Docker container
-	d.printDockerBanner(fmt.Sprintf("PORT %d → DOCKER CONTAINER", port))
+	runtime := docker.RuntimeByName(info.Runtime)
+
+	// Banner - clearly indicate this is a Docker (or Podman/containerd) container
+	title := runtime.Title()
+	if info.Engine != nil && info.Engine.Rootless {
+		title += " (ROOTLESS)"
+	}
+	d.printDockerBanner(fmt.Sprintf("PORT %d → %s CONTAINER", port, title))
+
+	// A crash-looping container will grab the port again shortly: say so first
+	if info.CrashLooping() {
+		d.printCrashLoopWarning(info)
+	}
+
+	// Protected containers need --force: say why before anything else
+	d.printProtected(info.Protected, "container")
 
 	// Section 1: Container Identity
 	d.printModernSection("🐳 CONTAINER IDENTITY")
@@ -44,6 +58,29 @@ func (d *Displayer) Display(info *docker.ContainerInfo, port int) {
 	if info.RunningFor != "" {
 		d.printEnhancedField("Running For", info.RunningFor, terminal.ColorLime, "⏱")
 	}
+	if info.PodID != "" {
+		pod := info.PodName
+		if pod == "" {
+			pod = info.PodID
+		}
+		d.printEnhancedField("Pod", pod, terminal.ColorGold, "")
+	}
+	if info.Namespace != "" {
+		d.printEnhancedField("Namespace", info.Namespace, terminal.ColorGold, "")
+	}
+	if info.Engine != nil {
+		d.printEngineField(info.Engine)
+	}
+
+	// Health and exit details
+	if info.Health != nil || info.RestartCount > 0 || info.OOMKilled || info.ExitCode != 0 || info.Error != "" {
+		d.printHealthSection(info)
+	}
+
+	// Compose project (only for containers started by Compose)
+	if info.Compose != nil {
+		d.printComposeSection(info.Compose)
+	}
 
 	// Section 2: Image Information
 	d.printModernSection("📀 IMAGE")
@@ -103,6 +140,11 @@ func (d *Displayer) Display(info *docker.ContainerInfo, port int) {
 		d.printEnhancedField("Networks", strings.Join(info.Networks, ", "), terminal.ColorLavender, "")
 	}
 
+	// Port path to the application inside the container
+	if info.App != nil {
+		d.printPortPath(info, port)
+	}
+
 	// Section 4: Resource Usage
 	if info.CPUPercent != "" || info.MemUsage != "" {
 		d.printModernSection("📊 RESOURCE USAGE")
@@ -153,7 +195,11 @@ func (d *Displayer) Display(info *docker.ContainerInfo, port int) {
 					dst = "..." + dst[len(dst)-27:]
 				}
 
-				mountLine := fmt.Sprintf("%s → %s (%s, %s)", src, dst, mount.Type, mount.Mode)
+				kind := mount.Type
+				if mount.Anonymous() {
+					kind = "anonymous volume"
+				}
+				mountLine := fmt.Sprintf("%s → %s (%s, %s)", src, dst, kind, mount.Mode)
 				fmt.Printf("    %s┃%s %s%s%s\n",
 					terminal.ColorBrightBlue, terminal.ColorReset,
 					terminal.ColorDim, mountLine, terminal.ColorReset)
@@ -192,15 +238,175 @@ func (d *Displayer) Display(info *docker.ContainerInfo, port int) {
 		if info.ProcessCmd != "" {
 			d.printEnhancedField("Process Command", info.ProcessCmd, terminal.ColorDim, "")
 		}
-		fmt.Printf("  %s%sNote:%s The process above is the Docker proxy/container process.%s\n",
-			terminal.ColorDim, terminal.ColorBold, terminal.ColorReset, terminal.ColorReset)
+		fmt.Printf("  %s%sNote:%s The process above is the %s proxy/container process.%s\n",
+			terminal.ColorDim, terminal.ColorBold, terminal.ColorReset, runtime.Name, terminal.ColorReset)
 		fmt.Printf("  %sActions below will affect the %scontainer%s, not just the process.%s\n",
 			terminal.ColorDim, terminal.ColorBold, terminal.ColorReset, terminal.ColorReset)
 	}
 
+	// No host process holds the port: explain how it reaches the container
+	if info.ProcessID == 0 {
+		d.printModernSection("🔀 PORT FORWARDING")
+		if info.Forward != nil {
+			d.printEnhancedField("Forwarded By", fmt.Sprintf("%s DNAT (%s/%s)", info.Forward.Source, info.Forward.Table, info.Forward.Chain), terminal.ColorGold, "")
+			d.printEnhancedField("Rule", info.Forward.String(), terminal.ColorBrightCyan, "")
+		}
+		fmt.Printf("  %s%sNote:%s No host process holds port %d; it is forwarded to the container by %s.%s\n",
+			terminal.ColorDim, terminal.ColorBold, terminal.ColorReset, port, forwarder(info), terminal.ColorReset)
+		fmt.Printf("  %sActions below will affect the %scontainer%s.%s\n",
+			terminal.ColorDim, terminal.ColorBold, terminal.ColorReset, terminal.ColorReset)
+	}
+
 	d.printGradientDivider()
 }
 
+// printPortPath shows each hop from the host port to the application in the container.
+func (d *Displayer) printPortPath(info *docker.ContainerInfo, port int) {
+	d.printModernSection("🧭 PORT PATH")
+
+	hops := []string{fmt.Sprintf("host :%d", port)}
+	if info.ProcessID > 0 {
+		hops = append(hops, fmt.Sprintf("%s (PID %d)", info.ProcessCmd, info.ProcessID))
+	} else {
+		hops = append(hops, "netfilter DNAT")
+	}
+	address := info.IPAddress
+	if address == "" {
+		address = "container"
+	}
+	hops = append(hops, fmt.Sprintf("%s:%d", address, info.ContainerPort))
+	hops = append(hops, fmt.Sprintf("%s (PID %d)", info.App.Command, info.App.ID))
+	fmt.Printf("  %s%s%s\n", terminal.ColorBrightCyan, strings.Join(hops, " → "), terminal.ColorReset)
+
+	d.printEnhancedField("App PID", fmt.Sprintf("%d", info.App.ID), terminal.ColorBrightGreen, "🎯")
+	if info.App.FullCommand != "" {
+		d.printEnhancedField("App Command", format.Truncate(info.App.FullCommand, 60), terminal.ColorOrange, "")
+	}
+	if info.App.State != "" {
+		d.printEnhancedField("App State", info.App.State, terminal.ColorLime, "")
+	}
+	d.printEnhancedField("App UID", fmt.Sprintf("%d", info.App.UID), terminal.ColorLavender, "")
+	if info.App.MemoryRSS > 0 {
+		d.printEnhancedField("App Memory", format.FormatMemory(info.App.MemoryRSS), terminal.ColorPeach, "")
+	}
+}
+
+// forwarder names what forwards a port that no host process holds.
+func forwarder(info *docker.ContainerInfo) string {
+	if info.Forward != nil {
+		return "netfilter"
+	}
+	return "netfilter or the " + info.Runtime + " engine"
+}
+
+// maxHealthLogs is the number of most recent health-check results shown.
+const maxHealthLogs = 3
+
+// printCrashLoopWarning flags a container that keeps exiting and being restarted.
+func (d *Displayer) printCrashLoopWarning(info *docker.ContainerInfo) {
+	reason := fmt.Sprintf("last exit code %d", info.ExitCode)
+	if info.OOMKilled {
+		reason += ", killed by the OOM killer"
+	}
+	fmt.Printf("\n  %s%s🔁 CRASH LOOP:%s %s%s has restarted %d times (%s)%s\n",
+		terminal.ColorBold, terminal.ColorRed, terminal.ColorReset,
+		terminal.ColorOrange, info.Name, info.RestartCount, reason, terminal.ColorReset)
+	fmt.Printf("  %sStopping the process alone will not free the port; stop or remove the container.%s\n",
+		terminal.ColorDim, terminal.ColorReset)
+}
+
+// printProtected lists why a container or service is protected from being stopped.
+func (d *Displayer) printProtected(reasons []string, kind string) {
+	if len(reasons) == 0 {
+		return
+	}
+	d.printModernSection("🛡️  PROTECTED")
+	for _, reason := range reasons {
+		fmt.Printf("  %s• %s%s\n", terminal.ColorRed, reason, terminal.ColorReset)
+	}
+	fmt.Printf("  %sActions need --force and typing the %s name.%s\n", terminal.ColorDim, kind, terminal.ColorReset)
+}
+
+// printHealthSection shows the health check state, restarts and the last exit.
+func (d *Displayer) printHealthSection(info *docker.ContainerInfo) {
+	d.printModernSection("🩺 HEALTH")
+
+	if info.Health != nil {
+		d.printEnhancedField("Health", strings.ToUpper(info.Health.Status), d.getHealthColor(info.Health.Status), "")
+		if info.Health.FailingStreak > 0 {
+			d.printEnhancedField("Failing Streak", fmt.Sprintf("%d check(s)", info.Health.FailingStreak), terminal.ColorRed, "")
+		}
+	}
+
+	restartColor := terminal.ColorLime
+	if info.CrashLooping() {
+		restartColor = terminal.ColorRed
+	}
+	d.printEnhancedField("Restart Count", fmt.Sprintf("%d", info.RestartCount), restartColor, "🔁")
+
+	if info.ExitCode != 0 || info.FinishedAt != "" {
+		d.printEnhancedField("Last Exit Code", fmt.Sprintf("%d", info.ExitCode), d.getExitCodeColor(info.ExitCode), "")
+	}
+	if info.OOMKilled {
+		d.printEnhancedField("OOM Killed", "yes - the container ran out of memory", terminal.ColorRed, "💥")
+	}
+	if info.Error != "" {
+		d.printEnhancedField("Error", format.Truncate(info.Error, 60), terminal.ColorRed, "")
+	}
+
+	if info.Health != nil && len(info.Health.Log) > 0 {
+		logs := info.Health.Log
+		if len(logs) > maxHealthLogs {
+			logs = logs[len(logs)-maxHealthLogs:]
+		}
+		d.printEnhancedField("Recent Checks", fmt.Sprintf("last %d", len(logs)), terminal.ColorCyan, "")
+		for _, check := range logs {
+			output := strings.Join(strings.Fields(check.Output), " ")
+			if output == "" {
+				output = "(no output)"
+			}
+			fmt.Printf("    %s┃%s %sexit %d%s %s%s%s\n",
+				terminal.ColorBrightBlue, terminal.ColorReset,
+				d.getExitCodeColor(check.ExitCode), check.ExitCode, terminal.ColorReset,
+				terminal.ColorDim, format.Truncate(output, 60), terminal.ColorReset)
+		}
+	}
+}
+
+// printEngineField shows which engine answered, highlighting remote engines.
+func (d *Displayer) printEngineField(engine *docker.Engine) {
+	color := terminal.ColorDim
+	if !engine.Local {
+		color = terminal.ColorOrange
+	}
+	d.printEnhancedField("Engine", format.Truncate(engine.String(), 60), color, "🛰")
+}
+
+// printComposeSection shows the Compose project, its files and the other services.
+func (d *Displayer) printComposeSection(compose *docker.ComposeInfo) {
+	d.printModernSection("🧩 COMPOSE")
+	d.printEnhancedField("Project", compose.Project, terminal.ColorBrightCyan, "")
+	d.printEnhancedField("Service", compose.Service, terminal.ColorBrightGreen, "")
+	if len(compose.ConfigFiles) > 0 {
+		d.printEnhancedField("Compose File", strings.Join(compose.ConfigFiles, ", "), terminal.ColorLavender, "📄")
+	} else if compose.WorkingDir != "" {
+		d.printEnhancedField("Working Dir", compose.WorkingDir, terminal.ColorLavender, "📁")
+	}
+
+	if len(compose.Siblings) > 0 {
+		d.printEnhancedField("Other Services", fmt.Sprintf("%d container(s)", len(compose.Siblings)), terminal.ColorCyan, "")
+		for _, s := range compose.Siblings {
+			line := fmt.Sprintf("%s (%s)", s.Service, s.State)
+			if s.Ports != "" {
+				line += " " + s.Ports
+			}
+			fmt.Printf("    %s┃%s %s%s%s\n",
+				terminal.ColorBrightBlue, terminal.ColorReset,
+				terminal.ColorDim, format.Truncate(line, 70), terminal.ColorReset)
+		}
+	}
+}
+
 func (d *Displayer) printDockerBanner(text string) {
 	textLen := format.VisualWidth(text)
 	emojiWidth := 0 // No emoji in text itself
@@ -277,6 +483,26 @@ func (d *Displayer) getStateColor(state string) string {
 	}
 }
 
+func (d *Displayer) getHealthColor(status string) string {
+	switch strings.ToLower(status) {
+	case "healthy":
+		return terminal.ColorBrightGreen
+	case "unhealthy":
+		return terminal.ColorRed
+	case "starting":
+		return terminal.ColorYellow
+	default:
+		return terminal.ColorDim
+	}
+}
+
+func (d *Displayer) getExitCodeColor(code int) string {
+	if code == 0 {
+		return terminal.ColorBrightGreen
+	}
+	return terminal.ColorRed
+}
+
 func (d *Displayer) getStateEmoji(state string) string {
 	state = strings.ToLower(state)
 	switch state {
@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/docker"
+	"github.com/bluehoodie/whoseport/internal/terminal"
+)
+
+// DisplayService outputs a swarm service publishing the port through the routing mesh.
+func (d *Displayer) DisplayService(info *docker.ServiceInfo, port int) {
+	d.printDockerBanner(fmt.Sprintf("PORT %d → SWARM SERVICE", port))
+	d.printProtected(info.Protected, "service")
+
+	// Section 1: Service Identity
+	d.printModernSection("🐝 SWARM SERVICE")
+	d.printEnhancedField("Service Name", info.Name, terminal.ColorBrightCyan, "📦")
+	d.printEnhancedField("Service ID", info.ID, terminal.ColorLavender, "")
+	if info.Engine != nil {
+		d.printEngineField(info.Engine)
+	}
+	if info.Stack != "" {
+		d.printEnhancedField("Stack", info.Stack, terminal.ColorGold, "")
+	}
+	d.printEnhancedField("Image", info.Image, terminal.ColorBrightGreen, "📀")
+	d.printEnhancedField("Mode", info.Mode, terminal.ColorPeach, "")
+
+	replicaColor := terminal.ColorBrightGreen
+	if info.Running < info.Replicas {
+		replicaColor = terminal.ColorYellow
+	}
+	if info.Running == 0 {
+		replicaColor = terminal.ColorRed
+	}
+	d.printEnhancedField("Replicas", fmt.Sprintf("%d/%d running", info.Running, info.Replicas), replicaColor, "")
+
+	// Section 2: Published Ports
+	d.printModernSection("🌐 PUBLISHED PORTS")
+	for _, p := range info.Ports {
+		portColor := terminal.ColorMint
+		emoji := "  "
+		if p.PublishedPort == port {
+			portColor = terminal.ColorBrightGreen
+			emoji = "👉"
+		}
+		d.printEnhancedField(
+			fmt.Sprintf("  *:%d:%d", p.PublishedPort, p.TargetPort),
+			fmt.Sprintf("%s (%s)", strings.ToUpper(p.Protocol), p.PublishMode),
+			portColor,
+			emoji,
+		)
+	}
+
+	// Section 3: Tasks
+	d.printModernSection("📋 TASKS")
+	if len(info.Tasks) == 0 {
+		d.printEnhancedField("Tasks", "none running", terminal.ColorDim, "")
+	}
+	for _, task := range info.Tasks {
+		line := fmt.Sprintf("%s on %s: %s", task.Name, task.Node, task.State)
+		if task.ContainerID != "" {
+			id := task.ContainerID
+			if len(id) > 12 {
+				id = id[:12]
+			}
+			line += fmt.Sprintf(" (container %s)", id)
+		}
+		fmt.Printf("    %s┃%s %s%s%s\n",
+			terminal.ColorBrightBlue, terminal.ColorReset,
+			d.getStateColor(task.State), line, terminal.ColorReset)
+		if task.Error != "" {
+			fmt.Printf("    %s┃%s   %s%s%s\n",
+				terminal.ColorBrightBlue, terminal.ColorReset,
+				terminal.ColorRed, task.Error, terminal.ColorReset)
+		}
+	}
+
+	// Section 4: Underlying Process (for context)
+	d.printModernSection("🔧 UNDERLYING PROCESS")
+	if info.ProcessID > 0 {
+		d.printEnhancedField("Process ID", fmt.Sprintf("%d", info.ProcessID), terminal.ColorDim, "")
+		d.printEnhancedField("Process Command", info.ProcessCmd, terminal.ColorDim, "")
+	}
+	fmt.Printf("  %s%sNote:%s Port %d is published through the swarm routing mesh, which dockerd serves on every node.%s\n",
+		terminal.ColorDim, terminal.ColorBold, terminal.ColorReset, port, terminal.ColorReset)
+	fmt.Printf("  %sKilling dockerd would stop every container; actions below affect the %sservice%s.%s\n",
+		terminal.ColorDim, terminal.ColorBold, terminal.ColorReset, terminal.ColorReset)
+
+	d.printGradientDivider()
+}
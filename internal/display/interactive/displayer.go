@@ -6,6 +6,7 @@ import (
 
 	"github.com/bluehoodie/whoseport/internal/display/format"
 	"github.com/bluehoodie/whoseport/internal/model"
+	"github.com/bluehoodie/whoseport/internal/supervisor"
 	"github.com/bluehoodie/whoseport/internal/terminal"
 )
 
@@ -43,6 +44,35 @@ func (d *Displayer) Display(info *model.ProcessInfo, port int) {
 		d.printEnhancedField("Child Processes", fmt.Sprintf("%d", info.ChildCount), terminal.ColorMint, "")
 	}
 
+	// Supervisor warning: killing the process alone will not free the port for long
+	if info.Supervisor != nil {
+		d.printModernSection("♻️  SUPERVISOR")
+		d.printEnhancedField("Managed By", fmt.Sprintf("%s (PID %d)", info.Supervisor.Kind, info.Supervisor.PID), terminal.ColorOrange, "")
+		if info.Supervisor.Program != "" {
+			d.printEnhancedField("Program", info.Supervisor.Program, terminal.ColorGold, "")
+		}
+		if len(info.Supervisor.StopCommand) > 0 {
+			d.printEnhancedField("Stop With", strings.Join(info.Supervisor.StopCommand, " "), terminal.ColorBrightGreen, "")
+		}
+		fmt.Printf("  %s%s⚠️  %s%s\n", terminal.ColorBold, terminal.ColorYellow, supervisor.Describe(info.Supervisor), terminal.ColorReset)
+	}
+
+	// Protected processes need --force and a typed confirmation
+	if len(info.Protected) > 0 {
+		d.printModernSection("🛡️  PROTECTED")
+		for _, reason := range info.Protected {
+			fmt.Printf("  %s• %s%s\n", terminal.ColorRed, reason, terminal.ColorReset)
+		}
+		fmt.Printf("  %sActions need --force and typing the command name.%s\n", terminal.ColorDim, terminal.ColorReset)
+	}
+
+	// Another user's process: say which details need root instead of showing blanks
+	if len(info.Hidden) > 0 {
+		d.printModernSection("🔒 HIDDEN WITHOUT ROOT")
+		d.printEnhancedField("Not Readable", strings.Join(info.Hidden, ", "), terminal.ColorDim, "")
+		fmt.Printf("  %sThe process belongs to %s. Run with --sudo to see everything.%s\n", terminal.ColorDim, info.User, terminal.ColorReset)
+	}
+
 	// Section 2: Binary Information
 	if info.ExePath != "" {
 		d.printModernSection("📦 BINARY INFORMATION")
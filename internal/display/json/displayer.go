@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 
+	"github.com/bluehoodie/whoseport/internal/docker"
+	"github.com/bluehoodie/whoseport/internal/kubernetes"
 	"github.com/bluehoodie/whoseport/internal/model"
 )
 
@@ -33,3 +35,51 @@ func (d *Displayer) Display(info *model.ProcessInfo) error {
 	fmt.Fprintf(d.output, "%s\n", j)
 	return nil
 }
+
+// containerDocument is the combined JSON document for a port held by a container
+type containerDocument struct {
+	Process   *model.ProcessInfo    `json:"process"`
+	Container *docker.ContainerInfo `json:"container"`
+}
+
+// DisplayContainer outputs the process and its container as one JSON document
+func (d *Displayer) DisplayContainer(info *model.ProcessInfo, container *docker.ContainerInfo) error {
+	j, err := json.MarshalIndent(containerDocument{Process: info, Container: container}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Fprintf(d.output, "%s\n", j)
+	return nil
+}
+
+// serviceDocument is the combined JSON document for a port published by a swarm service
+type serviceDocument struct {
+	Process *model.ProcessInfo  `json:"process"`
+	Service *docker.ServiceInfo `json:"service"`
+}
+
+// DisplayService outputs the process and the swarm service as one JSON document
+func (d *Displayer) DisplayService(info *model.ProcessInfo, service *docker.ServiceInfo) error {
+	j, err := json.MarshalIndent(serviceDocument{Process: info, Service: service}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Fprintf(d.output, "%s\n", j)
+	return nil
+}
+
+// podDocument is the combined JSON document for a port held by a process in a Kubernetes pod
+type podDocument struct {
+	Process *model.ProcessInfo  `json:"process"`
+	Pod     *kubernetes.PodInfo `json:"pod"`
+}
+
+// DisplayPod outputs the process and its pod as one JSON document
+func (d *Displayer) DisplayPod(info *model.ProcessInfo, pod *kubernetes.PodInfo) error {
+	j, err := json.MarshalIndent(podDocument{Process: info, Pod: pod}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Fprintf(d.output, "%s\n", j)
+	return nil
+}
@@ -0,0 +1,130 @@
+package json
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/docker"
+	"github.com/bluehoodie/whoseport/internal/kubernetes"
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden compares got with testdata/<name>.golden, rewriting the file under -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output does not match %s:\n%s", path, got)
+	}
+}
+
+func testProcess() *model.ProcessInfo {
+	info := model.New("docker-pr", 4321, "root", "4u", "IPv4", "0x1", "0t0", "TCP", "*:8080 (LISTEN)")
+	info.FullCommand = "/usr/bin/docker-proxy -proto tcp -host-port 8080"
+	info.PPid = 1
+	info.State = "S"
+	info.Argv = []string{"/usr/bin/docker-proxy", "-proto", "tcp", "-host-port", "8080"}
+	info.Environ = []string{"SECRET=hunter2"}
+	return info
+}
+
+func TestDisplay(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewDisplayerWithWriter(&buf).Display(testProcess()); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "process", buf.Bytes())
+}
+
+func TestDisplayContainer(t *testing.T) {
+	container := &docker.ContainerInfo{
+		ID:      "0123456789abcdef0123456789abcdef",
+		ShortID: "0123456789ab",
+		Name:    "web",
+		Image:   "nginx:1.27",
+		State:   "running",
+		Runtime: "docker",
+		Ports: []docker.PortMapping{
+			{HostIP: "0.0.0.0", HostPort: "8080", ContainerPort: "80", Protocol: "tcp"},
+		},
+		Compose:    &docker.ComposeInfo{Project: "shop", Service: "web"},
+		Labels:     map[string]string{"com.docker.compose.project": "shop"},
+		Env:        []string{"DB_PASSWORD=hunter2"},
+		Stats:      &docker.Stats{CPUPerc: 1.5, MemUsage: 100 << 20, MemLimit: 2 << 30},
+		ProcessID:  4321,
+		ProcessCmd: "docker-pr",
+	}
+
+	var buf bytes.Buffer
+	if err := NewDisplayerWithWriter(&buf).DisplayContainer(testProcess(), container); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "container", buf.Bytes())
+}
+
+func TestDisplayService(t *testing.T) {
+	service := &docker.ServiceInfo{
+		ID:       "svc123",
+		Name:     "shop_web",
+		Image:    "nginx:1.27",
+		Mode:     "replicated",
+		Replicas: 2,
+		Running:  1,
+		Stack:    "shop",
+		Ports: []docker.ServicePort{
+			{Protocol: "tcp", TargetPort: 80, PublishedPort: 8080, PublishMode: "ingress"},
+		},
+		Tasks: []docker.ServiceTask{
+			{ID: "task1", Name: "shop_web.1", Node: "node-a", DesiredState: "running", State: "running", ContainerID: "c1"},
+			{ID: "task2", Name: "shop_web.2", Node: "node-b", DesiredState: "running", State: "rejected", Error: "no suitable node"},
+		},
+		Protected:  []string{"part of stack shop"},
+		ProcessID:  4321,
+		ProcessCmd: "dockerd",
+	}
+
+	var buf bytes.Buffer
+	if err := NewDisplayerWithWriter(&buf).DisplayService(testProcess(), service); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "service", buf.Bytes())
+}
+
+func TestDisplayPod(t *testing.T) {
+	pod := &kubernetes.PodInfo{
+		PodUID:        "6f1c2b9e-0000-4000-8000-000000000001",
+		ContainerID:   "abcdef0123456789abcdef0123456789",
+		ShortID:       "abcdef0123456",
+		QoSClass:      "Burstable",
+		Runtime:       "containerd",
+		PodIP:         "10.244.0.12",
+		Namespace:     "default",
+		PodName:       "web-7d4b9c",
+		ContainerName: "web",
+		Image:         "nginx:1.27",
+		State:         "CONTAINER_RUNNING",
+		Source:        "crictl",
+		ProcessID:     4321,
+		ProcessCmd:    "nginx",
+	}
+
+	var buf bytes.Buffer
+	if err := NewDisplayerWithWriter(&buf).DisplayPod(testProcess(), pod); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "pod", buf.Bytes())
+}
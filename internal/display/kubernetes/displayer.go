@@ -0,0 +1,99 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/kubernetes"
+	"github.com/bluehoodie/whoseport/internal/terminal"
+)
+
+// Displayer outputs Kubernetes PodInfo as a section below the process display.
+type Displayer struct {
+	theme terminal.Theme
+	width int
+}
+
+// NewDisplayer creates a new Kubernetes pod displayer.
+func NewDisplayer() *Displayer {
+	return &Displayer{
+		theme: terminal.DefaultTheme(),
+		width: 90,
+	}
+}
+
+// Display outputs the PodInfo as a Kubernetes section.
+func (d *Displayer) Display(pod *kubernetes.PodInfo) {
+	// Protected pods need --force: say why before anything else
+	d.printProtected(pod.Protected)
+
+	d.printModernSection("☸️  KUBERNETES")
+
+	if pod.Namespace != "" {
+		d.printEnhancedField("Namespace", pod.Namespace, terminal.ColorLavender, "")
+	}
+	if pod.PodName != "" {
+		d.printEnhancedField("Pod", pod.PodName, terminal.ColorBrightCyan, "📦")
+	}
+	d.printEnhancedField("Pod UID", pod.PodUID, terminal.ColorDim, "")
+	if pod.PodIP != "" {
+		d.printEnhancedField("Pod IP", pod.PodIP, terminal.ColorSkyBlue, "")
+	}
+	if pod.ContainerName != "" {
+		d.printEnhancedField("Container", pod.ContainerName, terminal.ColorBrightGreen, "")
+	}
+	d.printEnhancedField("Container ID", pod.ShortID, terminal.ColorLavender, "")
+	if pod.Image != "" {
+		d.printEnhancedField("Image", pod.Image, terminal.ColorPeach, "")
+	}
+	if pod.State != "" {
+		d.printEnhancedField("State", pod.State, terminal.ColorLime, "")
+	}
+	d.printEnhancedField("QoS Class", pod.QoSClass, terminal.ColorGold, "")
+	if pod.Runtime != "" {
+		d.printEnhancedField("Runtime", pod.Runtime, terminal.ColorSkyBlue, "")
+	}
+
+	if pod.PodName == "" {
+		fmt.Printf("  %sPod names could not be resolved (crictl unavailable and no kubelet pod directory).%s\n",
+			terminal.ColorDim, terminal.ColorReset)
+	} else if pod.Source != "" {
+		fmt.Printf("  %sResolved via %s%s\n", terminal.ColorDim, pod.Source, terminal.ColorReset)
+	}
+	fmt.Printf("  %s%sNote:%s Killing the process only restarts the container; the kubelet manages its lifecycle.%s\n",
+		terminal.ColorDim, terminal.ColorBold, terminal.ColorReset, terminal.ColorReset)
+
+	d.printGradientDivider()
+}
+
+// printProtected lists why a pod is protected from being stopped.
+func (d *Displayer) printProtected(reasons []string) {
+	if len(reasons) == 0 {
+		return
+	}
+	d.printModernSection("🛡️  PROTECTED")
+	for _, reason := range reasons {
+		fmt.Printf("  %s• %s%s\n", terminal.ColorRed, reason, terminal.ColorReset)
+	}
+	fmt.Printf("  %sActions need --force and typing the pod name.%s\n", terminal.ColorDim, terminal.ColorReset)
+}
+
+func (d *Displayer) printModernSection(title string) {
+	fmt.Printf("\n  %s%s▌%s %s%s%s\n", terminal.ColorBold, terminal.ColorBrightBlue, terminal.ColorReset, terminal.ColorBold, title, terminal.ColorReset)
+}
+
+func (d *Displayer) printEnhancedField(label string, value string, valueColor string, emoji string) {
+	if emoji != "" {
+		fmt.Printf("  %s%s%-20s%s %s%s %s%s%s\n",
+			terminal.ColorBold, d.theme.Label, label+":", terminal.ColorReset,
+			emoji, valueColor, value, terminal.ColorReset, terminal.ColorReset)
+	} else {
+		fmt.Printf("  %s%s%-20s%s %s%s%s\n",
+			terminal.ColorBold, d.theme.Label, label+":", terminal.ColorReset,
+			valueColor, value, terminal.ColorReset)
+	}
+}
+
+func (d *Displayer) printGradientDivider() {
+	fmt.Printf("%s%s%s%s\n", terminal.ColorBold, terminal.ColorBrightBlue, strings.Repeat("─", d.width), terminal.ColorReset)
+}
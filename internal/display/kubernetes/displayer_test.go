@@ -0,0 +1,88 @@
+package kubernetes
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/display/format"
+	"github.com/bluehoodie/whoseport/internal/kubernetes"
+)
+
+func captureOutput(fn func()) string {
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		panic(err)
+	}
+	os.Stdout = w
+
+	fn()
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	output, err := io.ReadAll(r)
+	if err != nil {
+		panic(err)
+	}
+	return format.StripAnsiCodes(string(output))
+}
+
+func TestDisplayResolvedPod(t *testing.T) {
+	pod := &kubernetes.PodInfo{
+		PodUID:        "1f2e3d4c-5b6a-7980-a1b2-c3d4e5f60718",
+		ShortID:       "ab12ab12ab12a",
+		QoSClass:      "Burstable",
+		Runtime:       "containerd",
+		PodIP:         "10.244.0.5",
+		Namespace:     "shop",
+		PodName:       "api-5d9f7b-qwert",
+		ContainerName: "api",
+		Image:         "registry.example.com/shop/api:1.4",
+		State:         "RUNNING",
+		Source:        "crictl",
+	}
+
+	output := captureOutput(func() { NewDisplayer().Display(pod) })
+
+	for _, want := range []string{
+		"KUBERNETES",
+		"Namespace:", "shop",
+		"Pod:", "api-5d9f7b-qwert",
+		"Pod IP:", "10.244.0.5",
+		"Container:", "api",
+		"Container ID:", "ab12ab12ab12a",
+		"Image:", "registry.example.com/shop/api:1.4",
+		"QoS Class:", "Burstable",
+		"Runtime:", "containerd",
+		"Resolved via crictl",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "could not be resolved") {
+		t.Errorf("resolved pod reported as unresolved:\n%s", output)
+	}
+}
+
+func TestDisplayUnresolvedPod(t *testing.T) {
+	pod := &kubernetes.PodInfo{
+		PodUID:   "1f2e3d4c-5b6a-7980-a1b2-c3d4e5f60718",
+		ShortID:  "ab12ab12ab12a",
+		QoSClass: "BestEffort",
+	}
+
+	output := captureOutput(func() { NewDisplayer().Display(pod) })
+
+	if !strings.Contains(output, "Pod names could not be resolved") {
+		t.Errorf("output should explain the missing names:\n%s", output)
+	}
+	for _, absent := range []string{"Namespace:", "Pod:", "Pod IP:", "Image:", "Resolved via"} {
+		if strings.Contains(output, absent) {
+			t.Errorf("output should not show %q for an unresolved pod:\n%s", absent, output)
+		}
+	}
+}
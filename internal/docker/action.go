@@ -6,9 +6,18 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/action"
 )
 
+// defaultLogLines is the number of log lines shown when the user accepts the default.
+const defaultLogLines = 100
+
 // Action represents a Docker container action.
 type Action int
 
@@ -17,12 +26,30 @@ const (
 	ActionStop
 	ActionStopAndRemove
 	ActionRemove
+	ActionStopPod
+	ActionRemovePod
+	ActionStopService
+	ActionStopProject
+	ActionRestart
+	ActionKill
+	ActionPause
+	ActionUnpause
+	ActionLogs
+	ActionExec
+	ActionScaleService
+	ActionRemoveService
 )
 
 // ActionHandler handles Docker container actions.
 type ActionHandler struct {
 	reader      io.Reader
 	writer      io.Writer
+	scanner     *bufio.Scanner                    // Shared by all prompts so buffered input is not lost between them
+	stopTimeout int                               // Seconds passed to stop -t; negative uses the engine default
+	recordDir   string                            // Directory removed container configs are saved to; empty disables
+	guard       func() error                      // Runs before actions that change the container; an error refuses them
+	plan        *action.Plan                      // Records commands instead of running them (--dry-run); nil runs them
+	audit       func(command []string, err error) // Told about every command that changes a container; nil for none
 	colorBold   string
 	colorYellow string
 	colorCyan   string
@@ -36,6 +63,9 @@ func NewActionHandler() *ActionHandler {
 	return &ActionHandler{
 		reader:      os.Stdin,
 		writer:      os.Stdout,
+		scanner:     bufio.NewScanner(os.Stdin),
+		stopTimeout: -1,
+		recordDir:   defaultRecordDir(),
 		colorBold:   "\033[1m",
 		colorYellow: "\033[33m",
 		colorCyan:   "\033[36m",
@@ -50,6 +80,8 @@ func NewActionHandlerWithIO(reader io.Reader, writer io.Writer) *ActionHandler {
 	return &ActionHandler{
 		reader:      reader,
 		writer:      writer,
+		scanner:     bufio.NewScanner(reader),
+		stopTimeout: -1,
 		colorBold:   "\033[1m",
 		colorYellow: "\033[33m",
 		colorCyan:   "\033[36m",
@@ -59,58 +91,203 @@ func NewActionHandlerWithIO(reader io.Reader, writer io.Writer) *ActionHandler {
 	}
 }
 
+// SetStopTimeout sets the seconds the engine waits before killing a stopping container.
+// A negative value keeps the engine default.
+func (h *ActionHandler) SetStopTimeout(seconds int) {
+	h.stopTimeout = seconds
+}
+
+// SetGuard installs a check that runs before every action that stops, removes,
+// restarts, pauses or signals the container or service. An error refuses the action.
+func (h *ActionHandler) SetGuard(guard func() error) {
+	h.guard = guard
+}
+
+// SetPlan makes the handler record the commands it would run in plan instead of
+// running them (--dry-run).
+func (h *ActionHandler) SetPlan(plan *action.Plan) {
+	h.plan = plan
+}
+
+// SetAuditor installs a hook that is told about every command run to change the
+// container or service, and its error
+func (h *ActionHandler) SetAuditor(audit func(command []string, err error)) {
+	h.audit = audit
+}
+
+// run runs a command that changes the container or service and reports it to the auditor
+func (h *ActionHandler) run(cmd *exec.Cmd) ([]byte, error) {
+	output, err := cmd.CombinedOutput()
+	if h.audit != nil {
+		h.audit(cmd.Args, err)
+	}
+	return output, err
+}
+
+// record adds the command to the dry-run plan. It returns false when commands run.
+func (h *ActionHandler) record(cmd *exec.Cmd) bool {
+	if h.plan == nil {
+		return false
+	}
+	h.plan.Add(action.Operation{Kind: action.OpCommand, Command: cmd.Args})
+	return true
+}
+
+// ConfirmTyped asks the user to type word to go ahead with an action on a protected
+// container. Anything else, including an empty answer, declines.
+func (h *ActionHandler) ConfirmTyped(word string) bool {
+	fmt.Fprintf(h.writer, "%s%s🛡️  Type %s to confirm:%s ", h.colorBold, h.colorRed, word, h.colorReset)
+	if !h.scanner.Scan() {
+		return false
+	}
+	return strings.TrimSpace(h.scanner.Text()) == word
+}
+
+// authorize runs the guard for actions that change the container or service.
+// Logs and exec leave it running; kills are checked by KillContainer.
+func (h *ActionHandler) authorize(action Action) error {
+	switch action {
+	case ActionCancel, ActionLogs, ActionExec, ActionUnpause, ActionKill:
+		return nil
+	}
+	if h.guard == nil {
+		return nil
+	}
+	return h.guard()
+}
+
 // PromptAction prompts the user to select an action for the Docker container.
+// Podman pod members additionally get pod-level actions.
 func (h *ActionHandler) PromptAction(info *ContainerInfo) Action {
-	fmt.Fprintf(h.writer, "%s%s🐳 Container %s (%s) - Select action:%s\n",
-		h.colorBold, h.colorYellow, info.Name, info.ShortID, h.colorReset)
-	fmt.Fprintf(h.writer, "  [1] Stop container (docker stop)\n")
-	fmt.Fprintf(h.writer, "  [2] Stop and remove container (docker stop + docker rm)\n")
-	fmt.Fprintf(h.writer, "  [3] Force remove running container (docker rm -f)\n")
-	fmt.Fprintf(h.writer, "  [4] Cancel\n")
-	fmt.Fprintf(h.writer, "%sChoice [4]:%s ", h.colorBold, h.colorReset)
+	runtime := RuntimeByName(info.Runtime)
+	stop := runtime.Binary + " stop"
+	if h.stopTimeout >= 0 {
+		stop += fmt.Sprintf(" -t %d", h.stopTimeout)
+	}
+	pause := ActionPause
+	pauseLabel := fmt.Sprintf("Pause container (%s pause)", runtime.Binary)
+	if info.State == "paused" {
+		pause = ActionUnpause
+		pauseLabel = fmt.Sprintf("Unpause container (%s unpause)", runtime.Binary)
+	}
 
-	scanner := bufio.NewScanner(h.reader)
+	actions := []Action{ActionStop, ActionStopAndRemove, ActionRemove, ActionRestart, ActionKill, pause, ActionLogs, ActionExec}
+	labels := []string{
+		fmt.Sprintf("Stop container (%s)", stop),
+		fmt.Sprintf("Stop and remove container (%s + %s rm)", stop, runtime.Binary),
+		fmt.Sprintf("Force remove running container (%s rm -f)", runtime.Binary),
+		fmt.Sprintf("Restart container (%s restart)", runtime.Binary),
+		fmt.Sprintf("Send signal to container (%s kill --signal)", runtime.Binary),
+		pauseLabel,
+		fmt.Sprintf("Show logs (%s logs --tail)", runtime.Binary),
+		fmt.Sprintf("Open shell in container (%s exec -it %s sh)", runtime.Binary, info.ShortID),
+	}
+	if info.PodID != "" {
+		pod := info.PodName
+		if pod == "" {
+			pod = info.PodID
+		}
+		actions = append(actions, ActionStopPod, ActionRemovePod)
+		labels = append(labels,
+			fmt.Sprintf("Stop whole pod %s (%s pod stop)", pod, runtime.Binary),
+			fmt.Sprintf("Remove whole pod %s (%s pod rm -f)", pod, runtime.Binary),
+		)
+	}
+	if info.Compose != nil {
+		actions = append(actions, ActionStopService, ActionStopProject)
+		labels = append(labels,
+			fmt.Sprintf("Stop service %s (%s compose -p %s stop %s)", info.Compose.Service, runtime.Binary, info.Compose.Project, info.Compose.Service),
+			fmt.Sprintf("Stop whole project %s (%s compose -p %s stop)", info.Compose.Project, runtime.Binary, info.Compose.Project),
+		)
+	}
+	actions = append(actions, ActionCancel)
+	labels = append(labels, "Cancel")
+	cancel := strconv.Itoa(len(actions))
+
+	emoji := "🐳"
+	switch runtime.Name {
+	case PodmanRuntime.Name:
+		emoji = "🦭"
+	case ContainerdRuntime.Name:
+		emoji = "📦"
+	}
+	fmt.Fprintf(h.writer, "%s%s%s Container %s (%s) - Select action:%s\n",
+		h.colorBold, h.colorYellow, emoji, info.Name, info.ShortID, h.colorReset)
+	for i, label := range labels {
+		fmt.Fprintf(h.writer, "  [%d] %s\n", i+1, label)
+	}
+	fmt.Fprintf(h.writer, "%sChoice [%s]:%s ", h.colorBold, cancel, h.colorReset)
 
 	for {
-		if !scanner.Scan() {
+		if !h.scanner.Scan() {
 			return ActionCancel
 		}
 
-		choice := strings.TrimSpace(scanner.Text())
+		choice := strings.TrimSpace(h.scanner.Text())
 
 		// Default to Cancel if empty
 		if choice == "" {
-			choice = "4"
+			choice = cancel
 		}
 
-		switch choice {
-		case "1":
-			return ActionStop
-		case "2":
-			return ActionStopAndRemove
-		case "3":
-			return ActionRemove
-		case "4":
-			return ActionCancel
-		default:
-			fmt.Fprintf(h.writer, "%sInvalid choice. Please enter 1-4.%s\n", h.colorYellow, h.colorReset)
-			fmt.Fprintf(h.writer, "%sChoice [4]:%s ", h.colorBold, h.colorReset)
+		if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(actions) {
+			return actions[n-1]
 		}
+
+		fmt.Fprintf(h.writer, "%sInvalid choice. Please enter 1-%s.%s\n", h.colorYellow, cancel, h.colorReset)
+		fmt.Fprintf(h.writer, "%sChoice [%s]:%s ", h.colorBold, cancel, h.colorReset)
 	}
 }
 
 // ExecuteAction executes the selected Docker action.
 func (h *ActionHandler) ExecuteAction(action Action, info *ContainerInfo) error {
+	if err := h.authorize(action); err != nil {
+		return err
+	}
+
 	switch action {
 	case ActionStop:
 		return h.stopContainer(info)
 	case ActionStopAndRemove:
+		removeVolumes, ok := h.promptRemoval(info)
+		if !ok {
+			return nil
+		}
 		if err := h.stopContainer(info); err != nil {
 			return err
 		}
-		return h.removeContainer(info, false)
+		return h.removeContainer(info, false, removeVolumes)
 	case ActionRemove:
-		return h.removeContainer(info, true)
+		removeVolumes, ok := h.promptRemoval(info)
+		if !ok {
+			return nil
+		}
+		return h.removeContainer(info, true, removeVolumes)
+	case ActionStopPod:
+		return h.podAction(info, "⏸  Stopping", "stopped", "stop")
+	case ActionRemovePod:
+		return h.podAction(info, "🗑  Removing", "removed", "rm", "-f")
+	case ActionStopService:
+		return h.composeStop(info, info.Compose.Service)
+	case ActionStopProject:
+		return h.composeStop(info, "")
+	case ActionRestart:
+		return h.simpleAction(info, "🔄 Restarting", "restarted", "restart")
+	case ActionKill:
+		sig, ok := h.promptSignal()
+		if !ok {
+			return nil
+		}
+		return h.KillContainer(info, sig)
+	case ActionPause:
+		return h.simpleAction(info, "⏸  Pausing", "paused", "pause")
+	case ActionUnpause:
+		return h.simpleAction(info, "▶️  Unpausing", "unpaused", "unpause")
+	case ActionLogs:
+		lines, follow := h.promptLogOptions()
+		return h.ShowLogs(info, lines, follow)
+	case ActionExec:
+		return h.execShell(info)
 	case ActionCancel:
 		return nil
 	default:
@@ -118,12 +295,33 @@ func (h *ActionHandler) ExecuteAction(action Action, info *ContainerInfo) error
 	}
 }
 
+// StopAndRemove stops and removes the container without prompting (-k). Its volumes
+// are kept, and its configuration is saved like for a removal chosen in the prompt.
+func (h *ActionHandler) StopAndRemove(info *ContainerInfo) error {
+	if err := h.authorize(ActionStopAndRemove); err != nil {
+		return err
+	}
+	if err := h.stopContainer(info); err != nil {
+		return err
+	}
+	return h.removeContainer(info, false, false)
+}
+
 // stopContainer stops a running container.
 func (h *ActionHandler) stopContainer(info *ContainerInfo) error {
+	args := []string{"stop"}
+	if h.stopTimeout >= 0 {
+		args = append(args, "-t", strconv.Itoa(h.stopTimeout))
+	}
+	args = append(args, info.ID)
+
+	cmd := runtimeFor(info).command(args...)
+	if h.record(cmd) {
+		return nil
+	}
 	fmt.Fprintf(h.writer, "%s⏸  Stopping container %s...%s\n", h.colorCyan, info.Name, h.colorReset)
 
-	cmd := exec.Command("docker", "stop", info.ID)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := h.run(cmd); err != nil {
 		return fmt.Errorf("failed to stop container: %w\nOutput: %s", err, string(output))
 	}
 
@@ -131,27 +329,405 @@ func (h *ActionHandler) stopContainer(info *ContainerInfo) error {
 	return nil
 }
 
-// removeContainer removes a container (optionally with force).
-func (h *ActionHandler) removeContainer(info *ContainerInfo, force bool) error {
+// removeContainer removes a container (optionally with force and its anonymous volumes)
+// and saves its configuration so it can be recreated.
+func (h *ActionHandler) removeContainer(info *ContainerInfo, force, volumes bool) error {
 	action := "Removing"
 	if force {
 		action = "Force removing"
 	}
 
-	fmt.Fprintf(h.writer, "%s🗑  %s container %s...%s\n", h.colorCyan, action, info.Name, h.colorReset)
-
 	args := []string{"rm"}
 	if force {
 		args = append(args, "-f")
 	}
+	if volumes {
+		args = append(args, "-v")
+	}
 	args = append(args, info.ID)
 
-	cmd := exec.Command("docker", args...)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	cmd := runtimeFor(info).command(args...)
+	if h.record(cmd) {
+		return nil
+	}
+	fmt.Fprintf(h.writer, "%s🗑  %s container %s...%s\n", h.colorCyan, action, info.Name, h.colorReset)
+
+	if output, err := h.run(cmd); err != nil {
 		return fmt.Errorf("failed to remove container: %w\nOutput: %s", err, string(output))
 	}
 
 	fmt.Fprintf(h.writer, "%s✓ Container %s removed successfully%s\n", h.colorGreen, info.Name, h.colorReset)
+
+	if h.recordDir != "" {
+		path, err := recordRemoval(h.recordDir, info, volumes, time.Now())
+		if err != nil {
+			fmt.Fprintf(h.writer, "%s⚠️  Could not save the container config: %v%s\n", h.colorYellow, err, h.colorReset)
+		} else {
+			fmt.Fprintf(h.writer, "%s💾 Saved container config (with a run command to recreate it) to %s%s\n", h.colorCyan, path, h.colorReset)
+		}
+	}
+	return nil
+}
+
+// promptRemoval shows what happens to the container's mounts when it is removed and
+// asks whether its anonymous volumes go too. ok is false when the user cancels.
+// Containers without mounts are removed without asking.
+func (h *ActionHandler) promptRemoval(info *ContainerInfo) (removeVolumes, ok bool) {
+	if len(info.Mounts) == 0 {
+		return false, true
+	}
+
+	var anonymous, named, binds, tmpfs []string
+	for _, m := range info.Mounts {
+		switch {
+		case m.Anonymous():
+			anonymous = append(anonymous, fmt.Sprintf("%s → %s", m.Name[:12], m.Destination))
+		case m.Type == "volume":
+			named = append(named, fmt.Sprintf("%s → %s", m.Name, m.Destination))
+		case m.Type == "tmpfs":
+			tmpfs = append(tmpfs, m.Destination)
+		default:
+			binds = append(binds, fmt.Sprintf("%s → %s", m.Source, m.Destination))
+		}
+	}
+
+	fmt.Fprintf(h.writer, "%s%s⚠️  Data attached to container %s:%s\n", h.colorBold, h.colorYellow, info.Name, h.colorReset)
+	h.printMountGroup("Anonymous volumes (orphaned by rm, deleted by rm -v)", h.colorRed, anonymous)
+	h.printMountGroup("tmpfs mounts (contents are lost)", h.colorRed, tmpfs)
+	h.printMountGroup("Named volumes (kept)", h.colorGreen, named)
+	h.printMountGroup("Bind mounts (kept on the host)", h.colorGreen, binds)
+
+	runtime := RuntimeByName(info.Runtime)
+	choices := []bool{false}
+	labels := []string{fmt.Sprintf("Remove container, keep volumes (%s rm)", runtime.Binary)}
+	if len(anonymous) > 0 {
+		choices = append(choices, true)
+		labels = append(labels, fmt.Sprintf("Remove container and its anonymous volumes (%s rm -v)", runtime.Binary))
+	}
+	labels = append(labels, "Cancel")
+	cancel := strconv.Itoa(len(labels))
+
+	for i, label := range labels {
+		fmt.Fprintf(h.writer, "  [%d] %s\n", i+1, label)
+	}
+	fmt.Fprintf(h.writer, "%sChoice [%s]:%s ", h.colorBold, cancel, h.colorReset)
+
+	for {
+		if !h.scanner.Scan() {
+			return false, false
+		}
+
+		choice := strings.TrimSpace(h.scanner.Text())
+
+		// Default to Cancel if empty
+		if choice == "" || choice == cancel {
+			return false, false
+		}
+
+		if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1], true
+		}
+
+		fmt.Fprintf(h.writer, "%sInvalid choice. Please enter 1-%s.%s\n", h.colorYellow, cancel, h.colorReset)
+		fmt.Fprintf(h.writer, "%sChoice [%s]:%s ", h.colorBold, cancel, h.colorReset)
+	}
+}
+
+// printMountGroup prints a titled list of mounts, if any.
+func (h *ActionHandler) printMountGroup(title, color string, mounts []string) {
+	if len(mounts) == 0 {
+		return
+	}
+	fmt.Fprintf(h.writer, "  %s%s:%s\n", color, title, h.colorReset)
+	for _, m := range mounts {
+		fmt.Fprintf(h.writer, "    - %s\n", m)
+	}
+}
+
+// simpleAction runs a container command that takes only the container ID (restart, pause, unpause).
+func (h *ActionHandler) simpleAction(info *ContainerInfo, progress, done, command string) error {
+	cmd := runtimeFor(info).command(command, info.ID)
+	if h.record(cmd) {
+		return nil
+	}
+	fmt.Fprintf(h.writer, "%s%s container %s...%s\n", h.colorCyan, progress, info.Name, h.colorReset)
+
+	if output, err := h.run(cmd); err != nil {
+		return fmt.Errorf("failed to %s container: %w\nOutput: %s", command, err, string(output))
+	}
+
+	fmt.Fprintf(h.writer, "%s✓ Container %s %s successfully%s\n", h.colorGreen, info.Name, done, h.colorReset)
+	return nil
+}
+
+// KillContainer sends a signal to the container's main process (docker kill --signal).
+func (h *ActionHandler) KillContainer(info *ContainerInfo, sig syscall.Signal) error {
+	if h.guard != nil {
+		if err := h.guard(); err != nil {
+			return err
+		}
+	}
+
+	name := signalName(sig)
+	cmd := runtimeFor(info).command("kill", "--signal="+name, info.ID)
+	if h.record(cmd) {
+		return nil
+	}
+	fmt.Fprintf(h.writer, "%s⚡ Sending %s to container %s...%s\n", h.colorCyan, name, info.Name, h.colorReset)
+
+	if output, err := h.run(cmd); err != nil {
+		return fmt.Errorf("failed to signal container: %w\nOutput: %s", err, string(output))
+	}
+
+	fmt.Fprintf(h.writer, "%s✓ %s sent to container %s%s\n", h.colorGreen, name, info.Name, h.colorReset)
+	return nil
+}
+
+// signalName returns the name of a common signal, or its number for others.
+func signalName(sig syscall.Signal) string {
+	for _, s := range action.CommonSignals {
+		if s.Signal == sig {
+			return s.Name
+		}
+	}
+	return strconv.Itoa(int(sig))
+}
+
+// promptSignal asks which signal to send to the container.
+// ok is false when the user cancels.
+func (h *ActionHandler) promptSignal() (sig syscall.Signal, ok bool) {
+	fmt.Fprintf(h.writer, "%sSelect signal to send:%s\n", h.colorBold, h.colorReset)
+	for i, s := range action.CommonSignals {
+		fmt.Fprintf(h.writer, "  [%d] %s\n", i+1, action.FormatSignalOption(s))
+	}
+	cancel := strconv.Itoa(len(action.CommonSignals) + 1)
+	fmt.Fprintf(h.writer, "  [%s] Cancel\n", cancel)
+	fmt.Fprintf(h.writer, "%sChoice [1]:%s ", h.colorBold, h.colorReset)
+
+	for {
+		if !h.scanner.Scan() {
+			return 0, false
+		}
+
+		choice := strings.TrimSpace(h.scanner.Text())
+
+		// Default to SIGTERM if empty
+		if choice == "" {
+			choice = "1"
+		}
+		if choice == cancel {
+			return 0, false
+		}
+
+		if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(action.CommonSignals) {
+			return action.CommonSignals[n-1].Signal, true
+		}
+
+		fmt.Fprintf(h.writer, "%sInvalid choice. Please enter 1-%s.%s\n", h.colorYellow, cancel, h.colorReset)
+		fmt.Fprintf(h.writer, "%sChoice [1]:%s ", h.colorBold, h.colorReset)
+	}
+}
+
+// promptLogOptions asks how many log lines to show and whether to follow the log.
+func (h *ActionHandler) promptLogOptions() (lines int, follow bool) {
+	lines = defaultLogLines
+	fmt.Fprintf(h.writer, "%sNumber of lines [%d]:%s ", h.colorBold, defaultLogLines, h.colorReset)
+	for h.scanner.Scan() {
+		input := strings.TrimSpace(h.scanner.Text())
+		if input == "" {
+			break
+		}
+		if n, err := strconv.Atoi(input); err == nil && n > 0 {
+			lines = n
+			break
+		}
+		fmt.Fprintf(h.writer, "%sPlease enter a positive number:%s ", h.colorYellow, h.colorReset)
+	}
+
+	fmt.Fprintf(h.writer, "%sFollow log output? [y/N]:%s ", h.colorBold, h.colorReset)
+	if h.scanner.Scan() {
+		response := strings.ToLower(strings.TrimSpace(h.scanner.Text()))
+		follow = response == "y" || response == "yes"
+	}
+	return lines, follow
+}
+
+// ShowLogs prints the last lines of the container log, optionally following it until Ctrl+C.
+func (h *ActionHandler) ShowLogs(info *ContainerInfo, lines int, follow bool) error {
+	args := []string{"logs", "--tail", strconv.Itoa(lines)}
+	if follow {
+		args = append(args, "--follow")
+	}
+	args = append(args, info.ID)
+
+	cmd := runtimeFor(info).command(args...)
+	if h.record(cmd) {
+		return nil
+	}
+	if follow {
+		fmt.Fprintf(h.writer, "%sFollowing logs of %s (Ctrl+C to stop)...%s\n", h.colorCyan, info.Name, h.colorReset)
+
+		// Ctrl+C should end the log stream, not whoseport
+		signal.Ignore(os.Interrupt)
+		defer signal.Reset(os.Interrupt)
+	}
+	cmd.Stdout = h.writer
+	cmd.Stderr = h.writer
+	if err := cmd.Run(); err != nil && !follow {
+		return fmt.Errorf("failed to read container logs: %w", err)
+	}
+	return nil
+}
+
+// execShell opens an interactive shell inside the container.
+func (h *ActionHandler) execShell(info *ContainerInfo) error {
+	cmd := runtimeFor(info).command("exec", "-it", info.ID, "sh")
+	if h.record(cmd) {
+		return nil
+	}
+	fmt.Fprintf(h.writer, "%s🐚 Opening shell in container %s (exit to return)...%s\n", h.colorCyan, info.Name, h.colorReset)
+
+	cmd.Stdin = h.reader
+	cmd.Stdout = h.writer
+	cmd.Stderr = h.writer
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to open shell in container: %w", err)
+	}
+	return nil
+}
+
+// podAction runs a pod-level command (podman pod stop/rm) for the container's pod.
+func (h *ActionHandler) podAction(info *ContainerInfo, progress, done string, args ...string) error {
+	if info.PodID == "" {
+		return fmt.Errorf("container %s is not part of a pod", info.Name)
+	}
+
+	pod := info.PodName
+	if pod == "" {
+		pod = info.PodID
+	}
+
+	cmdArgs := append([]string{"pod"}, args...)
+	cmdArgs = append(cmdArgs, info.PodID)
+	cmd := runtimeFor(info).command(cmdArgs...)
+	if h.record(cmd) {
+		return nil
+	}
+	fmt.Fprintf(h.writer, "%s%s pod %s...%s\n", h.colorCyan, progress, pod, h.colorReset)
+
+	if output, err := h.run(cmd); err != nil {
+		return fmt.Errorf("failed to %s pod: %w\nOutput: %s", args[0], err, string(output))
+	}
+
+	fmt.Fprintf(h.writer, "%s✓ Pod %s %s successfully%s\n", h.colorGreen, pod, done, h.colorReset)
+	return nil
+}
+
+// composeStop stops one service of the container's Compose project, or the whole project
+// when service is empty. Compose finds the project's containers by label, so the
+// compose file does not need to be present.
+func (h *ActionHandler) composeStop(info *ContainerInfo, service string) error {
+	if info.Compose == nil {
+		return fmt.Errorf("container %s is not part of a Compose project", info.Name)
+	}
+
+	target := "project " + info.Compose.Project
+	args := []string{"compose", "-p", info.Compose.Project, "stop"}
+	if service != "" {
+		target = "service " + service
+		args = append(args, service)
+	}
+
+	cmd := runtimeFor(info).command(args...)
+	if h.record(cmd) {
+		return nil
+	}
+	fmt.Fprintf(h.writer, "%s⏸  Stopping %s...%s\n", h.colorCyan, target, h.colorReset)
+
+	if output, err := h.run(cmd); err != nil {
+		return fmt.Errorf("failed to stop %s: %w\nOutput: %s", target, err, string(output))
+	}
+
+	fmt.Fprintf(h.writer, "%s✓ Compose %s stopped successfully%s\n", h.colorGreen, target, h.colorReset)
+	return nil
+}
+
+// PromptServiceAction prompts the user to select an action for a swarm service.
+// dockerd holds routing mesh ports for every service, so the service is acted on
+// instead of the process.
+func (h *ActionHandler) PromptServiceAction(info *ServiceInfo) Action {
+	var actions []Action
+	var labels []string
+	if !info.Global() {
+		actions = append(actions, ActionScaleService)
+		labels = append(labels, fmt.Sprintf("Scale service to zero (docker service scale %s=0)", info.Name))
+	}
+	actions = append(actions, ActionRemoveService, ActionCancel)
+	labels = append(labels, fmt.Sprintf("Remove service (docker service rm %s)", info.Name), "Cancel")
+	cancel := strconv.Itoa(len(actions))
+
+	fmt.Fprintf(h.writer, "%s%s🐝 Service %s - Select action:%s\n",
+		h.colorBold, h.colorYellow, info.Name, h.colorReset)
+	for i, label := range labels {
+		fmt.Fprintf(h.writer, "  [%d] %s\n", i+1, label)
+	}
+	fmt.Fprintf(h.writer, "%sChoice [%s]:%s ", h.colorBold, cancel, h.colorReset)
+
+	for {
+		if !h.scanner.Scan() {
+			return ActionCancel
+		}
+
+		choice := strings.TrimSpace(h.scanner.Text())
+
+		// Default to Cancel if empty
+		if choice == "" {
+			choice = cancel
+		}
+
+		if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(actions) {
+			return actions[n-1]
+		}
+
+		fmt.Fprintf(h.writer, "%sInvalid choice. Please enter 1-%s.%s\n", h.colorYellow, cancel, h.colorReset)
+		fmt.Fprintf(h.writer, "%sChoice [%s]:%s ", h.colorBold, cancel, h.colorReset)
+	}
+}
+
+// ExecuteServiceAction executes the selected swarm service action.
+func (h *ActionHandler) ExecuteServiceAction(action Action, info *ServiceInfo) error {
+	if err := h.authorize(action); err != nil {
+		return err
+	}
+
+	switch action {
+	case ActionScaleService:
+		if info.Global() {
+			return fmt.Errorf("service %s is global and cannot be scaled", info.Name)
+		}
+		return h.serviceAction(info, "⏸  Scaling to zero", "scaled to zero", "scale", info.Name+"=0")
+	case ActionRemoveService:
+		return h.serviceAction(info, "🗑  Removing", "removed", "rm", info.Name)
+	case ActionCancel:
+		return nil
+	default:
+		return fmt.Errorf("unknown action: %v", action)
+	}
+}
+
+// serviceAction runs a docker service subcommand for a swarm service.
+func (h *ActionHandler) serviceAction(info *ServiceInfo, progress, done string, args ...string) error {
+	cmd := info.runtime().command(append([]string{"service"}, args...)...)
+	if h.record(cmd) {
+		return nil
+	}
+	fmt.Fprintf(h.writer, "%s%s service %s...%s\n", h.colorCyan, progress, info.Name, h.colorReset)
+
+	if output, err := h.run(cmd); err != nil {
+		return fmt.Errorf("failed to %s service: %w\nOutput: %s", args[0], err, string(output))
+	}
+
+	fmt.Fprintf(h.writer, "%s✓ Service %s %s successfully%s\n", h.colorGreen, info.Name, done, h.colorReset)
 	return nil
 }
 
@@ -2,8 +2,13 @@ package docker
 
 import (
 	"bytes"
+	"errors"
+	"reflect"
 	"strings"
+	"syscall"
 	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/action"
 )
 
 func TestPromptAction(t *testing.T) {
@@ -28,8 +33,33 @@ func TestPromptAction(t *testing.T) {
 			expectedAction: ActionRemove,
 		},
 		{
-			name:           "choose cancel",
+			name:           "choose restart",
 			input:          "4\n",
+			expectedAction: ActionRestart,
+		},
+		{
+			name:           "choose kill with signal",
+			input:          "5\n",
+			expectedAction: ActionKill,
+		},
+		{
+			name:           "choose pause",
+			input:          "6\n",
+			expectedAction: ActionPause,
+		},
+		{
+			name:           "choose logs",
+			input:          "7\n",
+			expectedAction: ActionLogs,
+		},
+		{
+			name:           "choose exec shell",
+			input:          "8\n",
+			expectedAction: ActionExec,
+		},
+		{
+			name:           "choose cancel",
+			input:          "9\n",
 			expectedAction: ActionCancel,
 		},
 		{
@@ -39,7 +69,7 @@ func TestPromptAction(t *testing.T) {
 		},
 		{
 			name:           "invalid then valid choice",
-			input:          "5\n1\n",
+			input:          "10\n1\n",
 			expectedAction: ActionStop,
 		},
 	}
@@ -69,3 +99,195 @@ func TestPromptAction(t *testing.T) {
 		})
 	}
 }
+
+func TestPromptActionPausedContainer(t *testing.T) {
+	writer := &bytes.Buffer{}
+	handler := NewActionHandlerWithIO(strings.NewReader("6\n"), writer)
+	info := &ContainerInfo{Name: "web", ShortID: "abc123def456", State: "paused"}
+
+	if got := handler.PromptAction(info); got != ActionUnpause {
+		t.Errorf("PromptAction() = %v, want ActionUnpause", got)
+	}
+	if !strings.Contains(writer.String(), "docker unpause") {
+		t.Errorf("Output should offer unpause for a paused container, got: %s", writer.String())
+	}
+}
+
+func TestPromptActionStopTimeout(t *testing.T) {
+	writer := &bytes.Buffer{}
+	handler := NewActionHandlerWithIO(strings.NewReader("\n"), writer)
+	handler.SetStopTimeout(30)
+	handler.PromptAction(&ContainerInfo{Name: "web", ShortID: "abc123def456"})
+
+	if !strings.Contains(writer.String(), "Stop container (docker stop -t 30)") {
+		t.Errorf("Output should show the stop timeout, got: %s", writer.String())
+	}
+}
+
+func TestPromptSignal(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   syscall.Signal
+		wantOK bool
+	}{
+		{"\n", syscall.SIGTERM, true},
+		{"2\n", syscall.SIGKILL, true},
+		{"3\n", syscall.SIGHUP, true},
+		{"9\n4\n", syscall.SIGINT, true},
+		{"5\n", 0, false},
+	}
+
+	for _, tt := range tests {
+		handler := NewActionHandlerWithIO(strings.NewReader(tt.input), &bytes.Buffer{})
+		sig, ok := handler.promptSignal()
+		if sig != tt.want || ok != tt.wantOK {
+			t.Errorf("promptSignal(%q) = (%v, %v), want (%v, %v)", tt.input, sig, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestPromptLogOptions(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantLines  int
+		wantFollow bool
+	}{
+		{"\n\n", defaultLogLines, false},
+		{"50\ny\n", 50, true},
+		{"abc\n20\nn\n", 20, false},
+	}
+
+	for _, tt := range tests {
+		handler := NewActionHandlerWithIO(strings.NewReader(tt.input), &bytes.Buffer{})
+		lines, follow := handler.promptLogOptions()
+		if lines != tt.wantLines || follow != tt.wantFollow {
+			t.Errorf("promptLogOptions(%q) = (%d, %v), want (%d, %v)", tt.input, lines, follow, tt.wantLines, tt.wantFollow)
+		}
+	}
+}
+
+func TestGuardRefusesChanges(t *testing.T) {
+	refused := errors.New("container is protected")
+	info := &ContainerInfo{ID: "abc123", Name: "prod-db"}
+
+	tests := []struct {
+		action  Action
+		refused bool
+	}{
+		{ActionStop, true},
+		{ActionRemove, true},
+		{ActionRestart, true},
+		{ActionPause, true},
+		{ActionScaleService, true},
+		{ActionCancel, false},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+		handler := NewActionHandlerWithIO(strings.NewReader(""), &bytes.Buffer{})
+		handler.SetGuard(func() error {
+			calls++
+			return refused
+		})
+
+		err := handler.ExecuteAction(tt.action, info)
+		if tt.refused && !errors.Is(err, refused) {
+			t.Errorf("ExecuteAction(%v) = %v, want the guard's error", tt.action, err)
+		}
+		if !tt.refused && (err != nil || calls != 0) {
+			t.Errorf("ExecuteAction(%v) = %v after %d guard calls, want no check", tt.action, err, calls)
+		}
+	}
+
+	handler := NewActionHandlerWithIO(strings.NewReader(""), &bytes.Buffer{})
+	handler.SetGuard(func() error { return refused })
+	if err := handler.KillContainer(info, syscall.SIGKILL); !errors.Is(err, refused) {
+		t.Errorf("KillContainer() = %v, want the guard's error", err)
+	}
+}
+
+func TestConfirmTyped(t *testing.T) {
+	handler := NewActionHandlerWithIO(strings.NewReader("prod\nprod-db\n"), &bytes.Buffer{})
+	if handler.ConfirmTyped("prod-db") {
+		t.Error("ConfirmTyped() should decline a partial name")
+	}
+	if !handler.ConfirmTyped("prod-db") {
+		t.Error("ConfirmTyped() should accept the exact name")
+	}
+}
+
+func TestPlanRecordsCommands(t *testing.T) {
+	info := &ContainerInfo{ID: "abc123", Name: "web", Runtime: DockerRuntime.Name}
+
+	tests := []struct {
+		action Action
+		want   [][]string
+	}{
+		{ActionStop, [][]string{{"docker", "stop", "abc123"}}},
+		{ActionRestart, [][]string{{"docker", "restart", "abc123"}}},
+		{ActionPause, [][]string{{"docker", "pause", "abc123"}}},
+		{ActionExec, [][]string{{"docker", "exec", "-it", "abc123", "sh"}}},
+	}
+
+	for _, tt := range tests {
+		plan := &action.Plan{}
+		writer := &bytes.Buffer{}
+		handler := NewActionHandlerWithIO(strings.NewReader(""), writer)
+		handler.SetPlan(plan)
+
+		if err := handler.ExecuteAction(tt.action, info); err != nil {
+			t.Fatalf("ExecuteAction(%v) failed: %v", tt.action, err)
+		}
+		var got [][]string
+		for _, op := range plan.Operations {
+			got = append(got, op.Command)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExecuteAction(%v) recorded %v, want %v", tt.action, got, tt.want)
+		}
+		if writer.Len() != 0 {
+			t.Errorf("ExecuteAction(%v) should print nothing in a dry run, got: %s", tt.action, writer.String())
+		}
+	}
+}
+
+func TestPlanRecordsLogs(t *testing.T) {
+	info := &ContainerInfo{ID: "abc123", Name: "web", Runtime: DockerRuntime.Name}
+	plan := &action.Plan{}
+	writer := &bytes.Buffer{}
+	handler := NewActionHandlerWithIO(strings.NewReader(""), writer)
+	handler.SetPlan(plan)
+
+	if err := handler.ShowLogs(info, 50, true); err != nil {
+		t.Fatalf("ShowLogs() failed: %v", err)
+	}
+	want := []string{"docker", "logs", "--tail", "50", "--follow", "abc123"}
+	if len(plan.Operations) != 1 || !reflect.DeepEqual(plan.Operations[0].Command, want) {
+		t.Errorf("ShowLogs() recorded %+v, want %v", plan.Operations, want)
+	}
+	if writer.Len() != 0 {
+		t.Errorf("ShowLogs() should print nothing in a dry run, got: %s", writer.String())
+	}
+}
+
+func TestStopAndRemoveKeepsVolumes(t *testing.T) {
+	// The mount would make the prompt ask about volumes; -k must not ask
+	info := &ContainerInfo{ID: "abc123", Name: "web", Runtime: DockerRuntime.Name,
+		Mounts: []Mount{{Type: "volume", Name: strings.Repeat("f", 64), Destination: "/data"}}}
+	plan := &action.Plan{}
+	handler := NewActionHandlerWithIO(strings.NewReader(""), &bytes.Buffer{})
+	handler.SetPlan(plan)
+	handler.SetStopTimeout(30)
+
+	if err := handler.StopAndRemove(info); err != nil {
+		t.Fatalf("StopAndRemove() failed: %v", err)
+	}
+	var got [][]string
+	for _, op := range plan.Operations {
+		got = append(got, op.Command)
+	}
+	want := [][]string{{"docker", "stop", "-t", "30", "abc123"}, {"docker", "rm", "abc123"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StopAndRemove() recorded %v, want %v", got, want)
+	}
+}
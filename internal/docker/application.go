@@ -0,0 +1,54 @@
+package docker
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+	"github.com/bluehoodie/whoseport/internal/procfs"
+)
+
+// ResolveApplication finds the process listening on the container port that the host
+// port leads to, by reading the container's network namespace through its init PID.
+// info.App is left nil when the listener is the host process that was already found
+// (the port owner runs inside the container) or cannot be determined.
+func ResolveApplication(info *ContainerInfo, hostPort int) error {
+	info.ContainerPort = containerPortFor(info, hostPort)
+	if info.PID <= 0 || (info.Engine != nil && !info.Engine.Local) {
+		// The init PID of a container on a remote engine is not a local process
+		return nil
+	}
+
+	pid, err := procfs.FindListener(info.PID, info.ContainerPort)
+	if err != nil {
+		return err
+	}
+	if pid == info.ProcessID {
+		return nil
+	}
+
+	app := &model.ProcessInfo{ID: pid}
+	procfs.NewProcessEnhancer().Enhance(app)
+	if fields := strings.Fields(app.FullCommand); len(fields) > 0 {
+		app.Command = filepath.Base(fields[0])
+	}
+	info.App = app
+	return nil
+}
+
+// containerPortFor returns the container port a host port is published to.
+// Without a mapping (host networking, DNAT rule added elsewhere) the ports are the same.
+func containerPortFor(info *ContainerInfo, hostPort int) int {
+	if info.Forward != nil && info.Forward.DestPort > 0 {
+		return info.Forward.DestPort
+	}
+	for _, p := range info.Ports {
+		if p.HostPort == strconv.Itoa(hostPort) && p.Protocol != "udp" {
+			if port, err := strconv.Atoi(p.ContainerPort); err == nil {
+				return port
+			}
+		}
+	}
+	return hostPort
+}
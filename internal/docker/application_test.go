@@ -0,0 +1,35 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/netfilter"
+)
+
+func TestContainerPortFor(t *testing.T) {
+	ports := []PortMapping{
+		{HostIP: "0.0.0.0", HostPort: "5353", ContainerPort: "53", Protocol: "udp"},
+		{HostIP: "0.0.0.0", HostPort: "5353", ContainerPort: "5300", Protocol: "tcp"},
+		{HostIP: "0.0.0.0", HostPort: "8080", ContainerPort: "80", Protocol: "tcp"},
+	}
+
+	tests := []struct {
+		name string
+		info *ContainerInfo
+		port int
+		want int
+	}{
+		{"published mapping", &ContainerInfo{Ports: ports}, 8080, 80},
+		{"tcp mapping preferred over udp", &ContainerInfo{Ports: ports}, 5353, 5300},
+		{"host networking", &ContainerInfo{}, 9000, 9000},
+		{"netfilter rule", &ContainerInfo{Forward: &netfilter.Rule{HostPort: 7000, DestPort: 7001}}, 7000, 7001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containerPortFor(tt.info, tt.port); got != tt.want {
+				t.Errorf("containerPortFor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
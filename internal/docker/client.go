@@ -0,0 +1,297 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultDockerSocket is the Docker Engine socket used when DOCKER_HOST is unset.
+	defaultDockerSocket = "/var/run/docker.sock"
+
+	// clientTimeout bounds every Engine API request.
+	clientTimeout = 5 * time.Second
+)
+
+// Client is a minimal Docker Engine API client. It replaces forking the docker
+// CLI for the list and inspect calls that detection and retrieval need.
+type Client struct {
+	http    *http.Client
+	baseURL string
+	info    *apiInfo // Engine info, once fetched
+}
+
+// NewClient creates a client for the engine named by DOCKER_HOST, or the default socket.
+// It returns an error for hosts the client cannot reach directly (e.g. ssh://),
+// in which case callers fall back to the CLI.
+func NewClient() (*Client, error) {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = "unix://" + defaultDockerSocket
+	}
+	return NewClientForHost(host)
+}
+
+// NewClientForHost creates a client for a unix:// or tcp:// engine address.
+func NewClientForHost(host string) (*Client, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker host %q: %w", host, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		return NewClientWithSocket(u.Path), nil
+	case "tcp", "http":
+		return &Client{
+			http:    &http.Client{Timeout: clientTimeout},
+			baseURL: "http://" + u.Host,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported docker host scheme %q", u.Scheme)
+	}
+}
+
+// NewClientWithSocket creates a client that talks HTTP over the given unix socket.
+func NewClientWithSocket(socket string) *Client {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &Client{
+		http:    &http.Client{Transport: transport, Timeout: clientTimeout},
+		baseURL: "http://docker",
+	}
+}
+
+// Ping checks that the engine is reachable.
+func (c *Client) Ping() error {
+	body, err := c.get("/_ping")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(body)) != "OK" {
+		return fmt.Errorf("unexpected ping response: %s", body)
+	}
+	return nil
+}
+
+// apiInfo is the subset of /info used to describe the engine.
+type apiInfo struct {
+	ServerVersion   string   `json:"ServerVersion"`
+	SecurityOptions []string `json:"SecurityOptions"` // e.g. "name=rootless", "name=seccomp,profile=builtin"
+}
+
+// rootless reports whether the daemon runs in rootless mode.
+func (i *apiInfo) rootless() bool {
+	for _, option := range i.SecurityOptions {
+		if option == "name=rootless" {
+			return true
+		}
+	}
+	return false
+}
+
+// Info returns the engine's version and security options. The engine is asked once.
+func (c *Client) Info() (*apiInfo, error) {
+	if c.info != nil {
+		return c.info, nil
+	}
+	body, err := c.get("/info")
+	if err != nil {
+		return nil, err
+	}
+
+	var info apiInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode engine info: %w", err)
+	}
+	c.info = &info
+	return c.info, nil
+}
+
+// apiContainer is the subset of a /containers/json entry used for detection.
+type apiContainer struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	State  string            `json:"State"`
+	Labels map[string]string `json:"Labels"`
+	Ports  []struct {
+		IP          string `json:"IP"`
+		PrivatePort int    `json:"PrivatePort"`
+		PublicPort  int    `json:"PublicPort"`
+		Type        string `json:"Type"`
+	} `json:"Ports"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress string `json:"IPAddress"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+// ListContainers returns all running containers in a single call.
+func (c *Client) ListContainers() ([]apiContainer, error) {
+	return c.listContainers("/containers/json")
+}
+
+// ListContainersByLabel returns all containers, running or not, that carry a label ("key=value").
+func (c *Client) ListContainersByLabel(label string) ([]apiContainer, error) {
+	filters, err := json.Marshal(map[string][]string{"label": {label}})
+	if err != nil {
+		return nil, err
+	}
+	return c.listContainers("/containers/json?all=1&filters=" + url.QueryEscape(string(filters)))
+}
+
+// listContainers decodes a container list endpoint.
+func (c *Client) listContainers(path string) ([]apiContainer, error) {
+	body, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []apiContainer
+	if err := json.Unmarshal(body, &containers); err != nil {
+		return nil, fmt.Errorf("failed to decode container list: %w", err)
+	}
+	return containers, nil
+}
+
+// InspectContainer returns the inspect document of a container.
+func (c *Client) InspectContainer(id string) (*containerJSON, error) {
+	body, err := c.get("/containers/" + url.PathEscape(id) + "/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var container containerJSON
+	if err := json.Unmarshal(body, &container); err != nil {
+		return nil, fmt.Errorf("failed to decode inspect data: %w", err)
+	}
+	return &container, nil
+}
+
+// apiStats is the subset of a /containers/{id}/stats document used for resource usage.
+type apiStats struct {
+	Read     time.Time `json:"read"`
+	CPUStats struct {
+		CPUUsage struct {
+			TotalUsage  uint64   `json:"total_usage"`
+			PercpuUsage []uint64 `json:"percpu_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+		OnlineCPUs  int    `json:"online_cpus"`
+	} `json:"cpu_stats"`
+	PreCPUStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+	} `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+	Networks map[string]struct {
+		RxBytes uint64 `json:"rx_bytes"`
+		TxBytes uint64 `json:"tx_bytes"`
+	} `json:"networks"`
+	BlkioStats struct {
+		IOServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value uint64 `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	} `json:"blkio_stats"`
+	PidsStats struct {
+		Current int `json:"current"`
+	} `json:"pids_stats"`
+}
+
+// ContainerStats returns a single resource usage sample of a running container.
+// The engine takes two samples about a second apart so that CPU usage can be computed.
+func (c *Client) ContainerStats(id string) (*apiStats, error) {
+	body, err := c.get("/containers/" + url.PathEscape(id) + "/stats?stream=false")
+	if err != nil {
+		return nil, err
+	}
+
+	var stats apiStats
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode container stats: %w", err)
+	}
+	return &stats, nil
+}
+
+// get performs a GET request and returns the response body.
+func (c *Client) get(path string) ([]byte, error) {
+	resp, err := c.http.Get(c.baseURL + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("docker API %s: %s", path, apiErr.Message)
+		}
+		return nil, fmt.Errorf("docker API %s: %s", path, resp.Status)
+	}
+
+	return body, nil
+}
+
+// containerWithPort returns the first container publishing the given host port.
+func containerWithPort(containers []apiContainer, port int) string {
+	for _, c := range containers {
+		for _, p := range c.Ports {
+			if p.PublicPort == port {
+				return c.ID
+			}
+		}
+	}
+	return ""
+}
+
+// containerWithIP returns the first container with the given address on any network.
+func containerWithIP(containers []apiContainer, ip string) string {
+	for _, c := range containers {
+		for _, network := range c.NetworkSettings.Networks {
+			if network.IPAddress == ip {
+				return c.ID
+			}
+		}
+	}
+	return ""
+}
+
+// containerWithIDPrefix returns the container whose ID starts with prefix (e.g. a hostname).
+func containerWithIDPrefix(containers []apiContainer, prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+	for _, c := range containers {
+		if strings.HasPrefix(c.ID, prefix) {
+			return c.ID
+		}
+	}
+	return ""
+}
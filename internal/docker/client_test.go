@@ -0,0 +1,272 @@
+package docker
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/netfilter"
+)
+
+const fakeContainerList = `[
+  {"Id": "aaa111", "Names": ["/web"], "Ports": [{"IP": "0.0.0.0", "PrivatePort": 80, "PublicPort": 8080, "Type": "tcp"}],
+   "NetworkSettings": {"Networks": {"bridge": {"IPAddress": "172.17.0.2"}}}},
+  {"Id": "bbb222", "Names": ["/db"], "Ports": [{"PrivatePort": 5432, "Type": "tcp"}],
+   "NetworkSettings": {"Networks": {"bridge": {"IPAddress": "172.17.0.3"}}}}
+]`
+
+const fakeInspect = `{
+  "Id": "aaa111",
+  "Name": "/web",
+  "Image": "sha256:0123456789abcdef0123",
+  "Platform": "linux",
+  "Config": {"Image": "nginx:alpine", "Cmd": ["nginx", "-g", "daemon off;"], "Labels": {"tier": "frontend"}},
+  "State": {"Status": "running", "StartedAt": "2024-05-01T10:00:00.000000000Z"},
+  "HostConfig": {"RestartPolicy": {"Name": "always"}},
+  "NetworkSettings": {
+    "Ports": {"80/tcp": [{"HostIp": "0.0.0.0", "HostPort": "8080"}], "443/tcp": null},
+    "Networks": {"bridge": {"IPAddress": "172.17.0.2", "Gateway": "172.17.0.1", "MacAddress": "02:42:ac:11:00:02"}}
+  },
+  "Mounts": [{"Type": "volume", "Source": "/var/lib/docker/volumes/data", "Destination": "/data", "RW": false}]
+}`
+
+const fakeStats = `{
+  "read": "2024-05-01T10:00:01Z",
+  "cpu_stats": {"cpu_usage": {"total_usage": 2300000000}, "system_cpu_usage": 20000000000, "online_cpus": 4},
+  "precpu_stats": {"cpu_usage": {"total_usage": 2000000000}, "system_cpu_usage": 16000000000},
+  "memory_stats": {"usage": 15204352, "limit": 8235564810, "stats": {"inactive_file": 2097152}},
+  "networks": {"eth0": {"rx_bytes": 1000, "tx_bytes": 3000}, "eth1": {"rx_bytes": 200, "tx_bytes": 400}},
+  "blkio_stats": {"io_service_bytes_recursive": [{"op": "read", "value": 0}, {"op": "write", "value": 8192}]},
+  "pids_stats": {"current": 5}
+}`
+
+// newFakeEngine serves a fake Engine API on a temporary unix socket and returns a client for it.
+func newFakeEngine(t *testing.T) *Client {
+	t.Helper()
+	return NewClientWithSocket(newFakeEngineSocket(t))
+}
+
+// newFakeEngineSocket serves a fake Engine API on <temporary dir>/docker.sock and returns the socket path.
+func newFakeEngineSocket(t *testing.T) string {
+	t.Helper()
+
+	// unix socket paths are limited to ~100 bytes, so avoid the long t.TempDir() path
+	dir, err := os.MkdirTemp("", "whoseport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ServerVersion": "24.0.7", "SecurityOptions": ["name=seccomp,profile=builtin"]}`))
+	})
+	mux.HandleFunc("/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fakeContainerList))
+	})
+	mux.HandleFunc("/containers/aaa111/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fakeInspect))
+	})
+	mux.HandleFunc("/containers/aaa111/stats", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("stream") != "false" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(fakeStats))
+	})
+	mux.HandleFunc("/containers/missing/json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "No such container: missing"}`))
+	})
+
+	mux.HandleFunc("/services", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[" + fakeService + "]"))
+	})
+	mux.HandleFunc("/services/svc111", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fakeService))
+	})
+	mux.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fakeTasks))
+	})
+	mux.HandleFunc("/nodes", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"ID": "node1", "Description": {"Hostname": "manager-1"}}]`))
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return socket
+}
+
+func TestClient(t *testing.T) {
+	client := newFakeEngine(t)
+
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	containers, err := client.ListContainers()
+	if err != nil {
+		t.Fatalf("ListContainers() error = %v", err)
+	}
+	if len(containers) != 2 {
+		t.Fatalf("ListContainers() returned %d containers, want 2", len(containers))
+	}
+
+	first, err := client.Info()
+	if err != nil || first.ServerVersion != "24.0.7" {
+		t.Fatalf("Info() = (%+v, %v), want version 24.0.7", first, err)
+	}
+	if second, _ := client.Info(); second != first {
+		t.Error("Info() asked the engine again instead of reusing the first answer")
+	}
+
+	if _, err := client.InspectContainer("missing"); err == nil || err.Error() != "docker API /containers/missing/json: No such container: missing" {
+		t.Errorf("InspectContainer(missing) error = %v, want API message", err)
+	}
+}
+
+func TestDetectorWithClient(t *testing.T) {
+	detector := &DefaultDetector{runtime: DockerRuntime, client: newFakeEngine(t)}
+	detector.checkAvailability()
+
+	if !detector.available {
+		t.Fatal("detector not available with a reachable engine")
+	}
+	if got := detector.findContainerByPort(8080); got != "aaa111" {
+		t.Errorf("findContainerByPort(8080) = %v, want aaa111", got)
+	}
+	if got := detector.findContainerByPort(5432); got != "" {
+		t.Errorf("findContainerByPort(5432) = %v, want no match for unpublished port", got)
+	}
+	if got := detector.findContainerByIP("172.17.0.3"); got != "bbb222" {
+		t.Errorf("findContainerByIP() = %v, want bbb222", got)
+	}
+}
+
+func TestRetrieverWithClient(t *testing.T) {
+	retriever := &DefaultRetriever{runtime: DockerRuntime, client: newFakeEngine(t)}
+	info := &ContainerInfo{ID: "aaa111"}
+
+	if err := retriever.populateInspectData(info); err != nil {
+		t.Fatalf("populateInspectData() error = %v", err)
+	}
+
+	if info.Name != "web" || info.Image != "nginx:alpine" || info.State != "running" {
+		t.Errorf("identity = (%v, %v, %v), want (web, nginx:alpine, running)", info.Name, info.Image, info.State)
+	}
+	if info.ImageID != "0123456789ab" {
+		t.Errorf("ImageID = %v, want 0123456789ab", info.ImageID)
+	}
+	if info.PortString != "8080->80/tcp" {
+		t.Errorf("PortString = %v, want 8080->80/tcp", info.PortString)
+	}
+	if info.IPAddress != "172.17.0.2" || info.Gateway != "172.17.0.1" {
+		t.Errorf("network = (%v, %v), want (172.17.0.2, 172.17.0.1)", info.IPAddress, info.Gateway)
+	}
+	if len(info.Mounts) != 1 || info.Mounts[0].Mode != "ro" {
+		t.Errorf("Mounts = %+v, want one read-only mount", info.Mounts)
+	}
+	if info.RestartPolicy != "always" || info.Labels["tier"] != "frontend" {
+		t.Errorf("config = (%v, %v), want (always, frontend)", info.RestartPolicy, info.Labels["tier"])
+	}
+
+	if err := retriever.populateStatsData(info); err != nil {
+		t.Fatalf("populateStatsData() error = %v", err)
+	}
+	want := Stats{CPUPerc: 30, MemUsage: 13107200, MemLimit: 8235564810, MemPerc: 13107200.0 / 8235564810 * 100,
+		NetInput: 1200, NetOutput: 3400, BlockOutput: 8192, NumPIDs: 5, Timestamp: time.Date(2024, 5, 1, 10, 0, 1, 0, time.UTC)}
+	if info.Stats == nil || *info.Stats != want {
+		t.Errorf("Stats = %+v, want %+v", info.Stats, want)
+	}
+	if info.CPUPercent != "30.00%" || info.MemUsage != "12.5MiB / 7.67GiB" || info.NetIO != "1.2kB / 3.4kB" || info.BlockIO != "0B / 8.19kB" || info.PIDs != "5" {
+		t.Errorf("columns = (%v, %v, %v, %v, %v), want the docker stats format", info.CPUPercent, info.MemUsage, info.NetIO, info.BlockIO, info.PIDs)
+	}
+
+	if engine := retriever.engine(); engine.Version != "24.0.7" || engine.Rootless {
+		t.Errorf("engine() = %+v, want version 24.0.7", engine)
+	}
+}
+
+func TestNewClientForHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantURL string
+		wantErr bool
+	}{
+		{"unix:///var/run/docker.sock", "http://docker", false},
+		{"tcp://10.0.0.5:2375", "http://10.0.0.5:2375", false},
+		{"ssh://user@remote", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			client, err := NewClientForHost(tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClientForHost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && client.baseURL != tt.wantURL {
+				t.Errorf("baseURL = %v, want %v", client.baseURL, tt.wantURL)
+			}
+		})
+	}
+}
+
+// fakeNetfilter serves fixed DNAT rules for testing
+type fakeNetfilter []netfilter.Rule
+
+func (f fakeNetfilter) Lookup(port int) ([]netfilter.Rule, error) {
+	return netfilter.MatchPort(f, port), nil
+}
+
+func TestFindPublishedPort(t *testing.T) {
+	detector := &DefaultDetector{
+		runtime:   DockerRuntime,
+		available: true,
+		client:    newFakeEngine(t),
+		netfilter: fakeNetfilter{
+			{Source: "iptables", Table: "nat", Chain: "DOCKER", Protocol: "tcp", HostPort: 8080, DestIP: "172.17.0.2", DestPort: 80},
+			{Source: "iptables", Table: "nat", Chain: "CUSTOM", Protocol: "tcp", HostPort: 6000, DestIP: "172.17.0.3", DestPort: 5432},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		port      int
+		wantID    string
+		wantChain string
+	}{
+		{"published by engine with DNAT rule", 8080, "aaa111", "DOCKER"},
+		{"DNAT rule only", 6000, "bbb222", "CUSTOM"},
+		{"nothing", 7000, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, rule := detector.FindPublishedPort(tt.port)
+			if id != tt.wantID {
+				t.Errorf("FindPublishedPort() id = %v, want %v", id, tt.wantID)
+			}
+			chain := ""
+			if rule != nil {
+				chain = rule.Chain
+			}
+			if chain != tt.wantChain {
+				t.Errorf("FindPublishedPort() rule chain = %v, want %v", chain, tt.wantChain)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Labels Compose sets on every container it creates.
+const (
+	composeProjectLabel     = "com.docker.compose.project"
+	composeServiceLabel     = "com.docker.compose.service"
+	composeWorkingDirLabel  = "com.docker.compose.project.working_dir"
+	composeConfigFilesLabel = "com.docker.compose.project.config_files"
+)
+
+// ComposeInfo describes the Compose project a container belongs to.
+type ComposeInfo struct {
+	Project     string           `json:"project"`
+	Service     string           `json:"service"`
+	WorkingDir  string           `json:"working_dir,omitempty"`
+	ConfigFiles []string         `json:"config_files,omitempty"`
+	Siblings    []ComposeService `json:"siblings,omitempty"` // Other containers of the project
+}
+
+// ComposeService is a container of a Compose project.
+type ComposeService struct {
+	Service     string `json:"service"`
+	ContainerID string `json:"container_id"`
+	State       string `json:"state"`
+	Ports       string `json:"ports,omitempty"`
+}
+
+// composeFromLabels extracts Compose project information from container labels.
+// It returns nil for containers not started by Compose.
+func composeFromLabels(labels map[string]string) *ComposeInfo {
+	project := labels[composeProjectLabel]
+	if project == "" {
+		return nil
+	}
+
+	compose := &ComposeInfo{
+		Project:    project,
+		Service:    labels[composeServiceLabel],
+		WorkingDir: labels[composeWorkingDirLabel],
+	}
+	for _, file := range strings.Split(labels[composeConfigFilesLabel], ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			compose.ConfigFiles = append(compose.ConfigFiles, file)
+		}
+	}
+	return compose
+}
+
+// populateComposeSiblings lists the other containers of the project.
+func (r *DefaultRetriever) populateComposeSiblings(info *ContainerInfo) {
+	filter := composeProjectLabel + "=" + info.Compose.Project
+
+	var services []ComposeService
+	if containers, err := r.listByLabel(filter); err == nil {
+		services = composeServicesFromAPI(containers)
+	} else {
+		output, err := runtimeFor(info).command("ps", "-a", "--filter", "label="+filter, "--format", "json").Output()
+		if err != nil {
+			return
+		}
+		services = parseComposePs(output)
+	}
+
+	for _, s := range services {
+		if s.ContainerID != info.ID && !strings.HasPrefix(info.ID, s.ContainerID) {
+			info.Compose.Siblings = append(info.Compose.Siblings, s)
+		}
+	}
+}
+
+// listByLabel lists containers with a label through the Engine API.
+func (r *DefaultRetriever) listByLabel(label string) ([]apiContainer, error) {
+	if r.client == nil {
+		return nil, fmt.Errorf("engine API not available")
+	}
+	return r.client.ListContainersByLabel(label)
+}
+
+// composeServicesFromAPI converts an Engine API container list to Compose services.
+func composeServicesFromAPI(containers []apiContainer) []ComposeService {
+	services := make([]ComposeService, 0, len(containers))
+	for _, c := range containers {
+		ports := make([]PortMapping, 0, len(c.Ports))
+		seen := make(map[string]bool)
+		for _, p := range c.Ports {
+			// The engine lists IPv4 and IPv6 bindings of the same port separately
+			key := fmt.Sprintf("%d/%d/%s", p.PublicPort, p.PrivatePort, p.Type)
+			if p.PublicPort == 0 || seen[key] {
+				continue
+			}
+			seen[key] = true
+			ports = append(ports, PortMapping{
+				HostPort:      fmt.Sprintf("%d", p.PublicPort),
+				ContainerPort: fmt.Sprintf("%d", p.PrivatePort),
+				Protocol:      p.Type,
+			})
+		}
+		services = append(services, ComposeService{
+			Service:     c.Labels[composeServiceLabel],
+			ContainerID: c.ID,
+			State:       c.State,
+			Ports:       (&DefaultRetriever{}).formatPortString(ports),
+		})
+	}
+	sortComposeServices(services)
+	return services
+}
+
+// composePsEntry is a `ps --format json` entry. Docker and nerdctl print one object per
+// line with labels and ports as strings; Podman prints an array with structured values.
+type composePsEntry struct {
+	ID     string          `json:"ID"`
+	State  string          `json:"State"`
+	Labels json.RawMessage `json:"Labels"`
+	Ports  json.RawMessage `json:"Ports"`
+}
+
+// parseComposePs parses `ps --format json` output of any supported runtime.
+func parseComposePs(output []byte) []ComposeService {
+	var entries []composePsEntry
+	trimmed := strings.TrimSpace(string(output))
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &entries); err != nil {
+			return nil
+		}
+	} else {
+		for _, line := range strings.Split(trimmed, "\n") {
+			var entry composePsEntry
+			if line != "" && json.Unmarshal([]byte(line), &entry) == nil {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	services := make([]ComposeService, 0, len(entries))
+	for _, e := range entries {
+		services = append(services, ComposeService{
+			Service:     psLabels(e.Labels)[composeServiceLabel],
+			ContainerID: e.ID,
+			State:       e.State,
+			Ports:       psPorts(e.Ports),
+		})
+	}
+	sortComposeServices(services)
+	return services
+}
+
+// psLabels decodes labels printed either as a map or as "k=v,k=v".
+func psLabels(raw json.RawMessage) map[string]string {
+	labels := make(map[string]string)
+	if json.Unmarshal(raw, &labels) == nil {
+		return labels
+	}
+
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		return labels
+	}
+	for _, pair := range strings.Split(s, ",") {
+		if k, v, ok := strings.Cut(pair, "="); ok {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// psPorts decodes ports printed either as a docker-style string or as Podman port objects.
+func psPorts(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+
+	var c podmanContainer
+	if json.Unmarshal(raw, &c.Ports) != nil {
+		return ""
+	}
+	ports := make([]PortMapping, 0, len(c.Ports))
+	for _, p := range c.Ports {
+		ports = append(ports, PortMapping{
+			HostIP:        p.HostIP,
+			HostPort:      fmt.Sprintf("%d", p.HostPort),
+			ContainerPort: fmt.Sprintf("%d", p.ContainerPort),
+			Protocol:      p.Protocol,
+		})
+	}
+	return (&DefaultRetriever{}).formatPortString(ports)
+}
+
+// sortComposeServices orders services by name for stable display.
+func sortComposeServices(services []ComposeService) {
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].Service < services[j].Service
+	})
+}
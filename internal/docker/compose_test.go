@@ -0,0 +1,94 @@
+package docker
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComposeFromLabels(t *testing.T) {
+	got := composeFromLabels(map[string]string{
+		"com.docker.compose.project":              "shop",
+		"com.docker.compose.service":              "api",
+		"com.docker.compose.project.working_dir":  "/srv/shop",
+		"com.docker.compose.project.config_files": "/srv/shop/compose.yaml,/srv/shop/compose.override.yaml",
+	})
+
+	want := &ComposeInfo{
+		Project:     "shop",
+		Service:     "api",
+		WorkingDir:  "/srv/shop",
+		ConfigFiles: []string{"/srv/shop/compose.yaml", "/srv/shop/compose.override.yaml"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("composeFromLabels() = %+v, want %+v", got, want)
+	}
+
+	if got := composeFromLabels(map[string]string{"maintainer": "me"}); got != nil {
+		t.Errorf("composeFromLabels() = %+v, want nil for non-Compose container", got)
+	}
+}
+
+func TestParseComposePs(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []ComposeService
+	}{
+		{
+			name: "docker lines",
+			output: `{"ID":"bbb","Labels":"com.docker.compose.project=shop,com.docker.compose.service=db","Ports":"","State":"running"}
+{"ID":"aaa","Labels":"com.docker.compose.service=api,com.docker.compose.project=shop","Ports":"0.0.0.0:8080->80/tcp","State":"running"}`,
+			want: []ComposeService{
+				{Service: "api", ContainerID: "aaa", State: "running", Ports: "0.0.0.0:8080->80/tcp"},
+				{Service: "db", ContainerID: "bbb", State: "running"},
+			},
+		},
+		{
+			name:   "podman array",
+			output: `[{"Id":"ccc","Labels":{"com.docker.compose.service":"cache"},"Ports":[{"host_ip":"","container_port":6379,"host_port":6379,"range":1,"protocol":"tcp"}],"State":"exited"}]`,
+			want: []ComposeService{
+				{Service: "cache", ContainerID: "ccc", State: "exited", Ports: "6379->6379/tcp"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseComposePs([]byte(tt.output)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseComposePs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptActionComposeService(t *testing.T) {
+	info := &ContainerInfo{
+		Name:    "shop-api-1",
+		ShortID: "abc123def456",
+		Compose: &ComposeInfo{Project: "shop", Service: "api"},
+	}
+
+	tests := []struct {
+		input string
+		want  Action
+	}{
+		{"9\n", ActionStopService},
+		{"10\n", ActionStopProject},
+		{"11\n", ActionCancel},
+		{"\n", ActionCancel},
+	}
+
+	for _, tt := range tests {
+		writer := &bytes.Buffer{}
+		handler := NewActionHandlerWithIO(strings.NewReader(tt.input), writer)
+
+		if got := handler.PromptAction(info); got != tt.want {
+			t.Errorf("PromptAction(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if !strings.Contains(writer.String(), "docker compose -p shop stop api") {
+			t.Errorf("output should offer the service stop command, got: %s", writer.String())
+		}
+	}
+}
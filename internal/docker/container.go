@@ -1,21 +1,51 @@
 // Package docker provides Docker container detection and information retrieval.
+// Podman and containerd (via nerdctl) are supported through the same types via
+// the Runtime abstraction.
 package docker
 
-import "time"
+import (
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+	"github.com/bluehoodie/whoseport/internal/netfilter"
+)
 
 // ContainerInfo represents comprehensive information about a Docker container.
 type ContainerInfo struct {
 	// Container identification
-	ID         string `json:"id"`          // Full container ID
-	ShortID    string `json:"short_id"`    // Short container ID (first 12 chars)
-	Name       string `json:"name"`        // Container name
-	Image      string `json:"image"`       // Image name
-	ImageID    string `json:"image_id"`    // Image ID
-	Command    string `json:"command"`     // Command running in container
-	CreatedAt  string `json:"created_at"`  // Creation timestamp
-	RunningFor string `json:"running_for"` // Duration running
-	Status     string `json:"status"`      // Container status
-	State      string `json:"state"`       // Container state (running, exited, etc.)
+	ID         string  `json:"id"`                  // Full container ID
+	ShortID    string  `json:"short_id"`            // Short container ID (first 12 chars)
+	Name       string  `json:"name"`                // Container name
+	Image      string  `json:"image"`               // Image name
+	ImageID    string  `json:"image_id"`            // Image ID
+	Command    string  `json:"command"`             // Command running in container
+	CreatedAt  string  `json:"created_at"`          // Creation timestamp
+	RunningFor string  `json:"running_for"`         // Duration running
+	Status     string  `json:"status"`              // Container status
+	State      string  `json:"state"`               // Container state (running, exited, etc.)
+	PID        int     `json:"pid,omitempty"`       // Host PID of the container's init process
+	Runtime    string  `json:"runtime"`             // Container runtime (docker, podman, containerd)
+	Namespace  string  `json:"namespace,omitempty"` // containerd namespace
+	Engine     *Engine `json:"engine,omitempty"`    // Docker engine that answered
+
+	// State details
+	StartedAt    string  `json:"started_at,omitempty"`  // Last start timestamp
+	FinishedAt   string  `json:"finished_at,omitempty"` // Last exit timestamp
+	RestartCount int     `json:"restart_count"`         // Restarts by the engine's restart policy
+	OOMKilled    bool    `json:"oom_killed"`            // Last exit was caused by the OOM killer
+	ExitCode     int     `json:"exit_code"`             // Exit code of the last run
+	Error        string  `json:"error,omitempty"`       // Engine error of the last start, if any
+	Health       *Health `json:"health,omitempty"`      // Health check state, if configured
+
+	// Pod membership (Podman)
+	PodID   string `json:"pod_id,omitempty"`   // Pod ID
+	PodName string `json:"pod_name,omitempty"` // Pod name
+
+	// Compose project membership
+	Compose *ComposeInfo `json:"compose,omitempty"`
+
+	// Why the container is protected from being stopped (empty when it is not)
+	Protected []string `json:"protected,omitempty"`
 
 	// Port mappings
 	Ports      []PortMapping `json:"ports"`       // Port mappings
@@ -28,22 +58,66 @@ type ContainerInfo struct {
 	MacAddress string   `json:"mac_address"` // MAC address
 
 	// Resource usage
-	CPUPercent string `json:"cpu_percent"` // CPU usage percentage
-	MemUsage   string `json:"mem_usage"`   // Memory usage (e.g., "100MiB / 2GiB")
-	MemPercent string `json:"mem_percent"` // Memory usage percentage
-	NetIO      string `json:"net_io"`      // Network I/O
-	BlockIO    string `json:"block_io"`    // Block I/O
-	PIDs       string `json:"pids"`        // Number of PIDs
+	CPUPercent string `json:"cpu_percent"`     // CPU usage percentage
+	MemUsage   string `json:"mem_usage"`       // Memory usage (e.g., "100MiB / 2GiB")
+	MemPercent string `json:"mem_percent"`     // Memory usage percentage
+	NetIO      string `json:"net_io"`          // Network I/O
+	BlockIO    string `json:"block_io"`        // Block I/O
+	PIDs       string `json:"pids"`            // Number of PIDs
+	Stats      *Stats `json:"stats,omitempty"` // Parsed numeric values of the fields above
 
 	// Container configuration
+	Cmd           []string          `json:"cmd,omitempty"`  // Command and arguments given to the image (Config.Cmd)
 	Labels        map[string]string `json:"labels"`         // Container labels
 	Mounts        []Mount           `json:"mounts"`         // Volume mounts
+	Env           []string          `json:"-"`              // Environment (KEY=VALUE); may hold secrets, so not in JSON output
 	RestartPolicy string            `json:"restart_policy"` // Restart policy
 	Platform      string            `json:"platform"`       // Platform (linux/amd64, etc.)
 
+	// Port forwarding when no host process holds the port
+	Forward *netfilter.Rule `json:"forward,omitempty"` // DNAT rule forwarding the port, if found
+
 	// Process information (from original whoseport detection)
 	ProcessID  int    `json:"process_id"`  // The PID that led to container detection
 	ProcessCmd string `json:"process_cmd"` // The process command
+
+	// Application listening on the container port, when it differs from the process above
+	ContainerPort int                `json:"container_port,omitempty"` // Container port the host port leads to
+	App           *model.ProcessInfo `json:"app,omitempty"`            // Listener inside the container
+}
+
+// Health represents the state of a container's health check.
+type Health struct {
+	Status        string        `json:"status"`         // starting, healthy, unhealthy
+	FailingStreak int           `json:"failing_streak"` // Consecutive failed checks
+	Log           []HealthCheck `json:"log,omitempty"`  // Most recent check results (oldest first)
+}
+
+// HealthCheck is the result of a single health check run.
+type HealthCheck struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	ExitCode int    `json:"exit_code"`
+	Output   string `json:"output"`
+}
+
+// Crash loop heuristics: a container that restarted this often and whose current
+// run started within the window is considered crash-looping.
+const (
+	crashLoopRestarts = 3
+	crashLoopWindow   = 10 * time.Minute
+)
+
+// CrashLooping reports whether the container keeps exiting and being restarted.
+func (c *ContainerInfo) CrashLooping() bool {
+	if c.State == "restarting" {
+		return true
+	}
+	if c.RestartCount < crashLoopRestarts {
+		return false
+	}
+	started, err := time.Parse(time.RFC3339Nano, c.StartedAt)
+	return err == nil && time.Since(started) < crashLoopWindow
 }
 
 // PortMapping represents a Docker port mapping.
@@ -56,10 +130,11 @@ type PortMapping struct {
 
 // Mount represents a Docker volume mount.
 type Mount struct {
-	Type        string `json:"type"`        // bind, volume, tmpfs
-	Source      string `json:"source"`      // Source path/volume
-	Destination string `json:"destination"` // Destination in container
-	Mode        string `json:"mode"`        // Mount mode (rw, ro)
+	Type        string `json:"type"`           // bind, volume, tmpfs
+	Name        string `json:"name,omitempty"` // Volume name (volumes only)
+	Source      string `json:"source"`         // Source path/volume
+	Destination string `json:"destination"`    // Destination in container
+	Mode        string `json:"mode"`           // Mount mode (rw, ro)
 }
 
 // Stats represents real-time container statistics.
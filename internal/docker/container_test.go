@@ -0,0 +1,31 @@
+package docker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCrashLooping(t *testing.T) {
+	recent := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano)
+	old := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339Nano)
+
+	tests := []struct {
+		name string
+		info ContainerInfo
+		want bool
+	}{
+		{"restarting state", ContainerInfo{State: "restarting"}, true},
+		{"many restarts, recently started", ContainerInfo{State: "running", RestartCount: 5, StartedAt: recent}, true},
+		{"many restarts, stable since", ContainerInfo{State: "running", RestartCount: 5, StartedAt: old}, false},
+		{"few restarts", ContainerInfo{State: "running", RestartCount: 1, StartedAt: recent}, false},
+		{"unparseable start time", ContainerInfo{State: "running", RestartCount: 5, StartedAt: "unknown"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.CrashLooping(); got != tt.want {
+				t.Errorf("CrashLooping() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+const (
+	// defaultContainerdNamespace is the namespace nerdctl and ctr use when none is given.
+	defaultContainerdNamespace = "default"
+
+	// kubernetesContainerdNamespace holds CRI containers, which are left to the kubernetes package.
+	kubernetesContainerdNamespace = "k8s.io"
+
+	// maxShimDepth limits how far up the parent chain a containerd shim is searched for.
+	maxShimDepth = 8
+)
+
+// shimContainer extracts the namespace and container ID from a containerd shim command line,
+// e.g. "containerd-shim-runc-v2 -namespace default -id <id> -address /run/containerd/containerd.sock".
+func shimContainer(cmdline string) (namespace, id string) {
+	fields := strings.Fields(cmdline)
+	if len(fields) == 0 || !strings.HasPrefix(filepath.Base(fields[0]), "containerd-shim") {
+		return "", ""
+	}
+	for i := 1; i < len(fields); i++ {
+		flag, value, hasValue := strings.Cut(strings.TrimLeft(fields[i], "-"), "=")
+		if !hasValue && i+1 < len(fields) {
+			value = fields[i+1]
+		}
+		switch flag {
+		case "namespace":
+			namespace = value
+		case "id":
+			id = value
+		}
+	}
+	return namespace, id
+}
+
+// isContainerdPortHolder checks whether the process holds ports for containerd containers.
+// Rootless nerdctl publishes ports from rootlesskit's port driver.
+func isContainerdPortHolder(info *model.ProcessInfo) bool {
+	name := strings.ToLower(info.Command)
+	return strings.HasPrefix(name, "containerd-shim") || strings.HasPrefix(name, "rootlesskit")
+}
+
+// findContainerdContainer maps a process to a containerd container through its shim:
+// the port owner itself, or the nearest containerd-shim in its parent chain.
+func (d *DefaultDetector) findContainerdContainer(info *model.ProcessInfo, port int) string {
+	if namespace, id := shimContainer(info.FullCommand); id != "" {
+		if namespace == kubernetesContainerdNamespace {
+			return ""
+		}
+		return id
+	}
+
+	pid := info.PPid
+	for depth := 0; depth < maxShimDepth && pid > 1; depth++ {
+		cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+		if err != nil {
+			break
+		}
+		if namespace, id := shimContainer(strings.ReplaceAll(string(cmdline), "\x00", " ")); id != "" {
+			if namespace == kubernetesContainerdNamespace {
+				return ""
+			}
+			return id
+		}
+		if pid, err = parentPID(pid); err != nil {
+			break
+		}
+	}
+
+	// rootlesskit serves every published port: match the port instead
+	if isContainerdPortHolder(info) {
+		return d.findContainerByPort(port)
+	}
+	return ""
+}
+
+// parentPID reads the parent PID from /proc/{pid}/stat.
+func parentPID(pid int) (int, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	// The command name may contain spaces, so skip past its closing parenthesis
+	stat := string(data)
+	end := strings.LastIndex(stat, ")")
+	if end < 0 {
+		return 0, fmt.Errorf("malformed stat for PID %d", pid)
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("malformed stat for PID %d", pid)
+	}
+	return strconv.Atoi(fields[1])
+}
+
+// findContainerdContainerByPort searches every containerd namespace for a published host port.
+func (d *DefaultDetector) findContainerdContainerByPort(port int) string {
+	for _, namespace := range containerdNamespaces(d.runtime) {
+		if id := d.findContainerByPortIn(d.runtime.WithNamespace(namespace), port); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// containerdNamespaces lists containerd namespaces via nerdctl, falling back to ctr.
+// The Kubernetes namespace is skipped.
+func containerdNamespaces(runtime Runtime) []string {
+	output, err := runtime.WithNamespace("").command("namespace", "ls", "-q").Output()
+	if err != nil {
+		output, err = exec.Command("ctr", "namespaces", "ls", "-q").Output()
+	}
+	if err != nil {
+		return []string{defaultContainerdNamespace}
+	}
+	return parseNamespaces(string(output))
+}
+
+// parseNamespaces parses `namespace ls -q` output, dropping the Kubernetes namespace.
+func parseNamespaces(output string) []string {
+	var namespaces []string
+	for _, line := range strings.Split(output, "\n") {
+		namespace := strings.TrimSpace(line)
+		if namespace == "" || namespace == kubernetesContainerdNamespace {
+			continue
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	if len(namespaces) == 0 {
+		return []string{defaultContainerdNamespace}
+	}
+	return namespaces
+}
+
+// populateContainerdData finds the namespace holding the container and inspects it,
+// preferring nerdctl's docker-compatible inspect and falling back to ctr.
+func (r *DefaultRetriever) populateContainerdData(info *ContainerInfo) error {
+	namespaces := containerdNamespaces(r.runtime)
+
+	for _, namespace := range namespaces {
+		scoped := &DefaultRetriever{runtime: r.runtime.WithNamespace(namespace)}
+		if err := scoped.populateInspectData(info); err == nil {
+			info.Namespace = namespace
+			return nil
+		}
+	}
+
+	for _, namespace := range namespaces {
+		output, err := exec.Command("ctr", "--namespace", namespace, "containers", "info", info.ID).Output()
+		if err != nil {
+			continue
+		}
+		if err := applyCtrInfo(info, output); err != nil {
+			return err
+		}
+		info.Namespace = namespace
+		return nil
+	}
+
+	return fmt.Errorf("container %s not found in containerd namespaces %s", info.ShortID, strings.Join(namespaces, ", "))
+}
+
+// ctrContainer is the subset of `ctr containers info` output used for display.
+type ctrContainer struct {
+	ID        string            `json:"ID"`
+	Labels    map[string]string `json:"Labels"`
+	Image     string            `json:"Image"`
+	CreatedAt string            `json:"CreatedAt"`
+	Spec      struct {
+		Process struct {
+			Args []string `json:"args"`
+		} `json:"process"`
+	} `json:"Spec"`
+}
+
+// nerdctlPort is an entry of the nerdctl/ports label nerdctl stores on its containers.
+type nerdctlPort struct {
+	HostIP        string `json:"HostIP"`
+	HostPort      int    `json:"HostPort"`
+	ContainerPort int    `json:"ContainerPort"`
+	Protocol      string `json:"Protocol"`
+}
+
+// applyCtrInfo fills container details from `ctr containers info` JSON.
+// nerdctl records the container name and published ports as labels.
+func applyCtrInfo(info *ContainerInfo, output []byte) error {
+	var c ctrContainer
+	if err := json.Unmarshal(output, &c); err != nil {
+		return err
+	}
+
+	info.Name = c.Labels["nerdctl/name"]
+	if info.Name == "" {
+		info.Name = info.ShortID
+	}
+	info.Image = c.Image
+	info.CreatedAt = c.CreatedAt
+	info.Command = strings.Join(c.Spec.Process.Args, " ")
+	info.Labels = c.Labels
+
+	var ports []nerdctlPort
+	if raw := c.Labels["nerdctl/ports"]; raw != "" && json.Unmarshal([]byte(raw), &ports) == nil {
+		for _, p := range ports {
+			info.Ports = append(info.Ports, PortMapping{
+				HostIP:        p.HostIP,
+				HostPort:      strconv.Itoa(p.HostPort),
+				ContainerPort: strconv.Itoa(p.ContainerPort),
+				Protocol:      p.Protocol,
+			})
+		}
+		info.PortString = (&DefaultRetriever{}).formatPortString(info.Ports)
+	}
+
+	return nil
+}
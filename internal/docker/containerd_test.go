@@ -0,0 +1,118 @@
+package docker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShimContainer(t *testing.T) {
+	id := strings.Repeat("ab12", 16)
+
+	tests := []struct {
+		name          string
+		cmdline       string
+		wantNamespace string
+		wantID        string
+	}{
+		{"runc v2 shim", "/usr/local/bin/containerd-shim-runc-v2 -namespace default -id " + id + " -address /run/containerd/containerd.sock", "default", id},
+		{"double dash flags", "containerd-shim-runc-v2 --namespace=buildkit --id=" + id, "buildkit", id},
+		{"kubernetes shim", "containerd-shim-runc-v2 -namespace k8s.io -id " + id, "k8s.io", id},
+		{"not a shim", "/usr/bin/nginx -id " + id, "", ""},
+		{"empty", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace, id := shimContainer(tt.cmdline)
+			if namespace != tt.wantNamespace || id != tt.wantID {
+				t.Errorf("shimContainer() = (%v, %v), want (%v, %v)", namespace, id, tt.wantNamespace, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestContainerdCgroupPattern(t *testing.T) {
+	id := strings.Repeat("9f", 32)
+
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"cgroupfs v1", "4:memory:/default/" + id, id},
+		{"cgroupfs v2", "0::/buildkit/" + id, id},
+		{"systemd scope", "0::/system.slice/nerdctl-" + id + ".scope", id},
+		{"kubernetes pod", "0::/kubepods/besteffort/pod1234/" + id, ""},
+		{"host process", "0::/user.slice/user-1000.slice/session-2.scope", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if m := ContainerdRuntime.cgroupPattern.FindStringSubmatch(tt.line); len(m) >= 2 {
+				got = m[1]
+			}
+			if got != tt.want {
+				t.Errorf("cgroupPattern match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNamespaces(t *testing.T) {
+	got := parseNamespaces("buildkit\ndefault\nk8s.io\n\n")
+	want := []string{"buildkit", "default"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNamespaces() = %v, want %v", got, want)
+	}
+
+	got = parseNamespaces("k8s.io\n")
+	if !reflect.DeepEqual(got, []string{"default"}) {
+		t.Errorf("parseNamespaces() = %v, want [default]", got)
+	}
+}
+
+func TestApplyCtrInfo(t *testing.T) {
+	output := []byte(`{
+  "ID": "9f9f",
+  "Labels": {
+    "nerdctl/name": "web",
+    "nerdctl/ports": "[{\"HostPort\":8080,\"ContainerPort\":80,\"Protocol\":\"tcp\",\"HostIP\":\"0.0.0.0\"}]"
+  },
+  "Image": "docker.io/library/nginx:alpine",
+  "CreatedAt": "2024-05-01T10:00:00Z",
+  "Spec": {"process": {"args": ["nginx", "-g", "daemon off;"]}}
+}`)
+
+	info := &ContainerInfo{ID: "9f9f", ShortID: "9f9f"}
+	if err := applyCtrInfo(info, output); err != nil {
+		t.Fatalf("applyCtrInfo() error = %v", err)
+	}
+
+	if info.Name != "web" {
+		t.Errorf("Name = %v, want web", info.Name)
+	}
+	if info.Image != "docker.io/library/nginx:alpine" {
+		t.Errorf("Image = %v, want docker.io/library/nginx:alpine", info.Image)
+	}
+	if info.Command != "nginx -g daemon off;" {
+		t.Errorf("Command = %v, want nginx -g daemon off;", info.Command)
+	}
+	if info.PortString != "8080->80/tcp" {
+		t.Errorf("PortString = %v, want 8080->80/tcp", info.PortString)
+	}
+}
+
+func TestRuntimeCommandNamespace(t *testing.T) {
+	cmd := ContainerdRuntime.WithNamespace("buildkit").command("stop", "abc")
+	want := []string{"nerdctl", "--namespace", "buildkit", "stop", "abc"}
+	if !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("command() args = %v, want %v", cmd.Args, want)
+	}
+
+	cmd = DockerRuntime.command("stop", "abc")
+	if !reflect.DeepEqual(cmd.Args, []string{"docker", "stop", "abc"}) {
+		t.Errorf("command() args = %v, want docker stop abc", cmd.Args)
+	}
+}
@@ -5,66 +5,152 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/bluehoodie/whoseport/internal/model"
+	"github.com/bluehoodie/whoseport/internal/netfilter"
 )
 
-// Detector identifies if a process is related to a Docker container.
+// Detector identifies if a process is related to a Docker (or Podman) container.
 type Detector interface {
-	// IsDockerRelated checks if the given process is Docker-related
+	// IsDockerRelated checks if the given process is related to a container of the detector's runtime
 	// Returns true and container ID if it's a Docker container process
 	// port parameter is used to find containers by port mapping (useful for Docker Desktop on macOS)
 	IsDockerRelated(info *model.ProcessInfo, port int) (bool, string, error)
+
+	// FindPublishedPort finds the container publishing a port that no host process holds
+	// (no userland proxy, rootless networking, Docker Desktop). The returned rule is the
+	// netfilter DNAT rule forwarding the port, or nil if none was found.
+	FindPublishedPort(port int) (string, *netfilter.Rule)
+
+	// FindService finds the swarm service publishing a port through the routing mesh,
+	// where dockerd itself holds the port. info is nil when no host process holds it.
+	FindService(info *model.ProcessInfo, port int) string
+
+	// Runtime returns the runtime the detector talks to. It may differ from the
+	// runtime the detector was created for when a container was found on the
+	// rootless daemon of the user holding the port.
+	Runtime() Runtime
 }
 
-// DefaultDetector implements Docker detection logic.
+// DefaultDetector implements container detection logic for a runtime.
 type DefaultDetector struct {
-	dockerAvailable bool
+	runtime    Runtime
+	available  bool
+	remote     bool             // Engine runs elsewhere; local PIDs say nothing about its containers
+	client     *Client          // Engine API client; nil means CLI only
+	containers []apiContainer   // Container list fetched once per detector
+	netfilter  netfilter.Finder // NAT rule lookup for ports without a host process
 }
 
 // NewDetector creates a new Docker detector.
 func NewDetector() Detector {
-	detector := &DefaultDetector{}
-	detector.checkDockerAvailability()
+	return NewDetectorForRuntime(DockerRuntime)
+}
+
+// NewDetectorForRuntime creates a detector for the given container runtime.
+func NewDetectorForRuntime(runtime Runtime) Detector {
+	detector := &DefaultDetector{runtime: runtime, client: runtime.apiClient(), remote: !runtime.local()}
+	if !detector.remote {
+		detector.netfilter = netfilter.NewFinder()
+	}
+	detector.checkAvailability()
 	return detector
 }
 
-// checkDockerAvailability verifies if Docker CLI is available.
-func (d *DefaultDetector) checkDockerAvailability() {
-	cmd := exec.Command("docker", "version", "--format", "{{.Server.Version}}")
-	err := cmd.Run()
-	d.dockerAvailable = err == nil
+// checkAvailability verifies if the engine API or the runtime CLI is available.
+func (d *DefaultDetector) checkAvailability() {
+	// /info answers like /_ping and also tells the retriever the engine version
+	if d.client != nil {
+		if _, err := d.client.Info(); err == nil {
+			d.available = true
+			return
+		}
+	}
+	d.client = nil
+	d.available = d.runtime.available()
+}
+
+// listContainers returns the running containers from the Engine API, fetching them once.
+// ok is false when the API is not in use and callers should fall back to the CLI.
+func (d *DefaultDetector) listContainers() (containers []apiContainer, ok bool) {
+	if d.client == nil {
+		return nil, false
+	}
+	if d.containers == nil {
+		list, err := d.client.ListContainers()
+		if err != nil {
+			return nil, false
+		}
+		d.containers = list
+	}
+	return d.containers, true
+}
+
+// Runtime returns the runtime the detector talks to.
+func (d *DefaultDetector) Runtime() Runtime {
+	return d.runtime
 }
 
-// IsDockerRelated determines if a process is Docker-related and returns the container ID.
+// IsDockerRelated determines if a process is container-related and returns the container ID.
 func (d *DefaultDetector) IsDockerRelated(info *model.ProcessInfo, port int) (bool, string, error) {
-	if !d.dockerAvailable {
+	// Strategy 0: rootless Docker and Docker Desktop port holders (rootlesskit, rootlessport,
+	// slirp4netns, com.docker.backend, vpnkit). The rootless daemon has its own socket, so
+	// this runs even when the default engine is unavailable.
+	if d.runtime.Name == DockerRuntime.Name && !d.remote &&
+		(hasHolderPrefix(info, rootlessPortHolders) || hasHolderPrefix(info, desktopPortHolders)) {
+		if containerID := d.findRootlessContainer(info, port); containerID != "" {
+			return true, containerID, nil
+		}
+	}
+
+	if !d.available {
 		return false, "", nil
 	}
 
-	// Strategy 1: If process name contains "docker", query Docker for containers using this port
+	// Strategy 1: If process name contains the engine name, query the engine for containers using this port
 	// This works on both Linux and macOS (including Docker Desktop where com.docker.backend handles ports)
-	if strings.Contains(strings.ToLower(info.Command), "docker") ||
-		strings.Contains(strings.ToLower(info.FullCommand), "docker") {
+	if strings.Contains(strings.ToLower(info.Command), d.runtime.Name) ||
+		strings.Contains(strings.ToLower(info.FullCommand), d.runtime.Name) {
 		containerID := d.findContainerByPort(port)
 		if containerID != "" {
 			return true, containerID, nil
 		}
 	}
 
+	// The remaining strategies map a local PID to a container, which only works
+	// when the engine runs on this machine
+	if d.remote {
+		return false, "", nil
+	}
+
 	// Strategy 2: Check if the process is docker-proxy (Linux)
 	// docker-proxy is the process that forwards ports from host to container
-	if strings.Contains(info.Command, "docker-proxy") {
+	if d.runtime.Name == DockerRuntime.Name && strings.Contains(info.Command, "docker-proxy") {
 		containerID := d.findContainerFromDockerProxy(info)
 		if containerID != "" {
 			return true, containerID, nil
 		}
 	}
 
+	// Strategy 2b: Check if the process is a Podman port holder (conmon, rootlessport, pasta, slirp4netns)
+	if d.runtime.Name == PodmanRuntime.Name && isPodmanPortHolder(info) {
+		containerID := d.findPodmanContainer(info, port)
+		if containerID != "" {
+			return true, containerID, nil
+		}
+	}
+
+	// Strategy 2c: Check for containerd shims and rootlesskit (containerd via nerdctl)
+	if d.runtime.Name == ContainerdRuntime.Name {
+		containerID := d.findContainerdContainer(info, port)
+		if containerID != "" {
+			return true, containerID, nil
+		}
+	}
+
 	// Strategy 3: Check if the process is running inside a container via cgroup (Linux)
 	containerID, err := d.checkCgroupForContainer(info.ID)
 	if err == nil && containerID != "" {
@@ -80,11 +166,70 @@ func (d *DefaultDetector) IsDockerRelated(info *model.ProcessInfo, port int) (bo
 	return false, "", nil
 }
 
+// FindPublishedPort searches the engine's published ports and the netfilter NAT rules for the port.
+func (d *DefaultDetector) FindPublishedPort(port int) (string, *netfilter.Rule) {
+	if !d.available {
+		return "", nil
+	}
+
+	var rules []netfilter.Rule
+	if d.netfilter != nil {
+		rules, _ = d.netfilter.Lookup(port)
+	}
+
+	if containerID := d.findContainerByPort(port); containerID != "" {
+		// Report the forwarding rule that leads to this container, if any
+		for i := range rules {
+			if sameContainer(d.findContainerByIP(rules[i].DestIP), containerID) {
+				return containerID, &rules[i]
+			}
+		}
+		return containerID, nil
+	}
+
+	// The engine does not list the port (e.g. a rule added outside of it): match the DNAT target
+	for i := range rules {
+		if containerID := d.findContainerByIP(rules[i].DestIP); containerID != "" {
+			return containerID, &rules[i]
+		}
+	}
+
+	return "", nil
+}
+
+// sameContainer compares container IDs that may be full or abbreviated.
+func sameContainer(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
 // findContainerByPort finds a container by checking which container has a port mapping to the given host port.
 // This works on both Linux and macOS Docker Desktop.
 func (d *DefaultDetector) findContainerByPort(port int) string {
+	// podman prints a JSON array with structured ports instead of one object per line
+	if d.runtime.Name == PodmanRuntime.Name {
+		return d.findPodmanContainerByPort(port)
+	}
+
+	// containerd keeps containers in namespaces that nerdctl lists one at a time
+	if d.runtime.Name == ContainerdRuntime.Name {
+		return d.findContainerdContainerByPort(port)
+	}
+
+	if containers, ok := d.listContainers(); ok {
+		return containerWithPort(containers, port)
+	}
+
+	return d.findContainerByPortIn(d.runtime, port)
+}
+
+// findContainerByPortIn searches the docker-compatible `ps --format json` output
+// (one object per line) of the given runtime for a published host port.
+func (d *DefaultDetector) findContainerByPortIn(runtime Runtime, port int) string {
 	// Use docker ps with format to get container IDs and ports
-	cmd := exec.Command("docker", "ps", "--format", "json")
+	cmd := runtime.command("ps", "--format", "json")
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -204,8 +349,12 @@ func (d *DefaultDetector) extractContainerIPFromProxy(info *model.ProcessInfo) s
 
 // findContainerByIP finds a container by its IP address.
 func (d *DefaultDetector) findContainerByIP(ip string) string {
+	if containers, ok := d.listContainers(); ok {
+		return containerWithIP(containers, ip)
+	}
+
 	// Use docker inspect to find container with this IP
-	cmd := exec.Command("docker", "ps", "-q")
+	cmd := d.runtime.command("ps", "-q")
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -218,7 +367,7 @@ func (d *DefaultDetector) findContainerByIP(ip string) string {
 		}
 
 		// Check if this container has the matching IP
-		cmd := exec.Command("docker", "inspect", "-f", "{{range .NetworkSettings.Networks}}{{.IPAddress}}{{end}}", containerID)
+		cmd := d.runtime.command("inspect", "-f", "{{range .NetworkSettings.Networks}}{{.IPAddress}}{{end}}", containerID)
 		output, err := cmd.Output()
 		if err != nil {
 			continue
@@ -247,12 +396,14 @@ func (d *DefaultDetector) checkCgroupForContainer(pid int) (string, error) {
 	// 12:memory:/docker/8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f
 	// or
 	// 12:memory:/system.slice/docker-8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f.scope
-	dockerPattern := regexp.MustCompile(`/docker[/-]([a-f0-9]{64})`)
+	// Podman uses libpod-<id>.scope (and libpod-conmon-<id>.scope for conmon)
+	// containerd uses /<namespace>/<id> or nerdctl-<id>.scope
+	pattern := d.runtime.cgroupPattern
 
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
-		matches := dockerPattern.FindStringSubmatch(line)
+		matches := pattern.FindStringSubmatch(line)
 		if len(matches) >= 2 {
 			return matches[1], nil
 		}
@@ -287,7 +438,11 @@ func (d *DefaultDetector) checkEnvironForContainer(pid int) (string, error) {
 	}
 
 	// Try to find a container with matching hostname (short ID)
-	cmd := exec.Command("docker", "ps", "-q", "--filter", fmt.Sprintf("id=%s", hostname))
+	if containers, ok := d.listContainers(); ok {
+		return containerWithIDPrefix(containers, hostname), nil
+	}
+
+	cmd := d.runtime.command("ps", "-q", "--filter", fmt.Sprintf("id=%s", hostname))
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
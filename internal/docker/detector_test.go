@@ -7,7 +7,7 @@ import (
 )
 
 func TestExtractContainerIPFromProxy(t *testing.T) {
-	detector := &DefaultDetector{dockerAvailable: true}
+	detector := &DefaultDetector{runtime: DockerRuntime, available: true}
 
 	tests := []struct {
 		name        string
@@ -52,7 +52,7 @@ func TestExtractContainerIPFromProxy(t *testing.T) {
 }
 
 func TestCheckCgroupForContainer(t *testing.T) {
-	detector := &DefaultDetector{dockerAvailable: true}
+	detector := &DefaultDetector{runtime: DockerRuntime, available: true}
 
 	// Note: This test will only work on systems with /proc filesystem
 	// For a process running in Docker, we expect to find a container ID
@@ -66,7 +66,7 @@ func TestCheckCgroupForContainer(t *testing.T) {
 }
 
 func TestContainerHasPort(t *testing.T) {
-	detector := &DefaultDetector{dockerAvailable: true}
+	detector := &DefaultDetector{runtime: DockerRuntime, available: true}
 
 	tests := []struct {
 		name     string
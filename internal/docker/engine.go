@@ -0,0 +1,253 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// availabilityTTL is how long an engine availability check is reused.
+const availabilityTTL = 5 * time.Minute
+
+// Engine identifies the Docker engine that answered: its context, its address and
+// whether it runs on this machine.
+type Engine struct {
+	Context  string `json:"context,omitempty"`  // docker context name (empty for the default context)
+	Host     string `json:"host"`               // Engine address (unix://, tcp://, ssh://)
+	Version  string `json:"version,omitempty"`  // Engine version, if known
+	Local    bool   `json:"local"`              // The engine runs on this machine, so PIDs are comparable
+	Rootless bool   `json:"rootless,omitempty"` // The daemon runs as an unprivileged user (rootless mode)
+}
+
+// String describes the engine, e.g. "remote engine at ssh://deploy@prod (context prod, version 24.0.7)".
+func (e *Engine) String() string {
+	where := "local"
+	if !e.Local {
+		where = "remote"
+	}
+
+	var details []string
+	if e.Rootless {
+		details = append(details, "rootless")
+	}
+	if e.Context != "" {
+		details = append(details, "context "+e.Context)
+	}
+	if e.Version != "" {
+		details = append(details, "version "+e.Version)
+	}
+
+	s := fmt.Sprintf("%s engine at %s", where, e.Host)
+	if len(details) > 0 {
+		s += " (" + strings.Join(details, ", ") + ")"
+	}
+	return s
+}
+
+// WithEngine returns a copy of the runtime that talks to a docker context or engine host.
+// Empty values keep the CLI defaults (DOCKER_HOST, DOCKER_CONTEXT, the current context).
+func (r Runtime) WithEngine(context, host string) Runtime {
+	r.Context = context
+	r.Host = host
+	return r
+}
+
+// endpoint resolves the docker context and engine address the runtime talks to,
+// following the precedence of the docker CLI.
+func (r Runtime) endpoint() (context, host string) {
+	if r.Name != DockerRuntime.Name {
+		return "", ""
+	}
+	if r.Host != "" {
+		return "", r.Host
+	}
+
+	context = r.Context
+	if context == "" {
+		if host := os.Getenv("DOCKER_HOST"); host != "" {
+			return "", host
+		}
+		context = currentContext()
+	}
+	if context == "" || context == "default" {
+		return "", "unix://" + defaultDockerSocket
+	}
+	return context, contextHost(context)
+}
+
+// engine describes the engine the runtime talks to, without its version.
+func (r Runtime) engine() Engine {
+	context, host := r.endpoint()
+	return Engine{Context: context, Host: host, Local: isLocalHost(host)}
+}
+
+// local reports whether the runtime's engine runs on this machine. PID-based
+// detection (cgroups, environ, docker-proxy) only makes sense for local engines.
+func (r Runtime) local() bool {
+	if r.Name != DockerRuntime.Name {
+		return true
+	}
+	_, host := r.endpoint()
+	return isLocalHost(host)
+}
+
+// isLocalHost reports whether an engine address points at this machine.
+func isLocalHost(host string) bool {
+	if host == "" {
+		return true
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return false
+	}
+
+	switch u.Scheme {
+	case "unix", "npipe":
+		return true
+	case "tcp", "http", "https":
+		hostname := u.Hostname()
+		if hostname == "localhost" {
+			return true
+		}
+		ip := net.ParseIP(hostname)
+		return ip != nil && ip.IsLoopback()
+	default:
+		return false
+	}
+}
+
+// dockerConfigDir returns the docker CLI configuration directory.
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker")
+}
+
+// currentContext returns the active docker context from DOCKER_CONTEXT or the CLI config.
+func currentContext() string {
+	if context := os.Getenv("DOCKER_CONTEXT"); context != "" {
+		return context
+	}
+
+	data, err := os.ReadFile(filepath.Join(dockerConfigDir(), "config.json"))
+	if err != nil {
+		return ""
+	}
+	var config struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if json.Unmarshal(data, &config) != nil {
+		return ""
+	}
+	return config.CurrentContext
+}
+
+// contextHost returns the engine address of a docker context. The CLI stores context
+// metadata under contexts/meta/<sha256 of the name>/meta.json; the CLI is asked when
+// that file cannot be read.
+func contextHost(context string) string {
+	sum := sha256.Sum256([]byte(context))
+	path := filepath.Join(dockerConfigDir(), "contexts", "meta", hex.EncodeToString(sum[:]), "meta.json")
+	if data, err := os.ReadFile(path); err == nil {
+		if host := parseContextMeta(data); host != "" {
+			return host
+		}
+	}
+
+	output, err := DockerRuntime.command("context", "inspect", context, "--format", "{{.Endpoints.docker.Host}}").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// parseContextMeta extracts the docker endpoint address from a context meta.json.
+func parseContextMeta(data []byte) string {
+	var meta struct {
+		Endpoints struct {
+			Docker struct {
+				Host string `json:"Host"`
+			} `json:"docker"`
+		} `json:"Endpoints"`
+	}
+	if json.Unmarshal(data, &meta) != nil {
+		return ""
+	}
+	return meta.Endpoints.Docker.Host
+}
+
+// availabilityEntry is a cached engine availability check.
+type availabilityEntry struct {
+	Available bool      `json:"available"`
+	Version   string    `json:"version,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// availabilityKey identifies a runtime and the engine it talks to in the cache.
+func (r Runtime) availabilityKey() string {
+	context, host := r.endpoint()
+	return strings.Join([]string{r.Name, context, host}, "|")
+}
+
+// availabilityCachePath returns the file caching availability checks, or "" if
+// there is no user cache directory.
+func availabilityCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "whoseport", "engines.json")
+}
+
+// readAvailability loads the availability cache; a missing or corrupt file is an empty cache.
+func readAvailability(path string) map[string]availabilityEntry {
+	entries := make(map[string]availabilityEntry)
+	if path == "" {
+		return entries
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &entries)
+	}
+	return entries
+}
+
+// cachedAvailability returns the cached check for key if it found the engine available
+// and is younger than availabilityTTL. A failed check is never reused, so an engine
+// started after it is seen on the next run.
+func cachedAvailability(path, key string, now time.Time) (availabilityEntry, bool) {
+	entry, ok := readAvailability(path)[key]
+	if !ok || !entry.Available || now.Sub(entry.CheckedAt) > availabilityTTL || now.Before(entry.CheckedAt) {
+		return availabilityEntry{}, false
+	}
+	return entry, true
+}
+
+// storeAvailability records a check for key. Failures are ignored: the cache only saves time.
+func storeAvailability(path, key string, entry availabilityEntry) {
+	if path == "" {
+		return
+	}
+	entries := readAvailability(path)
+	entries[key] = entry
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(path), 0o755) != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,149 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIsLocalHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"", true},
+		{"unix:///var/run/docker.sock", true},
+		{"unix:///run/user/1000/docker.sock", true},
+		{"tcp://127.0.0.1:2375", true},
+		{"tcp://localhost:2375", true},
+		{"tcp://10.0.0.5:2376", false},
+		{"ssh://deploy@prod.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := isLocalHost(tt.host); got != tt.want {
+				t.Errorf("isLocalHost(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+// writeContext creates a docker CLI context named name with the given engine address.
+func writeContext(t *testing.T, configDir, name, host string) {
+	t.Helper()
+	sum := sha256.Sum256([]byte(name))
+	dir := filepath.Join(configDir, "contexts", "meta", hex.EncodeToString(sum[:]))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	meta := `{"Name":"` + name + `","Endpoints":{"docker":{"Host":"` + host + `","SkipTLSVerify":false}}}`
+	if err := os.WriteFile(filepath.Join(dir, "meta.json"), []byte(meta), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRuntimeEndpoint(t *testing.T) {
+	configDir := t.TempDir()
+	writeContext(t, configDir, "prod", "ssh://deploy@prod")
+	writeContext(t, configDir, "lab", "tcp://10.0.0.5:2375")
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"currentContext": "lab"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", configDir)
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKER_HOST", "")
+
+	tests := []struct {
+		name        string
+		runtime     Runtime
+		env         map[string]string
+		wantContext string
+		wantHost    string
+	}{
+		{"explicit host", DockerRuntime.WithEngine("", "tcp://1.2.3.4:2375"), nil, "", "tcp://1.2.3.4:2375"},
+		{"explicit context", DockerRuntime.WithEngine("prod", ""), nil, "prod", "ssh://deploy@prod"},
+		{"DOCKER_HOST", DockerRuntime, map[string]string{"DOCKER_HOST": "unix:///tmp/docker.sock"}, "", "unix:///tmp/docker.sock"},
+		{"DOCKER_CONTEXT", DockerRuntime, map[string]string{"DOCKER_CONTEXT": "prod"}, "prod", "ssh://deploy@prod"},
+		{"current context", DockerRuntime, nil, "lab", "tcp://10.0.0.5:2375"},
+		{"default context", DockerRuntime, map[string]string{"DOCKER_CONTEXT": "default"}, "", "unix:///var/run/docker.sock"},
+		{"not docker", PodmanRuntime, nil, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			context, host := tt.runtime.endpoint()
+			if context != tt.wantContext || host != tt.wantHost {
+				t.Errorf("endpoint() = (%q, %q), want (%q, %q)", context, host, tt.wantContext, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestRuntimeCommandEngine(t *testing.T) {
+	tests := []struct {
+		name    string
+		runtime Runtime
+		want    []string
+	}{
+		{"context", DockerRuntime.WithEngine("prod", ""), []string{"docker", "--context", "prod", "ps"}},
+		{"host", DockerRuntime.WithEngine("", "ssh://deploy@prod"), []string{"docker", "--host", "ssh://deploy@prod", "ps"}},
+		{"context wins", DockerRuntime.WithEngine("prod", "ssh://deploy@prod"), []string{"docker", "--context", "prod", "ps"}},
+		{"defaults", DockerRuntime, []string{"docker", "ps"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.runtime.command("ps").Args; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("command() args = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvailabilityCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "whoseport", "engines.json")
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	if _, ok := cachedAvailability(path, "docker||unix:///var/run/docker.sock", now); ok {
+		t.Fatal("cachedAvailability() hit on an empty cache")
+	}
+
+	storeAvailability(path, "docker||unix:///var/run/docker.sock", availabilityEntry{Available: true, Version: "24.0.7", CheckedAt: now})
+	storeAvailability(path, "docker|prod|ssh://deploy@prod", availabilityEntry{Available: false, CheckedAt: now})
+
+	entry, ok := cachedAvailability(path, "docker||unix:///var/run/docker.sock", now.Add(time.Minute))
+	if !ok || !entry.Available || entry.Version != "24.0.7" {
+		t.Errorf("cachedAvailability() = (%+v, %v), want available 24.0.7", entry, ok)
+	}
+	// An engine that was down may have been started since, so it is probed again
+	if entry, ok := cachedAvailability(path, "docker|prod|ssh://deploy@prod", now.Add(time.Minute)); ok {
+		t.Errorf("cachedAvailability(prod) = (%+v, %v), want a miss for an unavailable engine", entry, ok)
+	}
+	if _, ok := cachedAvailability(path, "docker||unix:///var/run/docker.sock", now.Add(availabilityTTL+time.Second)); ok {
+		t.Error("cachedAvailability() hit after the TTL expired")
+	}
+}
+
+func TestEngineString(t *testing.T) {
+	tests := []struct {
+		engine Engine
+		want   string
+	}{
+		{Engine{Host: "unix:///var/run/docker.sock", Version: "24.0.7", Local: true}, "local engine at unix:///var/run/docker.sock (version 24.0.7)"},
+		{Engine{Context: "prod", Host: "ssh://deploy@prod"}, "remote engine at ssh://deploy@prod (context prod)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.engine.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+// podmanPortHolders are the host-side processes that hold published ports for
+// Podman containers instead of the container process itself.
+var podmanPortHolders = []string{"conmon", "rootlessport", "pasta", "passt", "slirp4netns"}
+
+// isPodmanPortHolder checks whether the process is one of Podman's port holders.
+func isPodmanPortHolder(info *model.ProcessInfo) bool {
+	name := strings.ToLower(info.Command)
+	for _, holder := range podmanPortHolders {
+		if strings.HasPrefix(name, holder) {
+			return true
+		}
+	}
+	return false
+}
+
+// podmanContainer is the subset of `podman ps --format json` output used for lookups.
+type podmanContainer struct {
+	ID      string `json:"Id"`
+	Pod     string `json:"Pod"`
+	IsInfra bool   `json:"IsInfra"`
+	Ports   []struct {
+		HostIP        string `json:"host_ip"`
+		ContainerPort int    `json:"container_port"`
+		HostPort      int    `json:"host_port"`
+		Range         int    `json:"range"`
+		Protocol      string `json:"protocol"`
+	} `json:"Ports"`
+}
+
+// hasHostPort checks whether the container publishes the given host port (including ranges).
+func (c podmanContainer) hasHostPort(port int) bool {
+	for _, p := range c.Ports {
+		span := max(p.Range, 1)
+		if port >= p.HostPort && port < p.HostPort+span {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePodmanPs parses the JSON array printed by `podman ps --format json`.
+func parsePodmanPs(output []byte) ([]podmanContainer, error) {
+	var containers []podmanContainer
+	if err := json.Unmarshal(output, &containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
+
+// findPodmanContainer maps a Podman port holder to the container it serves.
+func (d *DefaultDetector) findPodmanContainer(info *model.ProcessInfo, port int) string {
+	// conmon carries the container ID on its command line (-c <id>)
+	if id := conmonContainerID(info.FullCommand); id != "" {
+		return id
+	}
+
+	// rootlessport and friends are usually children of the container's conmon
+	if info.PPid > 0 {
+		if cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", info.PPid)); err == nil {
+			if id := conmonContainerID(strings.ReplaceAll(string(cmdline), "\x00", " ")); id != "" {
+				return id
+			}
+		}
+	}
+
+	// pasta and slirp4netns serve a whole network namespace: match the published port
+	return d.findContainerByPort(port)
+}
+
+// conmonContainerID extracts the container ID from a conmon command line.
+func conmonContainerID(cmdline string) string {
+	fields := strings.Fields(cmdline)
+	if len(fields) == 0 || filepath.Base(fields[0]) != "conmon" {
+		return ""
+	}
+	for i, field := range fields {
+		if (field == "-c" || field == "--cid") && i+1 < len(fields) {
+			return fields[i+1]
+		}
+		if strings.HasPrefix(field, "--cid=") {
+			return strings.TrimPrefix(field, "--cid=")
+		}
+	}
+	return ""
+}
+
+// findPodmanContainerByPort searches `podman ps` output for a published host port.
+// Pod containers share the infra container's ports, so a regular pod member is
+// preferred over the infra container when both match.
+func (d *DefaultDetector) findPodmanContainerByPort(port int) string {
+	output, err := d.runtime.command("ps", "--format", "json").Output()
+	if err != nil {
+		return ""
+	}
+
+	containers, err := parsePodmanPs(output)
+	if err != nil {
+		return ""
+	}
+
+	return selectPodmanContainer(containers, port)
+}
+
+// selectPodmanContainer picks the container publishing the port.
+// Ports published on a pod are only listed on its infra container, so when the
+// pod has a single application container that container is reported instead.
+func selectPodmanContainer(containers []podmanContainer, port int) string {
+	var infra *podmanContainer
+	for i := range containers {
+		if !containers[i].hasHostPort(port) {
+			continue
+		}
+		if !containers[i].IsInfra {
+			return containers[i].ID
+		}
+		infra = &containers[i]
+	}
+
+	if infra == nil {
+		return ""
+	}
+
+	var members []string
+	for _, c := range containers {
+		if c.Pod == infra.Pod && !c.IsInfra {
+			members = append(members, c.ID)
+		}
+	}
+	if len(members) == 1 {
+		return members[0]
+	}
+	return infra.ID
+}
+
+// podName resolves a pod ID to its name.
+func (r *DefaultRetriever) podName(podID string) string {
+	output, err := r.runtime.command("pod", "inspect", "--format", "{{.Name}}", podID).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,109 @@
+package docker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+func TestConmonContainerID(t *testing.T) {
+	id := strings.Repeat("c0ffee", 10) + "abcd"
+
+	tests := []struct {
+		name    string
+		cmdline string
+		want    string
+	}{
+		{"short flag", "/usr/bin/conmon --api-version 1 -c " + id + " -u " + id + " -r /usr/bin/crun", id},
+		{"long flag", "/usr/bin/conmon --cid " + id + " --runtime /usr/bin/runc", id},
+		{"long flag with equals", "/usr/bin/conmon --cid=" + id, id},
+		{"not conmon", "/usr/bin/rootlessport -c " + id, ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conmonContainerID(tt.cmdline); got != tt.want {
+				t.Errorf("conmonContainerID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPodmanPortHolder(t *testing.T) {
+	for _, cmd := range []string{"conmon", "rootlessport", "rootlessport-child", "pasta", "pasta.avx2", "slirp4netns"} {
+		if !isPodmanPortHolder(&model.ProcessInfo{Command: cmd}) {
+			t.Errorf("isPodmanPortHolder(%q) = false, want true", cmd)
+		}
+	}
+	if isPodmanPortHolder(&model.ProcessInfo{Command: "nginx"}) {
+		t.Error("isPodmanPortHolder(nginx) = true, want false")
+	}
+}
+
+func TestSelectPodmanContainer(t *testing.T) {
+	output := []byte(`[
+  {"Id": "aaa", "Pod": "", "IsInfra": false, "Ports": [{"host_ip": "", "container_port": 80, "host_port": 8080, "range": 1, "protocol": "tcp"}]},
+  {"Id": "infra1", "Pod": "pod1", "IsInfra": true, "Ports": [{"host_ip": "", "container_port": 5432, "host_port": 5432, "range": 1, "protocol": "tcp"}]},
+  {"Id": "db", "Pod": "pod1", "IsInfra": false, "Ports": null},
+  {"Id": "infra2", "Pod": "pod2", "IsInfra": true, "Ports": [{"host_ip": "", "container_port": 9000, "host_port": 9000, "range": 10, "protocol": "tcp"}]},
+  {"Id": "web", "Pod": "pod2", "IsInfra": false, "Ports": null},
+  {"Id": "worker", "Pod": "pod2", "IsInfra": false, "Ports": null}
+]`)
+
+	containers, err := parsePodmanPs(output)
+	if err != nil {
+		t.Fatalf("parsePodmanPs() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		port int
+		want string
+	}{
+		{"plain container", 8080, "aaa"},
+		{"single-member pod resolves to member", 5432, "db"},
+		{"multi-member pod resolves to infra", 9005, "infra2"},
+		{"no match", 7070, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectPodmanContainer(containers, tt.port); got != tt.want {
+				t.Errorf("selectPodmanContainer(%d) = %v, want %v", tt.port, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptActionPodMember(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Action
+	}{
+		{"9\n", ActionStopPod},
+		{"10\n", ActionRemovePod},
+		{"\n", ActionCancel},
+	}
+
+	for _, tt := range tests {
+		writer := &bytes.Buffer{}
+		handler := NewActionHandlerWithIO(strings.NewReader(tt.input), writer)
+		info := &ContainerInfo{
+			Name:    "db",
+			ShortID: "abc123def456",
+			Runtime: "podman",
+			PodID:   "f00dbabe",
+			PodName: "shop",
+		}
+
+		if got := handler.PromptAction(info); got != tt.want {
+			t.Errorf("PromptAction(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if !strings.Contains(writer.String(), "podman pod stop") {
+			t.Errorf("Output should offer podman pod actions, got: %s", writer.String())
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/bluehoodie/whoseport/internal/action"
+)
+
+// anonymousVolumeName matches the generated names of anonymous volumes.
+var anonymousVolumeName = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// Anonymous reports whether the mount is an anonymous volume: created for the
+// container alone, orphaned by `rm` and deleted by `rm -v`.
+func (m Mount) Anonymous() bool {
+	return m.Type == "volume" && anonymousVolumeName.MatchString(m.Name)
+}
+
+// RemovedContainer is the configuration of a removed container, kept so it can be recreated.
+type RemovedContainer struct {
+	RemovedAt      time.Time         `json:"removed_at"`
+	Runtime        string            `json:"runtime"`
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Image          string            `json:"image"`
+	Command        string            `json:"command,omitempty"`
+	Env            []string          `json:"env,omitempty"`
+	Ports          []PortMapping     `json:"ports,omitempty"`
+	Mounts         []Mount           `json:"mounts,omitempty"`
+	Networks       []string          `json:"networks,omitempty"`
+	RestartPolicy  string            `json:"restart_policy,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	VolumesRemoved bool              `json:"volumes_removed"` // Anonymous volumes were removed too (rm -v)
+	RunCommand     string            `json:"run_command"`     // Command that recreates the container
+}
+
+// defaultRecordDir returns the directory removed container configs are saved to:
+// $XDG_STATE_HOME/whoseport/removed, or ~/.local/state/whoseport/removed.
+func defaultRecordDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "whoseport", "removed")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "state", "whoseport", "removed")
+}
+
+// recordRemoval saves the container's configuration to dir and returns the file path.
+func recordRemoval(dir string, info *ContainerInfo, volumesRemoved bool, now time.Time) (string, error) {
+	record := RemovedContainer{
+		RemovedAt:      now,
+		Runtime:        info.Runtime,
+		ID:             info.ID,
+		Name:           info.Name,
+		Image:          info.Image,
+		Command:        info.Command,
+		Env:            info.Env,
+		Ports:          info.Ports,
+		Mounts:         info.Mounts,
+		Networks:       info.Networks,
+		RestartPolicy:  info.RestartPolicy,
+		Labels:         info.Labels,
+		VolumesRemoved: volumesRemoved,
+		RunCommand:     action.ShellJoin(runCommand(info)),
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode container config: %w", err)
+	}
+	// The environment may hold secrets, so the directory and file are private
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	name := info.Name
+	if name == "" {
+		name = info.ShortID
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.json", name, now.Format("20060102-150405")))
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// runCommand rebuilds a `run` command for the container from its inspected configuration,
+// ending with the command the container was started with.
+func runCommand(info *ContainerInfo) []string {
+	args := []string{RuntimeByName(info.Runtime).Binary, "run", "-d"}
+	if info.Name != "" {
+		args = append(args, "--name", info.Name)
+	}
+	if info.RestartPolicy != "" && info.RestartPolicy != "no" {
+		args = append(args, "--restart", info.RestartPolicy)
+	}
+	if len(info.Networks) == 1 && info.Networks[0] != "bridge" && info.Networks[0] != "default" {
+		args = append(args, "--network", info.Networks[0])
+	}
+
+	// Bindings are listed once per address family; publish each host port once
+	seen := make(map[string]bool)
+	for _, p := range info.Ports {
+		publish := fmt.Sprintf("%s:%s/%s", p.HostPort, p.ContainerPort, p.Protocol)
+		if p.HostIP != "" && p.HostIP != "0.0.0.0" && p.HostIP != "::" {
+			publish = p.HostIP + ":" + publish
+		}
+		if !seen[publish] {
+			seen[publish] = true
+			args = append(args, "-p", publish)
+		}
+	}
+
+	for _, m := range info.Mounts {
+		switch {
+		case m.Type == "tmpfs":
+			args = append(args, "--tmpfs", m.Destination)
+			continue
+		case m.Anonymous():
+			args = append(args, "-v", m.Destination)
+			continue
+		}
+
+		source := m.Source
+		if m.Type == "volume" && m.Name != "" {
+			source = m.Name
+		}
+		volume := source + ":" + m.Destination
+		if m.Mode == "ro" {
+			volume += ":ro"
+		}
+		args = append(args, "-v", volume)
+	}
+
+	for _, env := range info.Env {
+		args = append(args, "-e", env)
+	}
+
+	args = append(args, info.Image)
+	return append(args, info.Cmd...)
+}
@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const anonymousVolume = "3f4e5d6c7b8a99887766554433221100ffeeddccbbaa00112233445566778899"
+
+// removalFixture returns a container with one mount of each kind.
+func removalFixture() *ContainerInfo {
+	return &ContainerInfo{
+		ID:            "abc123def4567890",
+		ShortID:       "abc123def456",
+		Name:          "web",
+		Image:         "nginx:alpine",
+		Cmd:           []string{"nginx", "-g", "daemon off;"},
+		Runtime:       "docker",
+		RestartPolicy: "always",
+		Networks:      []string{"shop_default"},
+		Env:           []string{"MODE=prod", "GREETING=hello world"},
+		Ports: []PortMapping{
+			{HostIP: "0.0.0.0", HostPort: "8080", ContainerPort: "80", Protocol: "tcp"},
+			{HostIP: "::", HostPort: "8080", ContainerPort: "80", Protocol: "tcp"},
+			{HostIP: "127.0.0.1", HostPort: "9090", ContainerPort: "9090", Protocol: "tcp"},
+		},
+		Mounts: []Mount{
+			{Type: "volume", Name: anonymousVolume, Source: "/var/lib/docker/volumes/" + anonymousVolume + "/_data", Destination: "/cache", Mode: "rw"},
+			{Type: "volume", Name: "web-data", Source: "/var/lib/docker/volumes/web-data/_data", Destination: "/data", Mode: "rw"},
+			{Type: "bind", Source: "/srv/nginx.conf", Destination: "/etc/nginx/nginx.conf", Mode: "ro"},
+			{Type: "tmpfs", Destination: "/run"},
+		},
+	}
+}
+
+func TestMountAnonymous(t *testing.T) {
+	tests := []struct {
+		mount Mount
+		want  bool
+	}{
+		{Mount{Type: "volume", Name: anonymousVolume}, true},
+		{Mount{Type: "volume", Name: "web-data"}, false},
+		{Mount{Type: "bind", Source: "/srv"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.mount.Anonymous(); got != tt.want {
+			t.Errorf("Anonymous(%+v) = %v, want %v", tt.mount, got, tt.want)
+		}
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	want := []string{
+		"docker", "run", "-d", "--name", "web", "--restart", "always", "--network", "shop_default",
+		"-p", "8080:80/tcp", "-p", "127.0.0.1:9090:9090/tcp",
+		"-v", "/cache", "-v", "web-data:/data", "-v", "/srv/nginx.conf:/etc/nginx/nginx.conf:ro", "--tmpfs", "/run",
+		"-e", "MODE=prod", "-e", "GREETING=hello world",
+		"nginx:alpine", "nginx", "-g", "daemon off;",
+	}
+	if got := runCommand(removalFixture()); !reflect.DeepEqual(got, want) {
+		t.Errorf("runCommand() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestRecordRemoval(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "removed")
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	path, err := recordRemoval(dir, removalFixture(), true, now)
+	if err != nil {
+		t.Fatalf("recordRemoval() error = %v", err)
+	}
+	if filepath.Base(path) != "web-20240501-100000.json" {
+		t.Errorf("record file = %s", path)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Mode().Perm() != 0o600 {
+		t.Errorf("record permissions = %v, want 0600", stat.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record RemovedContainer
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Image != "nginx:alpine" || !record.VolumesRemoved || len(record.Env) != 2 || len(record.Mounts) != 4 {
+		t.Errorf("record = %+v", record)
+	}
+	if !strings.HasPrefix(record.RunCommand, "docker run -d --name web") || !strings.HasSuffix(record.RunCommand, "nginx:alpine nginx -g 'daemon off;'") {
+		t.Errorf("RunCommand = %s", record.RunCommand)
+	}
+}
+
+func TestPromptRemoval(t *testing.T) {
+	tests := []struct {
+		name        string
+		info        *ContainerInfo
+		input       string
+		wantVolumes bool
+		wantOK      bool
+	}{
+		{"keep volumes", removalFixture(), "1\n", false, true},
+		{"remove volumes", removalFixture(), "2\n", true, true},
+		{"cancel", removalFixture(), "3\n", false, false},
+		{"default cancel", removalFixture(), "\n", false, false},
+		{"invalid then keep", removalFixture(), "7\n1\n", false, true},
+		{"no mounts", &ContainerInfo{Name: "bare", Runtime: "docker"}, "", false, true},
+		{"no anonymous volumes", &ContainerInfo{Name: "named", Runtime: "docker", Mounts: []Mount{{Type: "volume", Name: "data", Destination: "/data"}}}, "2\n", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			handler := NewActionHandlerWithIO(strings.NewReader(tt.input), writer)
+
+			volumes, ok := handler.promptRemoval(tt.info)
+			if volumes != tt.wantVolumes || ok != tt.wantOK {
+				t.Errorf("promptRemoval() = (%v, %v), want (%v, %v)", volumes, ok, tt.wantVolumes, tt.wantOK)
+			}
+		})
+	}
+
+	writer := &bytes.Buffer{}
+	NewActionHandlerWithIO(strings.NewReader("3\n"), writer).promptRemoval(removalFixture())
+	for _, want := range []string{"Anonymous volumes", "3f4e5d6c7b8a → /cache", "web-data → /data", "/srv/nginx.conf → /etc/nginx/nginx.conf", "rm -v"} {
+		if !strings.Contains(writer.String(), want) {
+			t.Errorf("promptRemoval() output missing %q:\n%s", want, writer.String())
+		}
+	}
+}
@@ -3,7 +3,6 @@ package docker
 import (
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
 	"time"
 
@@ -13,25 +12,41 @@ import (
 // Retriever retrieves detailed Docker container information.
 type Retriever interface {
 	// GetContainerInfo retrieves detailed information about a container
+	// processInfo may be nil when no host process holds the port
 	GetContainerInfo(containerID string, processInfo *model.ProcessInfo) (*ContainerInfo, error)
+
+	// GetServiceInfo retrieves a swarm service with its published ports and tasks
+	GetServiceInfo(serviceID string, processInfo *model.ProcessInfo) (*ServiceInfo, error)
 }
 
-// DefaultRetriever implements container information retrieval.
-type DefaultRetriever struct{}
+// DefaultRetriever implements container information retrieval for a runtime.
+type DefaultRetriever struct {
+	runtime Runtime
+	client  *Client // Engine API client (Docker only); nil means CLI only
+}
 
 // NewRetriever creates a new Docker container retriever.
 func NewRetriever() Retriever {
-	return &DefaultRetriever{}
+	return NewRetrieverForRuntime(DockerRuntime)
+}
+
+// NewRetrieverForRuntime creates a container retriever for the given runtime.
+func NewRetrieverForRuntime(runtime Runtime) Retriever {
+	return &DefaultRetriever{runtime: runtime, client: runtime.apiClient()}
 }
 
 // GetContainerInfo retrieves comprehensive container information.
 func (r *DefaultRetriever) GetContainerInfo(containerID string, processInfo *model.ProcessInfo) (*ContainerInfo, error) {
 	info := &ContainerInfo{
-		ID:         containerID,
-		ShortID:    containerID,
-		ProcessID:  processInfo.ID,
-		ProcessCmd: processInfo.Command,
+		ID:      containerID,
+		ShortID: containerID,
+		Runtime: r.runtime.Name,
 	}
+	if processInfo != nil {
+		info.ProcessID = processInfo.ID
+		info.ProcessCmd = processInfo.Command
+	}
+	info.Engine = r.engine()
 
 	// Truncate to short ID if it's a full ID
 	if len(containerID) > 12 {
@@ -39,27 +54,129 @@ func (r *DefaultRetriever) GetContainerInfo(containerID string, processInfo *mod
 	}
 
 	// Get detailed inspect data
-	if err := r.populateInspectData(info); err != nil {
+	inspect := r.populateInspectData
+	if r.runtime.Name == ContainerdRuntime.Name {
+		inspect = r.populateContainerdData
+	}
+	if err := inspect(info); err != nil {
 		return nil, fmt.Errorf("failed to inspect container: %w", err)
 	}
 
+	// Resolve the pod name for Podman pod members
+	if info.PodID != "" {
+		info.PodName = r.podName(info.PodID)
+	}
+
+	// Compose project and sibling services
+	if info.Compose = composeFromLabels(info.Labels); info.Compose != nil {
+		r.populateComposeSiblings(info)
+	}
+
 	// Get real-time stats (non-blocking)
 	// We don't fail if stats aren't available
-	_ = r.populateStatsData(info)
+	_ = (&DefaultRetriever{runtime: runtimeFor(info), client: r.client}).populateStatsData(info)
 
 	return info, nil
 }
 
-// populateInspectData uses docker inspect to get container details.
+// engine describes the Docker engine the retriever talks to (nil for other runtimes).
+func (r *DefaultRetriever) engine() *Engine {
+	if r.runtime.Name != DockerRuntime.Name {
+		return nil
+	}
+
+	engine := r.runtime.engine()
+	engine.Rootless = isRootlessHost(engine.Host)
+	if r.client != nil {
+		if info, err := r.client.Info(); err == nil {
+			engine.Version = info.ServerVersion
+			engine.Rootless = info.rootless()
+		}
+	} else if entry, ok := cachedAvailability(availabilityCachePath(), r.runtime.availabilityKey(), time.Now()); ok {
+		engine.Version = entry.Version
+	}
+	return &engine
+}
+
+// containerJSON is the inspect document shared by `docker inspect` and the Engine API.
+// Podman and nerdctl print the same shape.
+type containerJSON struct {
+	ID       string `json:"Id"`
+	Name     string `json:"Name"`
+	Image    string `json:"Image"`
+	Platform string `json:"Platform"`
+	Pod      string `json:"Pod"` // Podman only
+	Config   struct {
+		Image  string            `json:"Image"`
+		Cmd    []string          `json:"Cmd"`
+		Env    []string          `json:"Env"`
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	RestartCount int `json:"RestartCount"`
+	State        struct {
+		Status     string `json:"Status"`
+		Pid        int    `json:"Pid"`
+		OOMKilled  bool   `json:"OOMKilled"`
+		ExitCode   int    `json:"ExitCode"`
+		Error      string `json:"Error"`
+		StartedAt  string `json:"StartedAt"`
+		FinishedAt string `json:"FinishedAt"`
+		Health     *struct {
+			Status        string `json:"Status"`
+			FailingStreak int    `json:"FailingStreak"`
+			Log           []struct {
+				Start    string `json:"Start"`
+				End      string `json:"End"`
+				ExitCode int    `json:"ExitCode"`
+				Output   string `json:"Output"`
+			} `json:"Log"`
+		} `json:"Health"`
+	} `json:"State"`
+	HostConfig struct {
+		RestartPolicy struct {
+			Name string `json:"Name"`
+		} `json:"RestartPolicy"`
+	} `json:"HostConfig"`
+	NetworkSettings struct {
+		Ports    map[string][]portBinding `json:"Ports"`
+		Networks map[string]struct {
+			IPAddress  string `json:"IPAddress"`
+			Gateway    string `json:"Gateway"`
+			MacAddress string `json:"MacAddress"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+	Mounts []struct {
+		Type        string `json:"Type"`
+		Name        string `json:"Name"`
+		Source      string `json:"Source"`
+		Destination string `json:"Destination"`
+		RW          bool   `json:"RW"`
+	} `json:"Mounts"`
+}
+
+// portBinding is a host binding of a container port.
+type portBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// populateInspectData gets container details from the Engine API, falling back to docker inspect.
 func (r *DefaultRetriever) populateInspectData(info *ContainerInfo) error {
-	cmd := exec.Command("docker", "inspect", info.ID)
+	if r.client != nil {
+		if data, err := r.client.InspectContainer(info.ID); err == nil {
+			r.applyInspect(info, data)
+			return nil
+		}
+	}
+
+	cmd := r.runtime.command("inspect", info.ID)
 	output, err := cmd.Output()
 	if err != nil {
 		return err
 	}
 
 	// Parse JSON output
-	var inspectData []map[string]interface{}
+	var inspectData []containerJSON
 	if err := json.Unmarshal(output, &inspectData); err != nil {
 		return err
 	}
@@ -68,141 +185,112 @@ func (r *DefaultRetriever) populateInspectData(info *ContainerInfo) error {
 		return fmt.Errorf("no inspect data returned")
 	}
 
-	data := inspectData[0]
+	r.applyInspect(info, &inspectData[0])
+	return nil
+}
 
-	// Extract basic information
-	if name, ok := data["Name"].(string); ok {
-		info.Name = strings.TrimPrefix(name, "/")
-	}
+// applyInspect copies an inspect document into the container info.
+func (r *DefaultRetriever) applyInspect(info *ContainerInfo, data *containerJSON) {
+	info.Name = strings.TrimPrefix(data.Name, "/")
 
 	// Config section
-	if config, ok := data["Config"].(map[string]interface{}); ok {
-		if image, ok := config["Image"].(string); ok {
-			info.Image = image
-		}
-		if cmd, ok := config["Cmd"].([]interface{}); ok {
-			cmdParts := make([]string, 0, len(cmd))
-			for _, part := range cmd {
-				if s, ok := part.(string); ok {
-					cmdParts = append(cmdParts, s)
-				}
-			}
-			info.Command = strings.Join(cmdParts, " ")
-		}
-		if labels, ok := config["Labels"].(map[string]interface{}); ok {
-			info.Labels = make(map[string]string)
-			for k, v := range labels {
-				if s, ok := v.(string); ok {
-					info.Labels[k] = s
-				}
-			}
-		}
-	}
+	info.Image = data.Config.Image
+	info.Command = strings.Join(data.Config.Cmd, " ")
+	info.Cmd = data.Config.Cmd
+	info.Labels = data.Config.Labels
+	info.Env = data.Config.Env
 
 	// State section
-	if state, ok := data["State"].(map[string]interface{}); ok {
-		if status, ok := state["Status"].(string); ok {
-			info.State = status
-			info.Status = status
+	info.State = data.State.Status
+	info.Status = data.State.Status
+	info.PID = data.State.Pid
+	info.RestartCount = data.RestartCount
+	info.OOMKilled = data.State.OOMKilled
+	info.ExitCode = data.State.ExitCode
+	info.Error = data.State.Error
+	info.StartedAt = data.State.StartedAt
+	if !strings.HasPrefix(data.State.FinishedAt, "0001-01-01") {
+		info.FinishedAt = data.State.FinishedAt
+	}
+	if h := data.State.Health; h != nil && h.Status != "" {
+		info.Health = &Health{Status: h.Status, FailingStreak: h.FailingStreak}
+		for _, l := range h.Log {
+			info.Health.Log = append(info.Health.Log, HealthCheck{
+				Start:    l.Start,
+				End:      l.End,
+				ExitCode: l.ExitCode,
+				Output:   strings.TrimSpace(l.Output),
+			})
 		}
-		if startedAt, ok := state["StartedAt"].(string); ok {
-			info.CreatedAt = startedAt
-			// Calculate running duration
-			if t, err := time.Parse(time.RFC3339Nano, startedAt); err == nil {
-				duration := time.Since(t)
-				info.RunningFor = formatDuration(duration)
-			}
+	}
+	if data.State.StartedAt != "" {
+		info.CreatedAt = data.State.StartedAt
+		// Calculate running duration
+		if t, err := time.Parse(time.RFC3339Nano, data.State.StartedAt); err == nil {
+			info.RunningFor = formatDuration(time.Since(t))
 		}
 	}
 
 	// Image ID
-	if imageID, ok := data["Image"].(string); ok {
-		info.ImageID = imageID
-		if len(imageID) > 19 && strings.HasPrefix(imageID, "sha256:") {
-			info.ImageID = imageID[7:19] // Short hash
-		}
+	info.ImageID = data.Image
+	if len(data.Image) > 19 && strings.HasPrefix(data.Image, "sha256:") {
+		info.ImageID = data.Image[7:19] // Short hash
 	}
 
 	// Host config for restart policy
-	if hostConfig, ok := data["HostConfig"].(map[string]interface{}); ok {
-		if restartPolicy, ok := hostConfig["RestartPolicy"].(map[string]interface{}); ok {
-			if name, ok := restartPolicy["Name"].(string); ok {
-				info.RestartPolicy = name
-			}
-		}
-	}
+	info.RestartPolicy = data.HostConfig.RestartPolicy.Name
 
-	// Network settings
-	if netSettings, ok := data["NetworkSettings"].(map[string]interface{}); ok {
-		// Port mappings
-		if ports, ok := netSettings["Ports"].(map[string]interface{}); ok {
-			info.Ports = r.parsePortMappings(ports)
-			info.PortString = r.formatPortString(info.Ports)
-		}
+	// Port mappings
+	if data.NetworkSettings.Ports != nil {
+		info.Ports = r.parsePortMappings(data.NetworkSettings.Ports)
+		info.PortString = r.formatPortString(info.Ports)
+	}
 
-		// Networks
-		if networks, ok := netSettings["Networks"].(map[string]interface{}); ok {
-			info.Networks = make([]string, 0, len(networks))
-			for netName, netData := range networks {
-				info.Networks = append(info.Networks, netName)
-
-				// Get IP from first network
-				if info.IPAddress == "" {
-					if netMap, ok := netData.(map[string]interface{}); ok {
-						if ip, ok := netMap["IPAddress"].(string); ok {
-							info.IPAddress = ip
-						}
-						if gw, ok := netMap["Gateway"].(string); ok {
-							info.Gateway = gw
-						}
-						if mac, ok := netMap["MacAddress"].(string); ok {
-							info.MacAddress = mac
-						}
-					}
-				}
+	// Networks
+	if data.NetworkSettings.Networks != nil {
+		info.Networks = make([]string, 0, len(data.NetworkSettings.Networks))
+		for netName, network := range data.NetworkSettings.Networks {
+			info.Networks = append(info.Networks, netName)
+
+			// Get IP from first network
+			if info.IPAddress == "" {
+				info.IPAddress = network.IPAddress
+				info.Gateway = network.Gateway
+				info.MacAddress = network.MacAddress
 			}
 		}
 	}
 
 	// Mounts
-	if mounts, ok := data["Mounts"].([]interface{}); ok {
-		info.Mounts = make([]Mount, 0, len(mounts))
-		for _, mountData := range mounts {
-			if m, ok := mountData.(map[string]interface{}); ok {
-				mount := Mount{}
-				if t, ok := m["Type"].(string); ok {
-					mount.Type = t
-				}
-				if s, ok := m["Source"].(string); ok {
-					mount.Source = s
-				}
-				if d, ok := m["Destination"].(string); ok {
-					mount.Destination = d
-				}
-				if rw, ok := m["RW"].(bool); ok {
-					if rw {
-						mount.Mode = "rw"
-					} else {
-						mount.Mode = "ro"
-					}
-				}
-				info.Mounts = append(info.Mounts, mount)
+	if data.Mounts != nil {
+		info.Mounts = make([]Mount, 0, len(data.Mounts))
+		for _, m := range data.Mounts {
+			mount := Mount{Type: m.Type, Name: m.Name, Source: m.Source, Destination: m.Destination, Mode: "ro"}
+			if m.RW {
+				mount.Mode = "rw"
 			}
+			info.Mounts = append(info.Mounts, mount)
 		}
 	}
 
 	// Platform
-	if platform, ok := data["Platform"].(string); ok {
-		info.Platform = platform
-	}
+	info.Platform = data.Platform
 
-	return nil
+	// Pod membership (Podman only)
+	info.PodID = data.Pod
 }
 
-// populateStatsData uses docker stats to get real-time resource usage.
+// populateStatsData gets real-time resource usage from the Engine API, falling back to docker stats.
 func (r *DefaultRetriever) populateStatsData(info *ContainerInfo) error {
+	if r.client != nil {
+		if data, err := r.client.ContainerStats(info.ID); err == nil {
+			applyStats(info, statsFromAPI(data))
+			return nil
+		}
+	}
+
 	// Use --no-stream to get a single snapshot
-	cmd := exec.Command("docker", "stats", "--no-stream", "--format",
+	cmd := r.runtime.command("stats", "--no-stream", "--format",
 		"{{.CPUPerc}}|{{.MemUsage}}|{{.MemPerc}}|{{.NetIO}}|{{.BlockIO}}|{{.PIDs}}",
 		info.ID)
 
@@ -221,33 +309,23 @@ func (r *DefaultRetriever) populateStatsData(info *ContainerInfo) error {
 		info.NetIO = strings.TrimSpace(parts[3])
 		info.BlockIO = strings.TrimSpace(parts[4])
 		info.PIDs = strings.TrimSpace(parts[5])
+		info.Stats = parseStats(info)
 	}
 
 	return nil
 }
 
-// parsePortMappings converts Docker port map to PortMapping structs.
-func (r *DefaultRetriever) parsePortMappings(ports map[string]interface{}) []PortMapping {
+// parsePortMappings converts inspect port bindings to PortMapping structs.
+func (r *DefaultRetriever) parsePortMappings(ports map[string][]portBinding) []PortMapping {
 	mappings := make([]PortMapping, 0)
 
 	for containerPort, bindings := range ports {
-		if bindings == nil {
-			continue
-		}
-
-		bindingsList, ok := bindings.([]interface{})
-		if !ok {
-			continue
-		}
-
-		for _, binding := range bindingsList {
-			bindingMap, ok := binding.(map[string]interface{})
-			if !ok {
-				continue
+		for _, binding := range bindings {
+			mapping := PortMapping{
+				HostIP:   binding.HostIP,
+				HostPort: binding.HostPort,
 			}
 
-			mapping := PortMapping{}
-
 			// Parse container port (format: "8080/tcp")
 			portParts := strings.Split(containerPort, "/")
 			if len(portParts) >= 1 {
@@ -257,14 +335,6 @@ func (r *DefaultRetriever) parsePortMappings(ports map[string]interface{}) []Por
 				mapping.Protocol = portParts[1]
 			}
 
-			// Parse host binding
-			if hostIP, ok := bindingMap["HostIp"].(string); ok {
-				mapping.HostIP = hostIP
-			}
-			if hostPort, ok := bindingMap["HostPort"].(string); ok {
-				mapping.HostPort = hostPort
-			}
-
 			mappings = append(mappings, mapping)
 		}
 	}
@@ -0,0 +1,56 @@
+package docker
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const unhealthyInspect = `{
+  "Id": "ccc333",
+  "Name": "/api",
+  "RestartCount": 4,
+  "Config": {"Image": "shop/api:1.2"},
+  "State": {
+    "Status": "running", "OOMKilled": true, "ExitCode": 137, "Error": "",
+    "StartedAt": "2024-05-01T10:00:00Z", "FinishedAt": "2024-05-01T09:59:58Z",
+    "Health": {"Status": "unhealthy", "FailingStreak": 3, "Log": [
+      {"Start": "2024-05-01T10:00:30Z", "End": "2024-05-01T10:00:31Z", "ExitCode": 1, "Output": "curl: (7) Failed to connect\n"}
+    ]}
+  }
+}`
+
+func TestApplyInspectHealth(t *testing.T) {
+	var data containerJSON
+	if err := json.Unmarshal([]byte(unhealthyInspect), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	info := &ContainerInfo{ID: "ccc333"}
+	(&DefaultRetriever{runtime: DockerRuntime}).applyInspect(info, &data)
+
+	if info.RestartCount != 4 || !info.OOMKilled || info.ExitCode != 137 {
+		t.Errorf("exit details = (%d, %v, %d), want (4, true, 137)", info.RestartCount, info.OOMKilled, info.ExitCode)
+	}
+	if info.FinishedAt != "2024-05-01T09:59:58Z" {
+		t.Errorf("FinishedAt = %q", info.FinishedAt)
+	}
+	if info.Health == nil || info.Health.Status != "unhealthy" || info.Health.FailingStreak != 3 {
+		t.Fatalf("Health = %+v, want unhealthy with streak 3", info.Health)
+	}
+	if len(info.Health.Log) != 1 || info.Health.Log[0].Output != "curl: (7) Failed to connect" {
+		t.Errorf("Health.Log = %+v, want one trimmed check", info.Health.Log)
+	}
+}
+
+func TestApplyInspectNeverFinished(t *testing.T) {
+	data := containerJSON{}
+	data.State.Status = "running"
+	data.State.FinishedAt = "0001-01-01T00:00:00Z"
+
+	info := &ContainerInfo{}
+	(&DefaultRetriever{runtime: DockerRuntime}).applyInspect(info, &data)
+
+	if info.FinishedAt != "" || info.Health != nil {
+		t.Errorf("FinishedAt = %q, Health = %+v, want both unset", info.FinishedAt, info.Health)
+	}
+}
@@ -0,0 +1,85 @@
+package docker
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+// rootlessPortHolders hold published ports for rootless Docker: the port driver of
+// rootlesskit (rootlessport) or the slirp4netns network stack.
+var rootlessPortHolders = []string{"rootlesskit", "rootlessport", "slirp4netns"}
+
+// desktopPortHolders hold published ports for Docker Desktop, which runs the engine in a VM.
+var desktopPortHolders = []string{"com.docker.backend", "vpnkit"}
+
+// hasHolderPrefix checks whether the process name starts with one of the holder names.
+func hasHolderPrefix(info *model.ProcessInfo, holders []string) bool {
+	name := strings.ToLower(info.Command)
+	for _, holder := range holders {
+		if strings.HasPrefix(name, holder) {
+			return true
+		}
+	}
+	return false
+}
+
+// rootlessSocket returns the socket of the rootless Docker daemon run by a user:
+// $XDG_RUNTIME_DIR/docker.sock for the current user, /run/user/<uid>/docker.sock otherwise.
+func rootlessSocket(uid int) string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" && uid == os.Getuid() {
+		return filepath.Join(dir, "docker.sock")
+	}
+	return fmt.Sprintf("/run/user/%d/docker.sock", uid)
+}
+
+// isRootlessHost reports whether an engine address is a rootless daemon's socket.
+func isRootlessHost(host string) bool {
+	u, err := url.Parse(host)
+	if err != nil || u.Scheme != "unix" {
+		return false
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" && strings.HasPrefix(u.Path, dir+"/") {
+		return true
+	}
+	return strings.HasPrefix(u.Path, "/run/user/")
+}
+
+// findRootlessContainer maps a rootless Docker or Docker Desktop port holder to the
+// container publishing the port. Rootless holders are resolved against the daemon of
+// the user owning the holder; when a container is found there, the detector switches
+// to that engine so the container is retrieved from it.
+func (d *DefaultDetector) findRootlessContainer(info *model.ProcessInfo, port int) string {
+	// Docker Desktop forwards ports from the VM; the default engine is the VM's engine
+	if hasHolderPrefix(info, desktopPortHolders) {
+		if !d.available {
+			return ""
+		}
+		return d.findContainerByPort(port)
+	}
+
+	host := "unix://" + rootlessSocket(info.UID)
+	if _, current := d.runtime.endpoint(); current == host {
+		if !d.available {
+			return ""
+		}
+		return d.findContainerByPort(port)
+	}
+
+	runtime := d.runtime.WithEngine("", host)
+	rootless := &DefaultDetector{runtime: runtime, client: runtime.apiClient()}
+	rootless.checkAvailability()
+	if !rootless.available {
+		return ""
+	}
+
+	containerID := rootless.findContainerByPort(port)
+	if containerID != "" {
+		d.runtime, d.client, d.containers, d.available = rootless.runtime, rootless.client, rootless.containers, true
+	}
+	return containerID
+}
@@ -0,0 +1,95 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+func TestHasHolderPrefix(t *testing.T) {
+	tests := []struct {
+		command      string
+		wantRootless bool
+		wantDesktop  bool
+	}{
+		{"rootlesskit", true, false},
+		{"rootlessport", true, false},
+		{"slirp4netns", true, false},
+		{"com.docker.backend", false, true},
+		{"vpnkit-bridge", false, true},
+		{"dockerd", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			info := &model.ProcessInfo{Command: tt.command}
+			if got := hasHolderPrefix(info, rootlessPortHolders); got != tt.wantRootless {
+				t.Errorf("rootless holder = %v, want %v", got, tt.wantRootless)
+			}
+			if got := hasHolderPrefix(info, desktopPortHolders); got != tt.wantDesktop {
+				t.Errorf("desktop holder = %v, want %v", got, tt.wantDesktop)
+			}
+		})
+	}
+}
+
+func TestRootlessSocket(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/4242")
+
+	if got := rootlessSocket(os.Getuid()); got != "/run/user/4242/docker.sock" {
+		t.Errorf("rootlessSocket(current user) = %v", got)
+	}
+	if got := rootlessSocket(os.Getuid() + 1); got != fmt.Sprintf("/run/user/%d/docker.sock", os.Getuid()+1) {
+		t.Errorf("rootlessSocket(other user) = %v", got)
+	}
+}
+
+func TestIsRootlessHost(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/tmp/runtime-me")
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"unix:///run/user/1000/docker.sock", true},
+		{"unix:///tmp/runtime-me/docker.sock", true},
+		{"unix:///var/run/docker.sock", false},
+		{"tcp://127.0.0.1:2375", false},
+	}
+
+	for _, tt := range tests {
+		if got := isRootlessHost(tt.host); got != tt.want {
+			t.Errorf("isRootlessHost(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestAPIInfoRootless(t *testing.T) {
+	info := &apiInfo{SecurityOptions: []string{"name=seccomp,profile=builtin", "name=rootless", "name=cgroupns"}}
+	if !info.rootless() {
+		t.Error("rootless() = false, want true with name=rootless")
+	}
+	if (&apiInfo{SecurityOptions: []string{"name=apparmor"}}).rootless() {
+		t.Error("rootless() = true, want false without name=rootless")
+	}
+}
+
+func TestIsDockerRelatedRootless(t *testing.T) {
+	socket := newFakeEngineSocket(t)
+	t.Setenv("XDG_RUNTIME_DIR", filepath.Dir(socket))
+
+	// The default engine is unreachable; the user's rootless daemon publishes the port
+	detector := &DefaultDetector{runtime: DockerRuntime.WithEngine("", "unix:///nonexistent/docker.sock")}
+	holder := &model.ProcessInfo{ID: 4321, Command: "rootlessport", UID: os.Getuid()}
+
+	isContainer, containerID, err := detector.IsDockerRelated(holder, 8080)
+	if err != nil || !isContainer || containerID != "aaa111" {
+		t.Fatalf("IsDockerRelated() = (%v, %v, %v), want (true, aaa111, nil)", isContainer, containerID, err)
+	}
+	if got := detector.Runtime().Host; got != "unix://"+socket {
+		t.Errorf("Runtime().Host = %v, want the rootless socket", got)
+	}
+}
@@ -0,0 +1,158 @@
+package docker
+
+import (
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Runtime describes a Docker-compatible container engine and its CLI.
+// Detector, Retriever and ActionHandler are engine-agnostic and run all
+// commands through a Runtime.
+type Runtime struct {
+	Name          string         // Engine name (docker, podman, containerd)
+	Binary        string         // CLI binary used for all engine commands
+	Namespace     string         // containerd namespace passed to the CLI (containerd only)
+	Context       string         // docker context passed to the CLI (Docker only)
+	Host          string         // Engine address passed to the CLI (Docker only)
+	versionArgs   []string       // Arguments of the command used to probe availability
+	cgroupPattern *regexp.Regexp // Extracts the container ID from a /proc/{pid}/cgroup line
+}
+
+var (
+	// DockerRuntime is the Docker Engine accessed through the docker CLI.
+	DockerRuntime = Runtime{
+		Name:        "docker",
+		Binary:      "docker",
+		versionArgs: []string{"version", "--format", "{{.Server.Version}}"},
+		// /docker/<id> (cgroupfs) or /system.slice/docker-<id>.scope (systemd)
+		cgroupPattern: regexp.MustCompile(`/docker[/-]([a-f0-9]{64})`),
+	}
+
+	// PodmanRuntime is Podman (rootful or rootless) accessed through the podman CLI.
+	PodmanRuntime = Runtime{
+		Name:        "podman",
+		Binary:      "podman",
+		versionArgs: []string{"version", "--format", "{{.Client.Version}}"},
+		// libpod-<id>.scope for container processes, libpod-conmon-<id>.scope for conmon
+		cgroupPattern: regexp.MustCompile(`libpod-(?:conmon-)?([a-f0-9]{64})`),
+	}
+
+	// ContainerdRuntime is plain containerd accessed through nerdctl (with ctr as a fallback).
+	ContainerdRuntime = Runtime{
+		Name:        "containerd",
+		Binary:      "nerdctl",
+		versionArgs: []string{"version"},
+		// /<namespace>/<id> (cgroupfs) or /system.slice/nerdctl-<id>.scope (systemd)
+		cgroupPattern: regexp.MustCompile(`(?:nerdctl-|^[0-9]+:[^:]*:/[A-Za-z0-9._-]+/)([a-f0-9]{64})(?:\.scope)?$`),
+	}
+)
+
+// Runtimes returns the supported container runtimes in detection order.
+func Runtimes() []Runtime {
+	return []Runtime{DockerRuntime, PodmanRuntime, ContainerdRuntime}
+}
+
+// RuntimeByName returns the runtime with the given name, defaulting to Docker.
+func RuntimeByName(name string) Runtime {
+	for _, rt := range Runtimes() {
+		if rt.Name == name {
+			return rt
+		}
+	}
+	return DockerRuntime
+}
+
+// Title returns the runtime name for banners and prompts (e.g. "DOCKER").
+func (r Runtime) Title() string {
+	return strings.ToUpper(r.Name)
+}
+
+// WithNamespace returns a copy of the runtime scoped to a containerd namespace.
+func (r Runtime) WithNamespace(namespace string) Runtime {
+	r.Namespace = namespace
+	return r
+}
+
+// command builds an engine CLI command.
+func (r Runtime) command(args ...string) *exec.Cmd {
+	if r.Namespace != "" {
+		args = append([]string{"--namespace", r.Namespace}, args...)
+	}
+	if r.Context != "" {
+		args = append([]string{"--context", r.Context}, args...)
+	} else if r.Host != "" {
+		args = append([]string{"--host", r.Host}, args...)
+	}
+	return exec.Command(r.Binary, args...)
+}
+
+// available reports whether the engine CLI works and can reach its engine.
+// An available engine is cached per engine (and docker context) for availabilityTTL;
+// an unavailable one is probed again on every run.
+func (r Runtime) available() bool {
+	path, key := availabilityCachePath(), r.availabilityKey()
+	if _, ok := cachedAvailability(path, key, time.Now()); ok {
+		return true
+	}
+
+	entry := r.probe()
+	if entry.Available {
+		storeAvailability(path, key, entry)
+	}
+	return entry.Available
+}
+
+// probe runs the runtime's version command to check that the engine is reachable.
+func (r Runtime) probe() availabilityEntry {
+	entry := availabilityEntry{CheckedAt: time.Now()}
+	if output, err := r.command(r.versionArgs...).Output(); err == nil {
+		entry.Available = true
+		// docker and podman print a bare version; nerdctl prints a full report
+		if version := strings.TrimSpace(string(output)); !strings.Contains(version, "\n") {
+			entry.Version = version
+		}
+		return entry
+	}
+	// containerd can still be inspected with ctr when nerdctl is not installed
+	entry.Available = r.Name == ContainerdRuntime.Name && exec.Command("ctr", "version").Run() == nil
+	return entry
+}
+
+// apiClients holds one Engine API client per engine address, so that detection and
+// retrieval share what a client has already asked the engine.
+var (
+	apiClientsMu sync.Mutex
+	apiClients   = make(map[string]*Client)
+)
+
+// apiClient returns an Engine API client for the runtime, or nil when only the CLI is usable.
+func (r Runtime) apiClient() *Client {
+	if r.Name != DockerRuntime.Name {
+		return nil
+	}
+	_, host := r.endpoint()
+
+	apiClientsMu.Lock()
+	defer apiClientsMu.Unlock()
+	if client, ok := apiClients[host]; ok {
+		return client
+	}
+	client, err := NewClientForHost(host)
+	if err != nil {
+		return nil
+	}
+	apiClients[host] = client
+	return client
+}
+
+// runtimeFor returns the runtime (and containerd namespace) that owns a container.
+func runtimeFor(info *ContainerInfo) Runtime {
+	runtime := RuntimeByName(info.Runtime).WithNamespace(info.Namespace)
+	if info.Engine != nil {
+		runtime = runtime.WithEngine(info.Engine.Context, info.Engine.Host)
+	}
+	return runtime
+}
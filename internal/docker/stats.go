@@ -0,0 +1,151 @@
+package docker
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sizeUnits maps the size suffixes printed by `docker stats` to bytes.
+// Memory uses binary units (MiB), network and block I/O use decimal units (MB).
+var sizeUnits = map[string]float64{
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// parseStats converts the formatted `docker stats` columns of a container into numbers.
+func parseStats(info *ContainerInfo) *Stats {
+	stats := &Stats{
+		CPUPerc:   parsePercent(info.CPUPercent),
+		MemPerc:   parsePercent(info.MemPercent),
+		Timestamp: time.Now(),
+	}
+	stats.MemUsage, stats.MemLimit = parseSizePair(info.MemUsage)
+	stats.NetInput, stats.NetOutput = parseSizePair(info.NetIO)
+	stats.BlockInput, stats.BlockOutput = parseSizePair(info.BlockIO)
+	stats.NumPIDs, _ = strconv.Atoi(strings.TrimSpace(info.PIDs))
+	return stats
+}
+
+// parsePercent parses a value such as "12.34%".
+func parsePercent(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	return v
+}
+
+// parseSizePair parses a "used / total" pair such as "100MiB / 2GiB".
+func parseSizePair(s string) (int64, int64) {
+	first, second, _ := strings.Cut(s, "/")
+	return parseSize(first), parseSize(second)
+}
+
+// parseSize parses a size such as "1.5kB" or "100MiB" into bytes.
+// Unparseable values yield 0.
+func parseSize(s string) int64 {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	value, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0
+	}
+
+	unit := strings.ToLower(strings.TrimSpace(s[i:]))
+	if unit == "" {
+		return int64(value)
+	}
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0
+	}
+	return int64(math.Round(value * multiplier))
+}
+
+// statsFromAPI computes the usage figures `docker stats` shows from an Engine API sample.
+func statsFromAPI(data *apiStats) *Stats {
+	stats := &Stats{NumPIDs: data.PidsStats.Current, Timestamp: data.Read}
+	if stats.Timestamp.IsZero() {
+		stats.Timestamp = time.Now()
+	}
+
+	cpuDelta := float64(data.CPUStats.CPUUsage.TotalUsage) - float64(data.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(data.CPUStats.SystemUsage) - float64(data.PreCPUStats.SystemUsage)
+	cpus := data.CPUStats.OnlineCPUs
+	if cpus == 0 {
+		cpus = len(data.CPUStats.CPUUsage.PercpuUsage)
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		stats.CPUPerc = cpuDelta / systemDelta * float64(cpus) * 100
+	}
+
+	// Like the CLI, page cache that can be reclaimed does not count as used memory
+	usage := data.MemoryStats.Usage
+	for _, key := range []string{"total_inactive_file", "inactive_file"} {
+		if cache, ok := data.MemoryStats.Stats[key]; ok && cache < usage {
+			usage -= cache
+			break
+		}
+	}
+	stats.MemUsage, stats.MemLimit = int64(usage), int64(data.MemoryStats.Limit)
+	if stats.MemLimit > 0 {
+		stats.MemPerc = float64(stats.MemUsage) / float64(stats.MemLimit) * 100
+	}
+
+	for _, network := range data.Networks {
+		stats.NetInput += int64(network.RxBytes)
+		stats.NetOutput += int64(network.TxBytes)
+	}
+	for _, entry := range data.BlkioStats.IOServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			stats.BlockInput += int64(entry.Value)
+		case "write":
+			stats.BlockOutput += int64(entry.Value)
+		}
+	}
+	return stats
+}
+
+// applyStats fills in the formatted columns of a container the way `docker stats` prints them.
+func applyStats(info *ContainerInfo, stats *Stats) {
+	info.CPUPercent = fmt.Sprintf("%.2f%%", stats.CPUPerc)
+	info.MemUsage = binarySize(stats.MemUsage) + " / " + binarySize(stats.MemLimit)
+	info.MemPercent = fmt.Sprintf("%.2f%%", stats.MemPerc)
+	info.NetIO = decimalSize(stats.NetInput) + " / " + decimalSize(stats.NetOutput)
+	info.BlockIO = decimalSize(stats.BlockInput) + " / " + decimalSize(stats.BlockOutput)
+	info.PIDs = strconv.Itoa(stats.NumPIDs)
+	info.Stats = stats
+}
+
+// binarySize formats a size in binary units, e.g. "12.5MiB".
+func binarySize(size int64) string {
+	return formatSize(float64(size), 1024, []string{"B", "KiB", "MiB", "GiB", "TiB"}, 4)
+}
+
+// decimalSize formats a size in decimal units, e.g. "1.2kB".
+func decimalSize(size int64) string {
+	return formatSize(float64(size), 1000, []string{"B", "kB", "MB", "GB", "TB"}, 3)
+}
+
+func formatSize(size, base float64, units []string, precision int) string {
+	i := 0
+	for size >= base && i < len(units)-1 {
+		size /= base
+		i++
+	}
+	return fmt.Sprintf("%.*g%s", precision, size, units[i])
+}
@@ -0,0 +1,57 @@
+package docker
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{"0B", 0},
+		{"512B", 512},
+		{"1.5kB", 1500},
+		{"2MB", 2000000},
+		{"100MiB", 100 << 20},
+		{"1.5GiB", 3 << 29},
+		{" 2.5GiB ", 5 << 29},
+		{"--", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := parseSize(tt.input); got != tt.want {
+				t.Errorf("parseSize(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	info := &ContainerInfo{
+		CPUPercent: "12.50%",
+		MemUsage:   "100MiB / 2GiB",
+		MemPercent: "4.88%",
+		NetIO:      "1.2kB / 3.4MB",
+		BlockIO:    "0B / 8.19kB",
+		PIDs:       "5",
+	}
+
+	stats := parseStats(info)
+
+	if stats.CPUPerc != 12.5 || stats.MemPerc != 4.88 {
+		t.Errorf("percentages = (%v, %v), want (12.5, 4.88)", stats.CPUPerc, stats.MemPerc)
+	}
+	if stats.MemUsage != 100<<20 || stats.MemLimit != 2<<30 {
+		t.Errorf("memory = (%d, %d), want (%d, %d)", stats.MemUsage, stats.MemLimit, int64(100<<20), int64(2<<30))
+	}
+	if stats.NetInput != 1200 || stats.NetOutput != 3400000 {
+		t.Errorf("network = (%d, %d), want (1200, 3400000)", stats.NetInput, stats.NetOutput)
+	}
+	if stats.BlockInput != 0 || stats.BlockOutput != 8190 {
+		t.Errorf("block = (%d, %d), want (0, 8190)", stats.BlockInput, stats.BlockOutput)
+	}
+	if stats.NumPIDs != 5 {
+		t.Errorf("NumPIDs = %d, want 5", stats.NumPIDs)
+	}
+}
@@ -0,0 +1,406 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+// stackLabel is the label `docker stack deploy` puts on the services of a stack.
+const stackLabel = "com.docker.stack.namespace"
+
+// ServiceInfo represents a Docker Swarm service publishing a port through the routing mesh.
+type ServiceInfo struct {
+	ID       string        `json:"id"`               // Service ID
+	Name     string        `json:"name"`             // Service name
+	Image    string        `json:"image"`            // Image of the task containers
+	Mode     string        `json:"mode"`             // replicated or global
+	Replicas int           `json:"replicas"`         // Desired replicas (number of tasks for global services)
+	Running  int           `json:"running"`          // Tasks currently running
+	Stack    string        `json:"stack,omitempty"`  // Stack the service was deployed with, if any
+	Engine   *Engine       `json:"engine,omitempty"` // Docker engine that answered
+	Ports    []ServicePort `json:"ports"`            // Published ports
+	Tasks    []ServiceTask `json:"tasks"`            // Current tasks of the service
+
+	// Why the service is protected from being stopped (empty when it is not)
+	Protected []string `json:"protected,omitempty"`
+
+	// Process information (from original whoseport detection)
+	ProcessID  int    `json:"process_id"`  // The PID holding the port (dockerd)
+	ProcessCmd string `json:"process_cmd"` // The process command
+}
+
+// ServicePort is a port published by a service.
+type ServicePort struct {
+	Protocol      string `json:"protocol"`       // tcp, udp or sctp
+	TargetPort    int    `json:"target_port"`    // Port inside the task containers
+	PublishedPort int    `json:"published_port"` // Port published on every node
+	PublishMode   string `json:"publish_mode"`   // ingress (routing mesh) or host
+}
+
+// ServiceTask is one task (replica) of a service.
+type ServiceTask struct {
+	ID           string `json:"id"`                     // Task ID
+	Name         string `json:"name"`                   // Task name (service.slot)
+	Node         string `json:"node"`                   // Hostname (or ID) of the node running the task
+	DesiredState string `json:"desired_state"`          // State the orchestrator wants
+	State        string `json:"state"`                  // Current state
+	ContainerID  string `json:"container_id,omitempty"` // Task container ID, once created
+	Error        string `json:"error,omitempty"`        // Error of the task, if any
+}
+
+// runtime returns the Docker runtime for the engine that owns the service.
+func (s *ServiceInfo) runtime() Runtime {
+	if s.Engine == nil {
+		return DockerRuntime
+	}
+	return DockerRuntime.WithEngine(s.Engine.Context, s.Engine.Host)
+}
+
+// Global reports whether the service runs one task per node instead of a replica count.
+func (s *ServiceInfo) Global() bool {
+	return s.Mode == "global"
+}
+
+// apiService is the service document shared by `docker service inspect` and the Engine API.
+type apiService struct {
+	ID   string `json:"ID"`
+	Spec struct {
+		Name         string            `json:"Name"`
+		Labels       map[string]string `json:"Labels"`
+		TaskTemplate struct {
+			ContainerSpec struct {
+				Image string `json:"Image"`
+			} `json:"ContainerSpec"`
+		} `json:"TaskTemplate"`
+		Mode struct {
+			Replicated *struct {
+				Replicas int `json:"Replicas"`
+			} `json:"Replicated"`
+			Global *struct{} `json:"Global"`
+		} `json:"Mode"`
+	} `json:"Spec"`
+	Endpoint struct {
+		Ports []struct {
+			Protocol      string `json:"Protocol"`
+			TargetPort    int    `json:"TargetPort"`
+			PublishedPort int    `json:"PublishedPort"`
+			PublishMode   string `json:"PublishMode"`
+		} `json:"Ports"`
+	} `json:"Endpoint"`
+}
+
+// apiTask is the subset of a /tasks entry used to list a service's replicas.
+type apiTask struct {
+	ID           string `json:"ID"`
+	Slot         int    `json:"Slot"`
+	NodeID       string `json:"NodeID"`
+	DesiredState string `json:"DesiredState"`
+	Status       struct {
+		State           string `json:"State"`
+		Err             string `json:"Err"`
+		ContainerStatus struct {
+			ContainerID string `json:"ContainerID"`
+		} `json:"ContainerStatus"`
+	} `json:"Status"`
+}
+
+// apiNode is the subset of a /nodes entry used to name the node of a task.
+type apiNode struct {
+	ID          string `json:"ID"`
+	Description struct {
+		Hostname string `json:"Hostname"`
+	} `json:"Description"`
+}
+
+// ListServices returns the swarm services. It fails when the engine is not a swarm manager.
+func (c *Client) ListServices() ([]apiService, error) {
+	body, err := c.get("/services")
+	if err != nil {
+		return nil, err
+	}
+
+	var services []apiService
+	if err := json.Unmarshal(body, &services); err != nil {
+		return nil, fmt.Errorf("failed to decode service list: %w", err)
+	}
+	return services, nil
+}
+
+// InspectService returns the document of a swarm service.
+func (c *Client) InspectService(id string) (*apiService, error) {
+	body, err := c.get("/services/" + url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var service apiService
+	if err := json.Unmarshal(body, &service); err != nil {
+		return nil, fmt.Errorf("failed to decode service: %w", err)
+	}
+	return &service, nil
+}
+
+// ListTasks returns the tasks of a service that the orchestrator wants running.
+func (c *Client) ListTasks(serviceID string) ([]apiTask, error) {
+	filters, err := json.Marshal(map[string][]string{"service": {serviceID}, "desired-state": {"running"}})
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.get("/tasks?filters=" + url.QueryEscape(string(filters)))
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []apiTask
+	if err := json.Unmarshal(body, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode task list: %w", err)
+	}
+	return tasks, nil
+}
+
+// ListNodes returns the nodes of the swarm.
+func (c *Client) ListNodes() ([]apiNode, error) {
+	body, err := c.get("/nodes")
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []apiNode
+	if err := json.Unmarshal(body, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to decode node list: %w", err)
+	}
+	return nodes, nil
+}
+
+// isSwarmPortHolder checks if the process is dockerd, which holds ports published
+// through the swarm routing mesh.
+func isSwarmPortHolder(info *model.ProcessInfo) bool {
+	if info.Command == "dockerd" {
+		return true
+	}
+	fields := strings.Fields(info.FullCommand)
+	return len(fields) > 0 && filepath.Base(fields[0]) == "dockerd"
+}
+
+// FindService finds the swarm service publishing the port. info is the process holding
+// the port, or nil when no host process holds it.
+func (d *DefaultDetector) FindService(info *model.ProcessInfo, port int) string {
+	if !d.available || d.runtime.Name != DockerRuntime.Name {
+		return ""
+	}
+	if info != nil && !isSwarmPortHolder(info) {
+		return ""
+	}
+	return serviceWithPort(d.listServices(), port)
+}
+
+// listServices returns the swarm services from the Engine API or the CLI.
+// It returns nil when the engine is not part of a swarm.
+func (d *DefaultDetector) listServices() []apiService {
+	if d.client != nil {
+		services, err := d.client.ListServices()
+		if err != nil {
+			return nil
+		}
+		return services
+	}
+
+	output, err := d.runtime.command("service", "ls", "-q").Output()
+	if err != nil {
+		return nil
+	}
+	ids := strings.Fields(string(output))
+	if len(ids) == 0 {
+		return nil
+	}
+
+	output, err = d.runtime.command(append([]string{"service", "inspect"}, ids...)...).Output()
+	if err != nil {
+		return nil
+	}
+	var services []apiService
+	if err := json.Unmarshal(output, &services); err != nil {
+		return nil
+	}
+	return services
+}
+
+// serviceWithPort returns the first service publishing the given port.
+func serviceWithPort(services []apiService, port int) string {
+	for _, s := range services {
+		for _, p := range s.Endpoint.Ports {
+			if p.PublishedPort == port {
+				return s.ID
+			}
+		}
+	}
+	return ""
+}
+
+// GetServiceInfo retrieves a swarm service with its published ports and current tasks.
+func (r *DefaultRetriever) GetServiceInfo(serviceID string, processInfo *model.ProcessInfo) (*ServiceInfo, error) {
+	service, err := r.inspectService(serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect service: %w", err)
+	}
+
+	info := serviceFromAPI(service)
+	info.Engine = r.engine()
+	if processInfo != nil {
+		info.ProcessID = processInfo.ID
+		info.ProcessCmd = processInfo.Command
+	}
+
+	// Tasks are informational; a service without them is still worth showing
+	if r.client != nil {
+		info.Tasks = r.tasksFromAPI(info)
+	} else if output, err := r.runtime.command("service", "ps", "--no-trunc", "--filter", "desired-state=running", "--format", "json", serviceID).Output(); err == nil {
+		info.Tasks = parseServicePs(output)
+	}
+
+	for _, task := range info.Tasks {
+		if strings.EqualFold(task.State, "running") {
+			info.Running++
+		}
+	}
+	if info.Global() {
+		info.Replicas = len(info.Tasks)
+	}
+
+	return info, nil
+}
+
+// inspectService returns the service document from the Engine API or the CLI.
+func (r *DefaultRetriever) inspectService(serviceID string) (*apiService, error) {
+	if r.client != nil {
+		if service, err := r.client.InspectService(serviceID); err == nil {
+			return service, nil
+		}
+	}
+
+	output, err := r.runtime.command("service", "inspect", serviceID).Output()
+	if err != nil {
+		return nil, err
+	}
+	var services []apiService
+	if err := json.Unmarshal(output, &services); err != nil {
+		return nil, fmt.Errorf("failed to parse service inspect output: %w", err)
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no service data returned")
+	}
+	return &services[0], nil
+}
+
+// serviceFromAPI converts a service document into a ServiceInfo without tasks.
+func serviceFromAPI(service *apiService) *ServiceInfo {
+	info := &ServiceInfo{
+		ID:    service.ID,
+		Name:  service.Spec.Name,
+		Image: service.Spec.TaskTemplate.ContainerSpec.Image,
+		Stack: service.Spec.Labels[stackLabel],
+		Mode:  "replicated",
+	}
+
+	// Service images are pinned by digest: name@sha256:...
+	if at := strings.Index(info.Image, "@"); at > 0 {
+		info.Image = info.Image[:at]
+	}
+
+	if service.Spec.Mode.Global != nil {
+		info.Mode = "global"
+	} else if service.Spec.Mode.Replicated != nil {
+		info.Replicas = service.Spec.Mode.Replicated.Replicas
+	}
+
+	for _, p := range service.Endpoint.Ports {
+		info.Ports = append(info.Ports, ServicePort{
+			Protocol:      p.Protocol,
+			TargetPort:    p.TargetPort,
+			PublishedPort: p.PublishedPort,
+			PublishMode:   p.PublishMode,
+		})
+	}
+	return info
+}
+
+// tasksFromAPI lists the service's tasks and names their nodes.
+func (r *DefaultRetriever) tasksFromAPI(info *ServiceInfo) []ServiceTask {
+	tasks, err := r.client.ListTasks(info.ID)
+	if err != nil {
+		return nil
+	}
+
+	// Node names are best effort: worker nodes cannot list the swarm's nodes
+	hostnames := make(map[string]string)
+	if nodes, err := r.client.ListNodes(); err == nil {
+		for _, n := range nodes {
+			hostnames[n.ID] = n.Description.Hostname
+		}
+	}
+
+	var result []ServiceTask
+	for _, t := range tasks {
+		node := hostnames[t.NodeID]
+		if node == "" {
+			node = t.NodeID
+		}
+		name := info.Name
+		if t.Slot > 0 {
+			name = fmt.Sprintf("%s.%d", info.Name, t.Slot)
+		}
+		result = append(result, ServiceTask{
+			ID:           t.ID,
+			Name:         name,
+			Node:         node,
+			DesiredState: t.DesiredState,
+			State:        t.Status.State,
+			ContainerID:  t.Status.ContainerStatus.ContainerID,
+			Error:        t.Status.Err,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// parseServicePs parses `docker service ps --format json` output (one object per line).
+// CurrentState reads like "Running 2 hours ago"; only the state is kept.
+func parseServicePs(output []byte) []ServiceTask {
+	var tasks []ServiceTask
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		var entry struct {
+			ID           string `json:"ID"`
+			Name         string `json:"Name"`
+			Node         string `json:"Node"`
+			DesiredState string `json:"DesiredState"`
+			CurrentState string `json:"CurrentState"`
+			Error        string `json:"Error"`
+		}
+		if line == "" || json.Unmarshal([]byte(line), &entry) != nil {
+			continue
+		}
+
+		state := entry.CurrentState
+		if fields := strings.Fields(state); len(fields) > 0 {
+			state = strings.ToLower(fields[0])
+		}
+		tasks = append(tasks, ServiceTask{
+			ID:           entry.ID,
+			Name:         entry.Name,
+			Node:         entry.Node,
+			DesiredState: strings.ToLower(entry.DesiredState),
+			State:        state,
+			Error:        entry.Error,
+		})
+	}
+
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Name < tasks[j].Name })
+	return tasks
+}
@@ -0,0 +1,136 @@
+package docker
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+const fakeService = `{
+  "ID": "svc111",
+  "Spec": {
+    "Name": "shop_web",
+    "Labels": {"com.docker.stack.namespace": "shop"},
+    "TaskTemplate": {"ContainerSpec": {"Image": "nginx:alpine@sha256:0123abcd"}},
+    "Mode": {"Replicated": {"Replicas": 3}}
+  },
+  "Endpoint": {"Ports": [{"Protocol": "tcp", "TargetPort": 80, "PublishedPort": 8081, "PublishMode": "ingress"}]}
+}`
+
+const fakeTasks = `[
+  {"ID": "task2", "Slot": 2, "NodeID": "node2", "DesiredState": "running",
+   "Status": {"State": "pending", "Err": "no suitable node"}},
+  {"ID": "task1", "Slot": 1, "NodeID": "node1", "DesiredState": "running",
+   "Status": {"State": "running", "ContainerStatus": {"ContainerID": "cccccccccccccccccccc"}}}
+]`
+
+func TestIsSwarmPortHolder(t *testing.T) {
+	tests := []struct {
+		name string
+		info model.ProcessInfo
+		want bool
+	}{
+		{"dockerd", model.ProcessInfo{Command: "dockerd"}, true},
+		{"dockerd by path", model.ProcessInfo{Command: "exe", FullCommand: "/usr/bin/dockerd -H fd://"}, true},
+		{"docker-proxy", model.ProcessInfo{Command: "docker-proxy", FullCommand: "/usr/bin/docker-proxy -proto tcp"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSwarmPortHolder(&tt.info); got != tt.want {
+				t.Errorf("isSwarmPortHolder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindService(t *testing.T) {
+	detector := &DefaultDetector{runtime: DockerRuntime, available: true, client: newFakeEngine(t)}
+	dockerd := &model.ProcessInfo{ID: 900, Command: "dockerd"}
+
+	if got := detector.FindService(dockerd, 8081); got != "svc111" {
+		t.Errorf("FindService(dockerd, 8081) = %v, want svc111", got)
+	}
+	if got := detector.FindService(nil, 8081); got != "svc111" {
+		t.Errorf("FindService(nil, 8081) = %v, want svc111", got)
+	}
+	if got := detector.FindService(dockerd, 9999); got != "" {
+		t.Errorf("FindService(dockerd, 9999) = %v, want no match", got)
+	}
+	if got := detector.FindService(&model.ProcessInfo{Command: "nginx"}, 8081); got != "" {
+		t.Errorf("FindService(nginx, 8081) = %v, want no match for a non-dockerd holder", got)
+	}
+}
+
+func TestGetServiceInfo(t *testing.T) {
+	retriever := &DefaultRetriever{runtime: DockerRuntime, client: newFakeEngine(t)}
+
+	info, err := retriever.GetServiceInfo("svc111", &model.ProcessInfo{ID: 900, Command: "dockerd"})
+	if err != nil {
+		t.Fatalf("GetServiceInfo() error = %v", err)
+	}
+
+	if info.Name != "shop_web" || info.Stack != "shop" || info.Image != "nginx:alpine" {
+		t.Errorf("identity = (%v, %v, %v), want (shop_web, shop, nginx:alpine)", info.Name, info.Stack, info.Image)
+	}
+	if info.Mode != "replicated" || info.Replicas != 3 || info.Running != 1 {
+		t.Errorf("replicas = (%v, %d, %d), want (replicated, 3, 1)", info.Mode, info.Replicas, info.Running)
+	}
+	if info.ProcessID != 900 {
+		t.Errorf("ProcessID = %d, want 900", info.ProcessID)
+	}
+
+	want := []ServiceTask{
+		{ID: "task1", Name: "shop_web.1", Node: "manager-1", DesiredState: "running", State: "running", ContainerID: "cccccccccccccccccccc"},
+		{ID: "task2", Name: "shop_web.2", Node: "node2", DesiredState: "running", State: "pending", Error: "no suitable node"},
+	}
+	if !reflect.DeepEqual(info.Tasks, want) {
+		t.Errorf("Tasks = %+v, want %+v", info.Tasks, want)
+	}
+}
+
+func TestParseServicePs(t *testing.T) {
+	output := `{"CurrentState":"Running 2 hours ago","DesiredState":"Running","Error":"","ID":"t2","Name":"web.2","Node":"worker-1"}
+{"CurrentState":"Rejected 5 seconds ago","DesiredState":"Running","Error":"port in use","ID":"t1","Name":"web.1","Node":"worker-2"}`
+
+	want := []ServiceTask{
+		{ID: "t1", Name: "web.1", Node: "worker-2", DesiredState: "running", State: "rejected", Error: "port in use"},
+		{ID: "t2", Name: "web.2", Node: "worker-1", DesiredState: "running", State: "running"},
+	}
+	if got := parseServicePs([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseServicePs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPromptServiceAction(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  string
+		input string
+		want  Action
+	}{
+		{"scale", "replicated", "1\n", ActionScaleService},
+		{"remove", "replicated", "2\n", ActionRemoveService},
+		{"default cancel", "replicated", "\n", ActionCancel},
+		{"global has no scale", "global", "1\n", ActionRemoveService},
+		{"global cancel", "global", "2\n", ActionCancel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			handler := NewActionHandlerWithIO(strings.NewReader(tt.input), writer)
+
+			info := &ServiceInfo{Name: "shop_web", Mode: tt.mode}
+			if got := handler.PromptServiceAction(info); got != tt.want {
+				t.Errorf("PromptServiceAction(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			if !strings.Contains(writer.String(), "docker service rm shop_web") {
+				t.Errorf("output should offer the service rm command, got: %s", writer.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package kubernetes
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/action"
+)
+
+// Action represents a Kubernetes pod or container action.
+type Action int
+
+const (
+	ActionCancel Action = iota
+	ActionStopContainer
+	ActionDeletePod
+)
+
+// ActionHandler handles Kubernetes pod and container actions.
+type ActionHandler struct {
+	reader      io.Reader
+	writer      io.Writer
+	scanner     *bufio.Scanner                    // Shared by all prompts so buffered input is not lost between them
+	guard       func() error                      // Runs before actions that change the pod; an error refuses them
+	plan        *action.Plan                      // Records commands instead of running them (--dry-run); nil runs them
+	audit       func(command []string, err error) // Told about every command that changes a pod; nil for none
+	colorBold   string
+	colorYellow string
+	colorCyan   string
+	colorRed    string
+	colorGreen  string
+	colorReset  string
+}
+
+// NewActionHandler creates a new Kubernetes action handler.
+func NewActionHandler() *ActionHandler {
+	return NewActionHandlerWithIO(os.Stdin, os.Stdout)
+}
+
+// NewActionHandlerWithIO creates a new action handler with custom reader/writer (for testing).
+func NewActionHandlerWithIO(reader io.Reader, writer io.Writer) *ActionHandler {
+	return &ActionHandler{
+		reader:      reader,
+		writer:      writer,
+		scanner:     bufio.NewScanner(reader),
+		colorBold:   "\033[1m",
+		colorYellow: "\033[33m",
+		colorCyan:   "\033[36m",
+		colorRed:    "\033[31m",
+		colorGreen:  "\033[32m",
+		colorReset:  "\033[0m",
+	}
+}
+
+// SetGuard installs a check that runs before the container is stopped or the pod
+// deleted, e.g. the protection list; an error refuses the action
+func (h *ActionHandler) SetGuard(guard func() error) {
+	h.guard = guard
+}
+
+// SetPlan makes the handler record the commands it would run in plan instead of
+// running them (--dry-run).
+func (h *ActionHandler) SetPlan(plan *action.Plan) {
+	h.plan = plan
+}
+
+// SetAuditor installs a hook that is told about every command run to change the
+// pod, and its error
+func (h *ActionHandler) SetAuditor(audit func(command []string, err error)) {
+	h.audit = audit
+}
+
+// run runs a command that changes the pod and reports it to the auditor
+func (h *ActionHandler) run(cmd *exec.Cmd) ([]byte, error) {
+	output, err := cmd.CombinedOutput()
+	if h.audit != nil {
+		h.audit(cmd.Args, err)
+	}
+	return output, err
+}
+
+// record adds the command to the dry-run plan. It returns false when commands run.
+func (h *ActionHandler) record(cmd *exec.Cmd) bool {
+	if h.plan == nil {
+		return false
+	}
+	h.plan.Add(action.Operation{Kind: action.OpCommand, Command: cmd.Args})
+	return true
+}
+
+// ConfirmTyped asks the user to type word to go ahead with an action on a protected
+// pod. Anything else, including an empty answer, declines.
+func (h *ActionHandler) ConfirmTyped(word string) bool {
+	fmt.Fprintf(h.writer, "%s%s🛡️  Type %s to confirm:%s ", h.colorBold, h.colorRed, word, h.colorReset)
+	if !h.scanner.Scan() {
+		return false
+	}
+	return strings.TrimSpace(h.scanner.Text()) == word
+}
+
+// PromptAction prompts the user to select an action for the pod container.
+func (h *ActionHandler) PromptAction(pod *PodInfo) Action {
+	fmt.Fprintf(h.writer, "%s%s☸️  Pod %s/%s container %s - Select action:%s\n",
+		h.colorBold, h.colorYellow, pod.Namespace, pod.PodName, pod.ContainerName, h.colorReset)
+	fmt.Fprintf(h.writer, "  [1] Stop container (crictl stop) - kubelet restarts it per restartPolicy\n")
+	fmt.Fprintf(h.writer, "  [2] Delete pod (kubectl delete pod) - controllers may recreate it\n")
+	fmt.Fprintf(h.writer, "  [3] Cancel\n")
+	fmt.Fprintf(h.writer, "%sChoice [3]:%s ", h.colorBold, h.colorReset)
+
+	for {
+		if !h.scanner.Scan() {
+			return ActionCancel
+		}
+
+		choice := strings.TrimSpace(h.scanner.Text())
+
+		// Default to Cancel if empty
+		if choice == "" {
+			choice = "3"
+		}
+
+		switch choice {
+		case "1":
+			return ActionStopContainer
+		case "2":
+			return ActionDeletePod
+		case "3":
+			return ActionCancel
+		default:
+			fmt.Fprintf(h.writer, "%sInvalid choice. Please enter 1-3.%s\n", h.colorYellow, h.colorReset)
+			fmt.Fprintf(h.writer, "%sChoice [3]:%s ", h.colorBold, h.colorReset)
+		}
+	}
+}
+
+// ExecuteAction executes the selected Kubernetes action.
+func (h *ActionHandler) ExecuteAction(action Action, pod *PodInfo) error {
+	if action != ActionCancel && h.guard != nil {
+		if err := h.guard(); err != nil {
+			return err
+		}
+	}
+
+	switch action {
+	case ActionStopContainer:
+		return h.stopContainer(pod)
+	case ActionDeletePod:
+		return h.deletePod(pod)
+	case ActionCancel:
+		return nil
+	default:
+		return fmt.Errorf("unknown action: %v", action)
+	}
+}
+
+// stopContainer stops the container through the CRI runtime.
+func (h *ActionHandler) stopContainer(pod *PodInfo) error {
+	cmd := exec.Command("crictl", "stop", pod.ContainerID)
+	if h.record(cmd) {
+		return nil
+	}
+	fmt.Fprintf(h.writer, "%s⏸  Stopping container %s...%s\n", h.colorCyan, pod.ContainerName, h.colorReset)
+
+	if output, err := h.run(cmd); err != nil {
+		return fmt.Errorf("failed to stop container: %w\nOutput: %s", err, string(output))
+	}
+
+	fmt.Fprintf(h.writer, "%s✓ Container %s stopped successfully%s\n", h.colorGreen, pod.ContainerName, h.colorReset)
+	return nil
+}
+
+// deletePod deletes the pod through the API server.
+func (h *ActionHandler) deletePod(pod *PodInfo) error {
+	if pod.Namespace == "" || pod.PodName == "" {
+		return fmt.Errorf("pod name for UID %s could not be resolved", pod.PodUID)
+	}
+
+	cmd := exec.Command("kubectl", "delete", "pod", "-n", pod.Namespace, pod.PodName)
+	if h.record(cmd) {
+		return nil
+	}
+	fmt.Fprintf(h.writer, "%s🗑  Deleting pod %s/%s...%s\n", h.colorCyan, pod.Namespace, pod.PodName, h.colorReset)
+
+	if output, err := h.run(cmd); err != nil {
+		return fmt.Errorf("failed to delete pod: %w\nOutput: %s", err, string(output))
+	}
+
+	fmt.Fprintf(h.writer, "%s✓ Pod %s/%s deleted successfully%s\n", h.colorGreen, pod.Namespace, pod.PodName, h.colorReset)
+	return nil
+}
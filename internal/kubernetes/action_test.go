@@ -0,0 +1,112 @@
+package kubernetes
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/action"
+)
+
+func TestPromptAction(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedAction Action
+	}{
+		{"choose stop container", "1\n", ActionStopContainer},
+		{"choose delete pod", "2\n", ActionDeletePod},
+		{"choose cancel", "3\n", ActionCancel},
+		{"default to cancel on empty input", "\n", ActionCancel},
+		{"invalid then valid choice", "9\n2\n", ActionDeletePod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			handler := NewActionHandlerWithIO(strings.NewReader(tt.input), writer)
+			pod := &PodInfo{Namespace: "shop", PodName: "web-abc", ContainerName: "nginx"}
+
+			action := handler.PromptAction(pod)
+
+			if action != tt.expectedAction {
+				t.Errorf("PromptAction() = %v, want %v", action, tt.expectedAction)
+			}
+			if !strings.Contains(writer.String(), "shop/web-abc") {
+				t.Errorf("Output should contain the pod reference, got: %s", writer.String())
+			}
+		})
+	}
+}
+
+func TestDeletePodRequiresName(t *testing.T) {
+	handler := NewActionHandlerWithIO(strings.NewReader(""), &bytes.Buffer{})
+
+	err := handler.ExecuteAction(ActionDeletePod, &PodInfo{PodUID: "1f2e3d4c-5b6a-7980-a1b2-c3d4e5f60718"})
+	if err == nil {
+		t.Error("ExecuteAction(ActionDeletePod) should fail when the pod name is unknown")
+	}
+}
+
+func TestPlanRecordsCommands(t *testing.T) {
+	pod := &PodInfo{Namespace: "shop", PodName: "web-abc", ContainerID: "0a1b2c3d"}
+	tests := []struct {
+		action Action
+		want   []string
+	}{
+		{ActionStopContainer, []string{"crictl", "stop", "0a1b2c3d"}},
+		{ActionDeletePod, []string{"kubectl", "delete", "pod", "-n", "shop", "web-abc"}},
+	}
+
+	for _, tt := range tests {
+		plan := &action.Plan{}
+		handler := NewActionHandlerWithIO(strings.NewReader(""), &bytes.Buffer{})
+		handler.SetPlan(plan)
+
+		if err := handler.ExecuteAction(tt.action, pod); err != nil {
+			t.Fatalf("ExecuteAction(%v) failed: %v", tt.action, err)
+		}
+		if len(plan.Operations) != 1 || !reflect.DeepEqual(plan.Operations[0].Command, tt.want) {
+			t.Errorf("ExecuteAction(%v) recorded %+v, want %v", tt.action, plan.Operations, tt.want)
+		}
+	}
+}
+
+func TestGuardRefusesActions(t *testing.T) {
+	pod := &PodInfo{Namespace: "shop", PodName: "web-abc", ContainerID: "0a1b2c3d"}
+	refused := errors.New("protected")
+
+	for _, a := range []Action{ActionStopContainer, ActionDeletePod} {
+		plan := &action.Plan{}
+		handler := NewActionHandlerWithIO(strings.NewReader(""), &bytes.Buffer{})
+		handler.SetPlan(plan)
+		handler.SetGuard(func() error { return refused })
+
+		if err := handler.ExecuteAction(a, pod); !errors.Is(err, refused) {
+			t.Errorf("ExecuteAction(%v) = %v, want the guard's error", a, err)
+		}
+		if len(plan.Operations) > 0 {
+			t.Errorf("ExecuteAction(%v) recorded %+v after the guard refused", a, plan.Operations)
+		}
+	}
+}
+
+func TestConfirmTyped(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"shop/web-abc\n", true},
+		{"web-abc\n", false},
+		{"\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		handler := NewActionHandlerWithIO(strings.NewReader(tt.input), &bytes.Buffer{})
+		if got := handler.ConfirmTyped("shop/web-abc"); got != tt.want {
+			t.Errorf("ConfirmTyped() with input %q = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
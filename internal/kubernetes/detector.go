@@ -0,0 +1,125 @@
+package kubernetes
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+// Detector identifies if a process runs inside a Kubernetes pod.
+type Detector interface {
+	// Detect returns the pod the process belongs to, or nil if it is not in a pod
+	Detect(info *model.ProcessInfo) (*PodInfo, error)
+}
+
+// DefaultDetector detects pods from /proc/{pid}/cgroup and resolves names with a Resolver.
+type DefaultDetector struct {
+	resolver Resolver
+}
+
+// NewDetector creates a new Kubernetes detector.
+func NewDetector() Detector {
+	return &DefaultDetector{resolver: NewResolver()}
+}
+
+// Pod cgroup paths come in two flavours depending on the kubelet cgroup driver:
+//
+//	systemd:  /kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1a2b_..._9z.slice/cri-containerd-<id>.scope
+//	cgroupfs: /kubepods/burstable/pod1a2b-...-9z/<id>
+var (
+	podUIDPattern    = regexp.MustCompile(`kubepods.*[-/]pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+	containerPattern = regexp.MustCompile(`/(?:(cri-containerd|crio|docker)-)?([0-9a-f]{64})(?:\.scope)?$`)
+)
+
+// Detect checks the process cgroup for a Kubernetes pod and resolves its names.
+func (d *DefaultDetector) Detect(info *model.ProcessInfo) (*PodInfo, error) {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", info.ID))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		pod := ParseCgroupLine(scanner.Text())
+		if pod == nil {
+			continue
+		}
+
+		pod.ProcessID = info.ID
+		pod.ProcessCmd = info.Command
+		if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/net/fib_trie", info.ID)); err == nil {
+			pod.PodIP = ParsePodIP(string(data))
+		}
+		d.resolver.Resolve(pod)
+		return pod, nil
+	}
+
+	return nil, scanner.Err()
+}
+
+// ParseCgroupLine extracts pod UID, container ID, QoS class and runtime from a cgroup line.
+// Returns nil if the line does not belong to a Kubernetes pod container.
+func ParseCgroupLine(line string) *PodInfo {
+	podMatch := podUIDPattern.FindStringSubmatch(line)
+	if len(podMatch) < 2 {
+		return nil
+	}
+	containerMatch := containerPattern.FindStringSubmatch(line)
+	if len(containerMatch) < 3 {
+		return nil
+	}
+
+	pod := &PodInfo{
+		PodUID:      strings.ReplaceAll(podMatch[1], "_", "-"),
+		ContainerID: containerMatch[2],
+		ShortID:     containerMatch[2][:13],
+		QoSClass:    "Guaranteed",
+		Runtime:     runtimeFromPrefix(containerMatch[1]),
+	}
+
+	switch {
+	case strings.Contains(line, "besteffort"):
+		pod.QoSClass = "BestEffort"
+	case strings.Contains(line, "burstable"):
+		pod.QoSClass = "Burstable"
+	}
+
+	return pod
+}
+
+// ParsePodIP returns the first non-loopback local IPv4 address in /proc/{pid}/net/fib_trie
+// content, which in a pod's network namespace is the pod IP. Returns "" if there is none.
+func ParsePodIP(fibTrie string) string {
+	var last string
+	for _, line := range strings.Split(fibTrie, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 2 && fields[0] == "|--":
+			last = fields[1]
+		case strings.Contains(line, "/32 host LOCAL"):
+			if last != "" && !strings.HasPrefix(last, "127.") {
+				return last
+			}
+		}
+	}
+	return ""
+}
+
+// runtimeFromPrefix maps a systemd scope prefix to a runtime name.
+func runtimeFromPrefix(prefix string) string {
+	switch prefix {
+	case "cri-containerd":
+		return "containerd"
+	case "crio":
+		return "cri-o"
+	case "docker":
+		return "docker"
+	default:
+		return ""
+	}
+}
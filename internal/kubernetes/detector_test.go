@@ -0,0 +1,107 @@
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCgroupLine(t *testing.T) {
+	containerID := strings.Repeat("ab12", 16)
+
+	tests := []struct {
+		name        string
+		line        string
+		wantPodUID  string
+		wantQoS     string
+		wantRuntime string
+	}{
+		{
+			name:        "systemd driver with containerd",
+			line:        "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1f2e3d4c_5b6a_7980_a1b2_c3d4e5f60718.slice/cri-containerd-" + containerID + ".scope",
+			wantPodUID:  "1f2e3d4c-5b6a-7980-a1b2-c3d4e5f60718",
+			wantQoS:     "Burstable",
+			wantRuntime: "containerd",
+		},
+		{
+			name:        "systemd driver with cri-o",
+			line:        "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1f2e3d4c_5b6a_7980_a1b2_c3d4e5f60718.slice/crio-" + containerID + ".scope",
+			wantPodUID:  "1f2e3d4c-5b6a-7980-a1b2-c3d4e5f60718",
+			wantQoS:     "BestEffort",
+			wantRuntime: "cri-o",
+		},
+		{
+			name:       "cgroupfs driver guaranteed pod",
+			line:       "11:memory:/kubepods/pod1f2e3d4c-5b6a-7980-a1b2-c3d4e5f60718/" + containerID,
+			wantPodUID: "1f2e3d4c-5b6a-7980-a1b2-c3d4e5f60718",
+			wantQoS:    "Guaranteed",
+		},
+		{
+			name:        "kind node nested under kubelet slice",
+			line:        "0::/kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-burstable.slice/kubelet-kubepods-burstable-pod1f2e3d4c_5b6a_7980_a1b2_c3d4e5f60718.slice/cri-containerd-" + containerID + ".scope",
+			wantPodUID:  "1f2e3d4c-5b6a-7980-a1b2-c3d4e5f60718",
+			wantQoS:     "Burstable",
+			wantRuntime: "containerd",
+		},
+		{
+			name: "plain docker container",
+			line: "0::/system.slice/docker-" + containerID + ".scope",
+		},
+		{
+			name: "pod slice without container",
+			line: "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1f2e3d4c_5b6a_7980_a1b2_c3d4e5f60718.slice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := ParseCgroupLine(tt.line)
+
+			if tt.wantPodUID == "" {
+				if pod != nil {
+					t.Fatalf("ParseCgroupLine() = %+v, want nil", pod)
+				}
+				return
+			}
+			if pod == nil {
+				t.Fatal("ParseCgroupLine() = nil, want pod")
+			}
+			if pod.PodUID != tt.wantPodUID {
+				t.Errorf("PodUID = %v, want %v", pod.PodUID, tt.wantPodUID)
+			}
+			if pod.ContainerID != containerID {
+				t.Errorf("ContainerID = %v, want %v", pod.ContainerID, containerID)
+			}
+			if pod.QoSClass != tt.wantQoS {
+				t.Errorf("QoSClass = %v, want %v", pod.QoSClass, tt.wantQoS)
+			}
+			if pod.Runtime != tt.wantRuntime {
+				t.Errorf("Runtime = %v, want %v", pod.Runtime, tt.wantRuntime)
+			}
+		})
+	}
+}
+
+func TestParsePodIP(t *testing.T) {
+	fibTrie := `Main:
+  +-- 0.0.0.0/0 3 0 5
+     |-- 0.0.0.0
+        /0 universe UNICAST
+     +-- 10.244.0.0/24 2 0 2
+        +-- 10.244.0.0/28 2 0 2
+           |-- 10.244.0.0
+              /24 link UNICAST
+           |-- 10.244.0.5
+              /32 host LOCAL
+Local:
+  +-- 0.0.0.0/0 3 0 5
+     +-- 127.0.0.0/8 2 0 2
+        |-- 127.0.0.1
+           /32 host LOCAL
+`
+	if got := ParsePodIP(fibTrie); got != "10.244.0.5" {
+		t.Errorf("ParsePodIP() = %q, want 10.244.0.5", got)
+	}
+	if got := ParsePodIP("Local:\n  +-- 127.0.0.0/8 2 0 2\n     |-- 127.0.0.1\n        /32 host LOCAL\n"); got != "" {
+		t.Errorf("ParsePodIP() = %q, want \"\" for loopback only", got)
+	}
+}
@@ -0,0 +1,28 @@
+// Package kubernetes provides Kubernetes pod and container detection for processes.
+package kubernetes
+
+// PodInfo represents the Kubernetes pod and container a process belongs to.
+type PodInfo struct {
+	// Identification extracted from the cgroup path
+	PodUID      string `json:"pod_uid"`          // Pod UID
+	ContainerID string `json:"container_id"`     // Full container ID
+	ShortID     string `json:"short_id"`         // Short container ID (first 13 chars, as shown by crictl)
+	QoSClass    string `json:"qos_class"`        // Guaranteed, Burstable or BestEffort
+	Runtime     string `json:"runtime"`          // Container runtime (containerd, cri-o, docker)
+	PodIP       string `json:"pod_ip,omitempty"` // Pod IP, from the process's network namespace
+
+	// Resolved via crictl inspect or the kubelet pod directory
+	Namespace     string `json:"namespace"`      // Pod namespace
+	PodName       string `json:"pod_name"`       // Pod name
+	ContainerName string `json:"container_name"` // Container name within the pod
+	Image         string `json:"image"`          // Container image
+	State         string `json:"state"`          // Container state reported by the runtime
+	Source        string `json:"source"`         // Where the names were resolved from (crictl, kubelet)
+
+	// Why the pod is protected from being stopped (empty when it is not)
+	Protected []string `json:"protected,omitempty"`
+
+	// Process information (from original whoseport detection)
+	ProcessID  int    `json:"process_id"`  // The PID that led to pod detection
+	ProcessCmd string `json:"process_cmd"` // The process command
+}
@@ -0,0 +1,132 @@
+package kubernetes
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Resolver fills in namespace, pod and container names for a detected pod.
+type Resolver interface {
+	Resolve(pod *PodInfo)
+}
+
+// DefaultResolver asks the CRI runtime via crictl and falls back to the kubelet pod directory.
+type DefaultResolver struct {
+	kubeletRoot string
+}
+
+// NewResolver creates a resolver using the default kubelet root (/var/lib/kubelet).
+func NewResolver() *DefaultResolver {
+	return NewResolverWithRoot("/var/lib/kubelet")
+}
+
+// NewResolverWithRoot creates a resolver with a custom kubelet root (for testing).
+func NewResolverWithRoot(kubeletRoot string) *DefaultResolver {
+	return &DefaultResolver{kubeletRoot: kubeletRoot}
+}
+
+// crictlInspect is the subset of `crictl inspect` output used by whoseport.
+type crictlInspect struct {
+	Status struct {
+		State    string `json:"state"`
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Image struct {
+			Image string `json:"image"`
+		} `json:"image"`
+		Labels map[string]string `json:"labels"`
+	} `json:"status"`
+}
+
+// Resolve populates the pod names, trying crictl first and the kubelet directory second.
+func (r *DefaultResolver) Resolve(pod *PodInfo) {
+	output, err := exec.Command("crictl", "inspect", pod.ContainerID).Output()
+	if err == nil && ApplyCrictlInspect(pod, output) == nil {
+		return
+	}
+
+	r.resolveFromKubelet(pod)
+}
+
+// ApplyCrictlInspect parses `crictl inspect` JSON output into the pod info.
+func ApplyCrictlInspect(pod *PodInfo, output []byte) error {
+	var data crictlInspect
+	if err := json.Unmarshal(output, &data); err != nil {
+		return err
+	}
+
+	labels := data.Status.Labels
+	pod.Namespace = labels["io.kubernetes.pod.namespace"]
+	pod.PodName = labels["io.kubernetes.pod.name"]
+	pod.ContainerName = labels["io.kubernetes.container.name"]
+	if pod.ContainerName == "" {
+		pod.ContainerName = data.Status.Metadata.Name
+	}
+	pod.Image = data.Status.Image.Image
+	pod.State = strings.TrimPrefix(data.Status.State, "CONTAINER_")
+	pod.Source = "crictl"
+
+	return nil
+}
+
+// hostAliasesHeader starts the entries the kubelet appends from the pod's hostAliases
+const hostAliasesHeader = "# Entries added by HostAliases."
+
+// podNameFromHosts finds the pod's own entry in a kubelet-managed hosts file: the line for
+// the pod IP or, when no line has it, the last entry before the hostAliases entries.
+// The pod name is the last name on the line, after the FQDN of pods with a subdomain.
+func podNameFromHosts(r io.Reader, podIP string) string {
+	var fallback string
+	aliases := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == hostAliasesHeader {
+			aliases = true
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if podIP != "" && fields[0] == podIP {
+			return fields[len(fields)-1]
+		}
+		if !aliases {
+			fallback = fields[len(fields)-1]
+		}
+	}
+	return fallback
+}
+
+// resolveFromKubelet reads names from /var/lib/kubelet/pods/<uid>.
+// The namespace comes from the projected service account volume, the pod name from the
+// kubelet-managed hosts file and the container name from the containers directory.
+func (r *DefaultResolver) resolveFromKubelet(pod *PodInfo) {
+	podDir := filepath.Join(r.kubeletRoot, "pods", pod.PodUID)
+	if _, err := os.Stat(podDir); err != nil {
+		return
+	}
+	pod.Source = "kubelet"
+
+	if matches, _ := filepath.Glob(filepath.Join(podDir, "volumes", "kubernetes.io~projected", "*", "namespace")); len(matches) > 0 {
+		if data, err := os.ReadFile(matches[0]); err == nil {
+			pod.Namespace = strings.TrimSpace(string(data))
+		}
+	}
+
+	if file, err := os.Open(filepath.Join(podDir, "etc-hosts")); err == nil {
+		pod.PodName = podNameFromHosts(file, pod.PodIP)
+		file.Close()
+	}
+
+	if entries, err := os.ReadDir(filepath.Join(podDir, "containers")); err == nil && len(entries) == 1 {
+		pod.ContainerName = entries[0].Name()
+	}
+}
@@ -0,0 +1,101 @@
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyCrictlInspect(t *testing.T) {
+	output := `{
+  "status": {
+    "id": "abc",
+    "metadata": {"attempt": 0, "name": "nginx"},
+    "state": "CONTAINER_RUNNING",
+    "image": {"image": "docker.io/library/nginx:1.25"},
+    "labels": {
+      "io.kubernetes.container.name": "nginx",
+      "io.kubernetes.pod.name": "web-7c5ddbdf54-abcde",
+      "io.kubernetes.pod.namespace": "shop"
+    }
+  }
+}`
+
+	pod := &PodInfo{}
+	if err := ApplyCrictlInspect(pod, []byte(output)); err != nil {
+		t.Fatalf("ApplyCrictlInspect() error = %v", err)
+	}
+
+	if pod.Namespace != "shop" {
+		t.Errorf("Namespace = %v, want shop", pod.Namespace)
+	}
+	if pod.PodName != "web-7c5ddbdf54-abcde" {
+		t.Errorf("PodName = %v, want web-7c5ddbdf54-abcde", pod.PodName)
+	}
+	if pod.ContainerName != "nginx" {
+		t.Errorf("ContainerName = %v, want nginx", pod.ContainerName)
+	}
+	if pod.Image != "docker.io/library/nginx:1.25" {
+		t.Errorf("Image = %v, want docker.io/library/nginx:1.25", pod.Image)
+	}
+	if pod.State != "RUNNING" {
+		t.Errorf("State = %v, want RUNNING", pod.State)
+	}
+}
+
+func TestResolveFromKubelet(t *testing.T) {
+	root := t.TempDir()
+	uid := "1f2e3d4c-5b6a-7980-a1b2-c3d4e5f60718"
+	podDir := filepath.Join(root, "pods", uid)
+
+	saDir := filepath.Join(podDir, "volumes", "kubernetes.io~projected", "kube-api-access-x7k2p")
+	if err := os.MkdirAll(saDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(podDir, "containers", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(saDir, "namespace"), []byte("shop"), 0644)
+	os.WriteFile(filepath.Join(podDir, "etc-hosts"), []byte("# Kubernetes-managed hosts file.\n127.0.0.1\tlocalhost\n::1\tlocalhost ip6-localhost\n10.244.0.5\tapi-5d9f7b-qwert\n"), 0644)
+
+	pod := &PodInfo{PodUID: uid}
+	NewResolverWithRoot(root).resolveFromKubelet(pod)
+
+	if pod.Namespace != "shop" {
+		t.Errorf("Namespace = %v, want shop", pod.Namespace)
+	}
+	if pod.PodName != "api-5d9f7b-qwert" {
+		t.Errorf("PodName = %v, want api-5d9f7b-qwert", pod.PodName)
+	}
+	if pod.ContainerName != "api" {
+		t.Errorf("ContainerName = %v, want api", pod.ContainerName)
+	}
+	if pod.Source != "kubelet" {
+		t.Errorf("Source = %v, want kubelet", pod.Source)
+	}
+}
+
+func TestPodNameFromHosts(t *testing.T) {
+	hosts := "# Kubernetes-managed hosts file.\n127.0.0.1\tlocalhost\n::1\tlocalhost ip6-localhost ip6-loopback\n" +
+		"10.244.0.5\tapi-5d9f7b-qwert.api.shop.svc.cluster.local\tapi-5d9f7b-qwert\n\n" +
+		"# Entries added by HostAliases.\n10.0.0.9\tlegacy-db\n"
+
+	tests := []struct {
+		name  string
+		podIP string
+		want  string
+	}{
+		{"pod IP", "10.244.0.5", "api-5d9f7b-qwert"},
+		{"unknown pod IP skips host aliases", "", "api-5d9f7b-qwert"},
+		{"pod IP not listed", "192.168.1.20", "api-5d9f7b-qwert"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := podNameFromHosts(strings.NewReader(hosts), tt.podIP); got != tt.want {
+				t.Errorf("podNameFromHosts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
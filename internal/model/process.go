@@ -49,6 +49,29 @@ type ProcessInfo struct {
 	IOWriteSyscalls int64   `json:"io_write_syscalls"` // Number of write syscalls
 	MemoryLimit     int64   `json:"memory_limit_kb"`   // Memory limit in KB (-1 if unlimited)
 	CPUPercent      float64 `json:"cpu_percent"`       // CPU usage percentage
+
+	// How the process was started, so it can be restarted (Linux only)
+	Argv    []string `json:"argv,omitempty"` // Command line as separate arguments
+	Environ []string `json:"-"`              // Environment (KEY=value); not output, it may hold secrets
+
+	// Process manager or watcher found in the parent chain (nil when unsupervised)
+	Supervisor *SupervisorInfo `json:"supervisor,omitempty"`
+
+	// Why the process is protected from being stopped (empty when it is not)
+	Protected []string `json:"protected,omitempty"`
+
+	// Details that could not be read without root (empty when nothing is hidden)
+	Hidden []string `json:"hidden,omitempty"`
+}
+
+// SupervisorInfo describes a process supervisor or file watcher that manages a process.
+// Killing a supervised process usually has no lasting effect because the supervisor respawns it.
+type SupervisorInfo struct {
+	Kind        string   `json:"kind"`         // Supervisor type (pm2, supervisord, nodemon, air, runit, s6)
+	PID         int      `json:"pid"`          // PID of the supervising process
+	Program     string   `json:"program"`      // Managed program, app or service name (if known)
+	Restarts    bool     `json:"restarts"`     // Whether the supervisor respawns the process when it exits
+	StopCommand []string `json:"stop_command"` // Supervisor-native command that stops the program
 }
 
 // New creates a new ProcessInfo with basic lsof data.
@@ -0,0 +1,241 @@
+// Package netfilter finds NAT rules that forward host ports to containers.
+// Published ports without a userland proxy are DNAT rules in the kernel, so no
+// host process holds them.
+package netfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Rule is a DNAT rule forwarding a host port to another address.
+type Rule struct {
+	Source   string `json:"source"`            // Ruleset the rule came from (iptables, nftables)
+	Table    string `json:"table"`             // Table name (nat)
+	Chain    string `json:"chain"`             // Chain name (e.g. DOCKER)
+	Protocol string `json:"protocol"`          // tcp or udp
+	HostIP   string `json:"host_ip,omitempty"` // Destination address matched on the host, if restricted
+	HostPort int    `json:"host_port"`         // Host port matched by the rule
+	DestIP   string `json:"dest_ip"`           // Address the traffic is forwarded to
+	DestPort int    `json:"dest_port"`         // Port the traffic is forwarded to
+}
+
+// String describes the rule, e.g. "tcp :8080 → 172.17.0.2:80".
+func (r Rule) String() string {
+	return fmt.Sprintf("%s %s:%d → %s", r.Protocol, r.HostIP, r.HostPort, net.JoinHostPort(r.DestIP, strconv.Itoa(r.DestPort)))
+}
+
+// Finder looks up DNAT rules for a host port.
+type Finder interface {
+	// Lookup returns the DNAT rules forwarding the given host port
+	Lookup(port int) ([]Rule, error)
+}
+
+// DefaultFinder reads the live ruleset with iptables-save, falling back to nft.
+type DefaultFinder struct{}
+
+// NewFinder creates a new netfilter rule finder.
+func NewFinder() Finder {
+	return &DefaultFinder{}
+}
+
+// Lookup returns the DNAT rules for the port from iptables or, if that yields nothing, nftables.
+// Reading the ruleset usually requires root.
+func (f *DefaultFinder) Lookup(port int) ([]Rule, error) {
+	var errs []string
+
+	output, err := exec.Command("iptables-save", "-t", "nat").Output()
+	if err == nil {
+		if rules := MatchPort(ParseIptablesSave(string(output)), port); len(rules) > 0 {
+			return rules, nil
+		}
+	} else {
+		errs = append(errs, fmt.Sprintf("iptables-save: %v", err))
+	}
+
+	output, err = exec.Command("nft", "-j", "list", "ruleset").Output()
+	if err == nil {
+		rules, err := ParseNftJSON(output)
+		if err != nil {
+			return nil, err
+		}
+		return MatchPort(rules, port), nil
+	}
+	errs = append(errs, fmt.Sprintf("nft: %v", err))
+
+	if len(errs) == 2 {
+		return nil, fmt.Errorf("failed to read netfilter rules: %s", strings.Join(errs, "; "))
+	}
+	return nil, nil
+}
+
+// MatchPort returns the rules forwarding the given host port.
+func MatchPort(rules []Rule, port int) []Rule {
+	var matched []Rule
+	for _, r := range rules {
+		if r.HostPort == port {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// ParseIptablesSave extracts DNAT rules from iptables-save output, e.g.
+// "-A DOCKER ! -i docker0 -p tcp -m tcp --dport 8080 -j DNAT --to-destination 172.17.0.2:80".
+func ParseIptablesSave(output string) []Rule {
+	var rules []Rule
+	table := ""
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "*") {
+			table = strings.TrimPrefix(line, "*")
+			continue
+		}
+		if !strings.HasPrefix(line, "-A ") || !strings.Contains(line, "-j DNAT") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		rule := Rule{Source: "iptables", Table: table, Chain: fields[1]}
+		negated := false
+		for i := 2; i < len(fields); i++ {
+			value := ""
+			if i+1 < len(fields) {
+				value = fields[i+1]
+			}
+			switch fields[i] {
+			case "!":
+				negated = true
+				continue
+			case "-p":
+				rule.Protocol = value
+			case "-d":
+				if !negated {
+					rule.HostIP = strings.TrimSuffix(strings.TrimSuffix(value, "/32"), "/128")
+				}
+			case "--dport":
+				rule.HostPort, _ = strconv.Atoi(value)
+			case "--to-destination":
+				rule.DestIP, rule.DestPort = splitDestination(value)
+			}
+			negated = false
+		}
+
+		if rule.HostPort == 0 || rule.DestIP == "" {
+			continue
+		}
+		if rule.DestPort == 0 {
+			rule.DestPort = rule.HostPort
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// splitDestination splits a DNAT target ("172.17.0.2:80", "[fd00::2]:80" or "172.17.0.2").
+func splitDestination(value string) (string, int) {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return strings.Trim(value, "[]"), 0
+	}
+	p, _ := strconv.Atoi(port)
+	return host, p
+}
+
+// nftRuleset is the subset of `nft -j list ruleset` output used for DNAT rules.
+type nftRuleset struct {
+	Nftables []struct {
+		Rule *struct {
+			Family string                       `json:"family"`
+			Table  string                       `json:"table"`
+			Chain  string                       `json:"chain"`
+			Expr   []map[string]json.RawMessage `json:"expr"`
+		} `json:"rule"`
+	} `json:"nftables"`
+}
+
+// nftMatch is a match expression such as "tcp dport 8080" or "ip daddr 127.0.0.1".
+type nftMatch struct {
+	Op   string `json:"op"`
+	Left struct {
+		Payload *struct {
+			Protocol string `json:"protocol"`
+			Field    string `json:"field"`
+		} `json:"payload"`
+	} `json:"left"`
+	Right json.RawMessage `json:"right"`
+}
+
+// nftNat is a dnat statement.
+type nftNat struct {
+	Addr json.RawMessage `json:"addr"`
+	Port int             `json:"port"`
+}
+
+// ParseNftJSON extracts DNAT rules from `nft -j list ruleset` output.
+func ParseNftJSON(data []byte) ([]Rule, error) {
+	var ruleset nftRuleset
+	if err := json.Unmarshal(data, &ruleset); err != nil {
+		return nil, fmt.Errorf("failed to parse nft ruleset: %w", err)
+	}
+
+	var rules []Rule
+	for _, entry := range ruleset.Nftables {
+		if entry.Rule == nil {
+			continue
+		}
+		rule := Rule{Source: "nftables", Table: entry.Rule.Table, Chain: entry.Rule.Chain}
+
+		for _, expr := range entry.Rule.Expr {
+			if raw, ok := expr["match"]; ok {
+				applyNftMatch(&rule, raw)
+			}
+			if raw, ok := expr["dnat"]; ok {
+				var nat nftNat
+				if json.Unmarshal(raw, &nat) == nil {
+					// addr is a plain string for static DNAT; maps and sets are skipped
+					_ = json.Unmarshal(nat.Addr, &rule.DestIP)
+					rule.DestPort = nat.Port
+				}
+			}
+		}
+
+		if rule.HostPort == 0 || rule.DestIP == "" {
+			continue
+		}
+		if rule.DestPort == 0 {
+			rule.DestPort = rule.HostPort
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// applyNftMatch records port, protocol and address matches of a rule.
+func applyNftMatch(rule *Rule, raw json.RawMessage) {
+	var m nftMatch
+	if json.Unmarshal(raw, &m) != nil || m.Left.Payload == nil || m.Op == "!=" {
+		return
+	}
+
+	switch m.Left.Payload.Field {
+	case "dport":
+		var port int
+		if json.Unmarshal(m.Right, &port) == nil {
+			rule.Protocol = m.Left.Payload.Protocol
+			rule.HostPort = port
+		}
+	case "daddr":
+		var addr string
+		if json.Unmarshal(m.Right, &addr) == nil {
+			rule.HostIP = addr
+		}
+	}
+}
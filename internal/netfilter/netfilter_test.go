@@ -0,0 +1,88 @@
+package netfilter
+
+import (
+	"reflect"
+	"testing"
+)
+
+const iptablesSave = `# Generated by iptables-save v1.8.7 on Mon May  6 10:00:00 2024
+*nat
+:PREROUTING ACCEPT [0:0]
+:DOCKER - [0:0]
+-A PREROUTING -m addrtype --dst-type LOCAL -j DOCKER
+-A POSTROUTING -s 172.17.0.0/16 ! -o docker0 -j MASQUERADE
+-A DOCKER -i docker0 -j RETURN
+-A DOCKER ! -i docker0 -p tcp -m tcp --dport 8080 -j DNAT --to-destination 172.17.0.2:80
+-A DOCKER -d 127.0.0.1/32 ! -i docker0 -p udp -m udp --dport 5353 -j DNAT --to-destination 172.17.0.3:53
+-A DOCKER ! -d 10.0.0.1/32 -p tcp -m tcp --dport 9000 -j DNAT --to-destination 172.18.0.4
+COMMIT
+`
+
+func TestParseIptablesSave(t *testing.T) {
+	got := ParseIptablesSave(iptablesSave)
+	want := []Rule{
+		{Source: "iptables", Table: "nat", Chain: "DOCKER", Protocol: "tcp", HostPort: 8080, DestIP: "172.17.0.2", DestPort: 80},
+		{Source: "iptables", Table: "nat", Chain: "DOCKER", Protocol: "udp", HostIP: "127.0.0.1", HostPort: 5353, DestIP: "172.17.0.3", DestPort: 53},
+		{Source: "iptables", Table: "nat", Chain: "DOCKER", Protocol: "tcp", HostPort: 9000, DestIP: "172.18.0.4", DestPort: 9000},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseIptablesSave() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+const nftRulesetJSON = `{"nftables": [
+  {"metainfo": {"version": "1.0.2", "json_schema_version": 1}},
+  {"table": {"family": "ip", "name": "nat", "handle": 1}},
+  {"rule": {"family": "ip", "table": "nat", "chain": "DOCKER", "handle": 12, "expr": [
+    {"match": {"op": "!=", "left": {"meta": {"key": "iifname"}}, "right": "docker0"}},
+    {"match": {"op": "==", "left": {"payload": {"protocol": "tcp", "field": "dport"}}, "right": 8080}},
+    {"counter": {"packets": 0, "bytes": 0}},
+    {"dnat": {"addr": "172.17.0.2", "port": 80}}
+  ]}},
+  {"rule": {"family": "ip", "table": "nat", "chain": "DOCKER", "handle": 13, "expr": [
+    {"match": {"op": "==", "left": {"payload": {"protocol": "ip", "field": "daddr"}}, "right": "127.0.0.1"}},
+    {"match": {"op": "==", "left": {"payload": {"protocol": "tcp", "field": "dport"}}, "right": 5432}},
+    {"dnat": {"addr": "172.17.0.5"}}
+  ]}},
+  {"rule": {"family": "ip", "table": "nat", "chain": "POSTROUTING", "handle": 14, "expr": [
+    {"masquerade": null}
+  ]}}
+]}`
+
+func TestParseNftJSON(t *testing.T) {
+	got, err := ParseNftJSON([]byte(nftRulesetJSON))
+	if err != nil {
+		t.Fatalf("ParseNftJSON() error = %v", err)
+	}
+
+	want := []Rule{
+		{Source: "nftables", Table: "nat", Chain: "DOCKER", Protocol: "tcp", HostPort: 8080, DestIP: "172.17.0.2", DestPort: 80},
+		{Source: "nftables", Table: "nat", Chain: "DOCKER", Protocol: "tcp", HostIP: "127.0.0.1", HostPort: 5432, DestIP: "172.17.0.5", DestPort: 5432},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseNftJSON() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if _, err := ParseNftJSON([]byte("not json")); err == nil {
+		t.Error("ParseNftJSON() expected error for invalid input")
+	}
+}
+
+func TestMatchPort(t *testing.T) {
+	rules := ParseIptablesSave(iptablesSave)
+
+	if got := MatchPort(rules, 5353); len(got) != 1 || got[0].DestIP != "172.17.0.3" {
+		t.Errorf("MatchPort(5353) = %+v, want the 172.17.0.3 rule", got)
+	}
+	if got := MatchPort(rules, 1234); len(got) != 0 {
+		t.Errorf("MatchPort(1234) = %+v, want none", got)
+	}
+}
+
+func TestRuleString(t *testing.T) {
+	r := Rule{Protocol: "tcp", HostPort: 8080, DestIP: "172.17.0.2", DestPort: 80}
+	if got := r.String(); got != "tcp :8080 → 172.17.0.2:80" {
+		t.Errorf("String() = %q", got)
+	}
+}
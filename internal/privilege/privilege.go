@@ -0,0 +1,186 @@
+// Package privilege finds what whoseport cannot see or do without root and runs it
+// again under sudo, doas or pkexec.
+package privilege
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+// EnvTool selects the escalation command, e.g. WHOSEPORT_ESCALATE=doas
+const EnvTool = "WHOSEPORT_ESCALATE"
+
+// Tools are the supported escalation commands, in the order they are looked for
+var Tools = []string{"sudo", "doas", "pkexec"}
+
+// SudoFlags are the flags removed from the command line when whoseport runs again
+var SudoFlags = []string{"-sudo", "--sudo", "-sudo=true", "--sudo=true"}
+
+// Elevated reports whether whoseport runs as root
+func Elevated() bool {
+	return os.Geteuid() == 0
+}
+
+// Invoker returns the user who started whoseport as root through sudo, doas or
+// pkexec, or nil when whoseport was not escalated
+func Invoker() *user.User {
+	if !Elevated() {
+		return nil
+	}
+	if name := os.Getenv("SUDO_USER"); name != "" {
+		u, _ := user.Lookup(name)
+		return u
+	}
+	if name := os.Getenv("DOAS_USER"); name != "" {
+		u, _ := user.Lookup(name)
+		return u
+	}
+	if uid := os.Getenv("PKEXEC_UID"); uid != "" {
+		u, _ := user.LookupId(uid)
+		return u
+	}
+	return nil
+}
+
+// Hidden lists the details of a process that stayed empty because it belongs to
+// another user. Nothing is hidden from root or for the user's own processes.
+func Hidden(info *model.ProcessInfo) []string {
+	if Elevated() || ownProcess(info) {
+		return nil
+	}
+	return emptyFields(info)
+}
+
+// emptyFields lists the /proc details that are missing from info
+func emptyFields(info *model.ProcessInfo) []string {
+	var hidden []string
+	if info.ExePath == "" {
+		hidden = append(hidden, "executable path")
+	}
+	if info.WorkingDir == "" {
+		hidden = append(hidden, "working directory")
+	}
+	if info.OpenFDs == 0 {
+		hidden = append(hidden, "open file descriptors")
+	}
+	if info.EnvCount == 0 {
+		hidden = append(hidden, "environment")
+	}
+	if info.IOReadBytes == 0 && info.IOWriteBytes == 0 && info.IOReadSyscalls == 0 {
+		hidden = append(hidden, "I/O counters")
+	}
+	if info.NetworkConns == 0 {
+		hidden = append(hidden, "network connections")
+	}
+	return hidden
+}
+
+// ownProcess reports whether the process belongs to the current user. lsof names
+// the owner, or shows the UID when the name is unknown.
+func ownProcess(info *model.ProcessInfo) bool {
+	if u, err := user.Current(); err == nil && info.User != "" {
+		return info.User == u.Username || info.User == u.Uid
+	}
+	return info.UID == os.Getuid()
+}
+
+// Tool returns the escalation command: the one named by $WHOSEPORT_ESCALATE, or the
+// first of sudo, doas and pkexec that is installed
+func Tool() (string, error) {
+	if name := os.Getenv(EnvTool); name != "" {
+		for _, tool := range Tools {
+			if tool == name {
+				if _, err := exec.LookPath(name); err != nil {
+					return "", fmt.Errorf("%s=%s, but %s is not installed", EnvTool, name, name)
+				}
+				return name, nil
+			}
+		}
+		return "", fmt.Errorf("unsupported %s=%s (expected sudo, doas or pkexec)", EnvTool, name)
+	}
+	for _, tool := range Tools {
+		if _, err := exec.LookPath(tool); err == nil {
+			return tool, nil
+		}
+	}
+	return "", fmt.Errorf("none of sudo, doas or pkexec is installed")
+}
+
+// Command returns the argv that runs the executable self with args under tool.
+// pkexec takes no "--" and needs the absolute path it is given.
+func Command(tool, self string, args []string) []string {
+	argv := []string{tool}
+	if tool != "pkexec" {
+		argv = append(argv, "--")
+	}
+	argv = append(argv, self)
+	return append(argv, args...)
+}
+
+// Args rebuilds the command line for the elevated run: --sudo is dropped and extra
+// flags, which repeat an action chosen interactively, are inserted before the nArg
+// positional arguments, so the same lookup runs in the same output mode.
+func Args(args []string, nArg int, extra ...string) []string {
+	if nArg > len(args) {
+		nArg = len(args)
+	}
+	flags, positional := args[:len(args)-nArg], args[len(args)-nArg:]
+
+	var rebuilt []string
+	for _, arg := range flags {
+		if !isSudoFlag(arg) {
+			rebuilt = append(rebuilt, arg)
+		}
+	}
+	// Flags end at "--"; the extra flags have to come before it
+	if n := len(rebuilt); n > 0 && rebuilt[n-1] == "--" {
+		rebuilt = append(append(rebuilt[:n-1:n-1], extra...), "--")
+	} else {
+		rebuilt = append(rebuilt, extra...)
+	}
+	return append(rebuilt, positional...)
+}
+
+func isSudoFlag(arg string) bool {
+	for _, flag := range SudoFlags {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Reexec replaces whoseport with itself running args under tool. The terminal,
+// environment and exit status carry over. It only returns on error.
+func Reexec(tool string, args []string) error {
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return fmt.Errorf("%s is not installed: %w", tool, err)
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the whoseport executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(self); err == nil {
+		self = resolved
+	}
+	if err := syscall.Exec(path, Command(tool, self, args), os.Environ()); err != nil {
+		return fmt.Errorf("failed to run %s: %w", tool, err)
+	}
+	return nil
+}
+
+// IsTerminal reports whether f is a terminal a prompt can be answered on.
+// /dev/null is a character device too, so the terminal attributes are read.
+func IsTerminal(f *os.File) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(getTermios), uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
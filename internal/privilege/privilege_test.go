@@ -0,0 +1,72 @@
+package privilege
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		nArg  int
+		extra []string
+		want  []string
+	}{
+		{"drops --sudo", []string{"--sudo", "-k", "22"}, 1, nil, []string{"-k", "22"}},
+		{"keeps the output mode", []string{"--json", "-sudo=true", "8080"}, 1, nil, []string{"--json", "8080"}},
+		{"repeats the chosen action", []string{"8080"}, 1, []string{"--signal", "SIGTERM"}, []string{"--signal", "SIGTERM", "8080"}},
+		{"before the flag terminator", []string{"--scope", "pgroup", "--", "8080"}, 1, []string{"--stop"}, []string{"--scope", "pgroup", "--stop", "--", "8080"}},
+		{"no positional", []string{"--sudo"}, 0, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Args(tt.args, tt.nArg, tt.extra...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Args() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	args := []string{"-k", "22"}
+	tests := []struct {
+		tool string
+		want []string
+	}{
+		{"sudo", []string{"sudo", "--", "/usr/local/bin/whoseport", "-k", "22"}},
+		{"doas", []string{"doas", "--", "/usr/local/bin/whoseport", "-k", "22"}},
+		{"pkexec", []string{"pkexec", "/usr/local/bin/whoseport", "-k", "22"}},
+	}
+
+	for _, tt := range tests {
+		if got := Command(tt.tool, "/usr/local/bin/whoseport", args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Command(%s) = %q, want %q", tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestEmptyFields(t *testing.T) {
+	// Another user's process as seen without root: status is readable, the rest is not
+	info := &model.ProcessInfo{Command: "nginx", ID: 812, User: "www-data", UID: 33, State: "S", Threads: 1}
+	want := []string{"executable path", "working directory", "open file descriptors", "environment", "I/O counters", "network connections"}
+	if got := emptyFields(info); !reflect.DeepEqual(got, want) {
+		t.Errorf("emptyFields() = %q, want %q", got, want)
+	}
+
+	info.ExePath, info.WorkingDir, info.OpenFDs, info.EnvCount = "/usr/sbin/nginx", "/", 12, 20
+	info.IOReadBytes, info.NetworkConns = 4096, 1
+	if got := emptyFields(info); len(got) != 0 {
+		t.Errorf("emptyFields() = %q for a fully read process", got)
+	}
+}
+
+func TestToolFromEnvironment(t *testing.T) {
+	t.Setenv(EnvTool, "su")
+	if _, err := Tool(); err == nil {
+		t.Error("Tool() should reject an unsupported escalation command")
+	}
+}
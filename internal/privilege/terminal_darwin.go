@@ -0,0 +1,8 @@
+//go:build darwin
+
+package privilege
+
+import "syscall"
+
+// getTermios is the ioctl that reads terminal attributes
+const getTermios = syscall.TIOCGETA
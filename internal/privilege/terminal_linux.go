@@ -0,0 +1,8 @@
+//go:build linux
+
+package privilege
+
+import "syscall"
+
+// getTermios is the ioctl that reads terminal attributes
+const getTermios = syscall.TCGETS
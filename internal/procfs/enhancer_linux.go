@@ -32,6 +32,7 @@ func (e *ProcessEnhancer) Enhance(info *model.ProcessInfo) error {
 		if info.FullCommand == "" {
 			info.FullCommand = info.Command
 		}
+		info.Argv = SplitNul(cmdline)
 	}
 
 	// Read /proc/[pid]/status
@@ -62,13 +63,10 @@ func (e *ProcessEnhancer) Enhance(info *model.ProcessInfo) error {
 		info.OpenFDs = len(fds)
 	}
 
-	// Count environment variables
+	// Read the environment and count its variables
 	if environ, err := os.ReadFile(fmt.Sprintf("/proc/%d/environ", pid)); err == nil {
-		envVars := bytes.Split(environ, []byte{0})
-		info.EnvCount = len(envVars) - 1
-		if info.EnvCount < 0 {
-			info.EnvCount = 0
-		}
+		info.Environ = SplitNul(environ)
+		info.EnvCount = len(info.Environ)
 	}
 
 	// Count child processes
@@ -458,3 +456,14 @@ func ParseTCPState(hex string) string {
 	}
 	return hex
 }
+
+// SplitNul splits a NUL-separated list such as /proc/[pid]/cmdline, keeping empty
+// arguments, including trailing ones: only the terminating NUL is dropped. An empty
+// list gives an empty, non-nil slice.
+func SplitNul(data []byte) []string {
+	data = bytes.TrimSuffix(data, []byte{0})
+	if len(data) == 0 {
+		return []string{}
+	}
+	return strings.Split(string(data), "\x00")
+}
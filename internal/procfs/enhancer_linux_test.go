@@ -0,0 +1,45 @@
+//go:build linux
+
+package procfs
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+func TestSplitNul(t *testing.T) {
+	tests := []struct {
+		data string
+		want []string
+	}{
+		{"node\x00server.js\x00--port\x008080\x00", []string{"node", "server.js", "--port", "8080"}},
+		{"sh\x00-c\x00echo a b\x00", []string{"sh", "-c", "echo a b"}},
+		{"app\x00\x00--flag\x00", []string{"app", "", "--flag"}},
+		{"cmd\x00\x00", []string{"cmd", ""}}, // cmd ""
+		{"nginx: master process\x00\x00\x00", []string{"nginx: master process", "", ""}},
+		{"no terminator", []string{"no terminator"}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := SplitNul([]byte(tt.data)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitNul(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestEnhanceCapturesLaunch(t *testing.T) {
+	info := &model.ProcessInfo{ID: os.Getpid()}
+	if err := NewProcessEnhancer().Enhance(info); err != nil {
+		t.Fatalf("Enhance() failed: %v", err)
+	}
+	if !reflect.DeepEqual(info.Argv, os.Args) {
+		t.Errorf("Argv = %q, want %q", info.Argv, os.Args)
+	}
+	if len(info.Environ) != info.EnvCount || info.EnvCount == 0 {
+		t.Errorf("Environ has %d variables, EnvCount = %d", len(info.Environ), info.EnvCount)
+	}
+}
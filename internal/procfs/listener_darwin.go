@@ -0,0 +1,28 @@
+//go:build darwin
+
+package procfs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// FindListener returns the PID of the process listening on a TCP port in the network
+// namespace of nsPID. macOS has no network namespaces and Docker Desktop runs containers
+// inside a VM, so this is not supported.
+func FindListener(nsPID, port int) (int, error) {
+	return 0, fmt.Errorf("finding listeners inside network namespaces is not supported on macOS")
+}
+
+// PortListening reports whether a TCP port is in use, whether or not its owner is
+// visible to the current user. macOS has no /proc/net, so a bind is attempted.
+func PortListening(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return errors.Is(err, syscall.EADDRINUSE)
+	}
+	listener.Close()
+	return false
+}
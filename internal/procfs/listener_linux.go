@@ -0,0 +1,99 @@
+//go:build linux
+
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// tcpListenState is the hex TCP state of a listening socket in /proc/net/tcp.
+const tcpListenState = "0A"
+
+// FindListener returns the PID of the process listening on a TCP port in the network
+// namespace of nsPID (for example a container's init process). When several processes
+// share the listening socket (pre-forking servers), the lowest PID is returned.
+func FindListener(nsPID, port int) (int, error) {
+	inodes := make(map[string]bool)
+	for _, protocol := range []string{"tcp", "tcp6"} {
+		data, err := os.ReadFile(fmt.Sprintf("/proc/%d/net/%s", nsPID, protocol))
+		if err != nil {
+			continue
+		}
+		for _, inode := range parseListenInodes(string(data), port) {
+			inodes[inode] = true
+		}
+	}
+	if len(inodes) == 0 {
+		return 0, fmt.Errorf("no listener on port %d in the network namespace of PID %d", port, nsPID)
+	}
+
+	netns, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", nsPID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read network namespace of PID %d: %w", nsPID, err)
+	}
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0, err
+	}
+
+	listener := 0
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || (listener != 0 && pid > listener) {
+			continue
+		}
+		if ns, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", pid)); err != nil || ns != netns {
+			continue
+		}
+		for inode := range getProcessInodes(pid) {
+			if inodes[inode] {
+				listener = pid
+				break
+			}
+		}
+	}
+
+	if listener == 0 {
+		return 0, fmt.Errorf("no process owns the listener on port %d (insufficient permissions?)", port)
+	}
+	return listener, nil
+}
+
+// PortListening reports whether a TCP socket listens on port in whoseport's network
+// namespace, whether or not its owner is visible to the current user
+func PortListening(port int) bool {
+	for _, protocol := range []string{"tcp", "tcp6"} {
+		data, err := os.ReadFile("/proc/net/" + protocol)
+		if err == nil && len(parseListenInodes(string(data), port)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parseListenInodes returns the socket inodes listening on port in /proc/net/tcp{,6} content.
+func parseListenInodes(data string, port int) []string {
+	var inodes []string
+	for i, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 10 || fields[3] != tcpListenState {
+			continue
+		}
+
+		// local_address is ADDR:PORT with the port in hex
+		colon := strings.LastIndex(fields[1], ":")
+		if colon < 0 {
+			continue
+		}
+		localPort, err := strconv.ParseInt(fields[1][colon+1:], 16, 32)
+		if err != nil || int(localPort) != port {
+			continue
+		}
+		inodes = append(inodes, fields[9])
+	}
+	return inodes
+}
@@ -0,0 +1,48 @@
+//go:build linux
+
+package procfs
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestParseListenInodes(t *testing.T) {
+	data := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 41234 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 41235 1 0000000000000000 100 0 0 10 0
+   2: 020011AC:0050 010011AC:D2F0 01 00000000:00000000 00:00000000 00000000     0        0 41299 1 0000000000000000 20 4 30 10 -1
+`
+
+	tests := []struct {
+		port int
+		want []string
+	}{
+		{80, []string{"41234"}}, // established connection on port 80 is ignored
+		{8080, []string{"41235"}},
+		{443, nil},
+	}
+
+	for _, tt := range tests {
+		if got := parseListenInodes(data, tt.port); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseListenInodes(%d) = %v, want %v", tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestPortListening(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if !PortListening(port) {
+		t.Errorf("PortListening(%d) = false while listening", port)
+	}
+	listener.Close()
+	if PortListening(port) {
+		t.Errorf("PortListening(%d) = true after the listener closed", port)
+	}
+}
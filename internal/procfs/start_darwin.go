@@ -0,0 +1,19 @@
+//go:build darwin
+
+package procfs
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StartedAt returns when a process started, from ps (second resolution).
+func StartedAt(pid int) (time.Time, error) {
+	output, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "lstart=").Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation("Mon Jan _2 15:04:05 2006", strings.TrimSpace(string(output)), time.Local)
+}
@@ -0,0 +1,43 @@
+//go:build linux
+
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is the kernel's USER_HZ, the unit of the start time in /proc/<pid>/stat.
+const clockTicks = 100
+
+// StartedAt returns when a process started, from /proc/<pid>/stat.
+func StartedAt(pid int) (time.Time, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return time.Time{}, err
+	}
+	ticks, err := parseStartTicks(string(data))
+	if err != nil {
+		return time.Time{}, err
+	}
+	boot := time.Unix(getBootTime(), 0)
+	return boot.Add(time.Duration(ticks) * time.Second / clockTicks), nil
+}
+
+// parseStartTicks extracts the start time (field 22, in clock ticks since boot) from a
+// stat line. The command name is skipped by its closing parenthesis since it may contain spaces.
+func parseStartTicks(stat string) (int64, error) {
+	i := strings.LastIndexByte(stat, ')')
+	if i < 0 {
+		return 0, fmt.Errorf("malformed stat line")
+	}
+	// Fields after the command name start at field 3 (state)
+	fields := strings.Fields(stat[i+1:])
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("malformed stat line")
+	}
+	return strconv.ParseInt(fields[19], 10, 64)
+}
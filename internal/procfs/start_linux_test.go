@@ -0,0 +1,44 @@
+//go:build linux
+
+package procfs
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseStartTicks(t *testing.T) {
+	tests := []struct {
+		stat    string
+		want    int64
+		wantErr bool
+	}{
+		{"1234 (node) S 1 1234 1234 0 -1 4194560 5042 0 0 0 12 3 0 0 20 0 11 0 987654 1093 2048", 987654, false},
+		{"1234 (my server) S 1 1234 1234 0 -1 4194560 5042 0 0 0 12 3 0 0 20 0 11 0 42 1093 2048", 42, false},
+		{"1234 (a) b) S 1 1234 1234 0 -1 4194560 5042 0 0 0 12 3 0 0 20 0 11 0 7 1093 2048", 7, false},
+		{"1234 (node) S 1 1234", 0, true},
+		{"garbage", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseStartTicks(tt.stat)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseStartTicks(%q) error = %v, wantErr %v", tt.stat, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseStartTicks(%q) = %d, want %d", tt.stat, got, tt.want)
+		}
+	}
+}
+
+func TestStartedAt(t *testing.T) {
+	started, err := StartedAt(os.Getpid())
+	if err != nil {
+		t.Fatalf("StartedAt() failed: %v", err)
+	}
+	if started.After(time.Now()) || time.Since(started) > time.Hour {
+		t.Errorf("StartedAt() = %v, expected a recent time for the test process", started)
+	}
+}
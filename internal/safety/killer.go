@@ -0,0 +1,40 @@
+package safety
+
+import (
+	"syscall"
+
+	"github.com/bluehoodie/whoseport/internal/action"
+)
+
+// Killer refuses to signal a protected target unless forced and confirmed
+// Following Open/Closed Principle: wraps any action.Killer, which stays unchanged
+type Killer struct {
+	Killer  action.Killer
+	Reasons []string          // Why the target is protected; empty lets every signal through
+	Word    string            // What the user has to type to confirm
+	Force   bool              // Set by --force
+	Confirm func(string) bool // Asks the user to type Word
+	allowed bool
+}
+
+// NewKiller guards killer with the protection reasons of its target
+func NewKiller(killer action.Killer, reasons []string, word string) *Killer {
+	return &Killer{Killer: killer, Reasons: reasons, Word: word}
+}
+
+// Kill authorizes the first signal and passes every signal on to the wrapped killer.
+// A graceful stop sends SIGTERM and SIGKILL but is confirmed only once.
+func (k *Killer) Kill(pid int, signal syscall.Signal) error {
+	if !k.allowed {
+		if err := Authorize(k.Reasons, k.Word, k.Force, k.Confirm); err != nil {
+			return err
+		}
+		k.allowed = true
+	}
+	return k.Killer.Kill(pid, signal)
+}
+
+// Unwrap returns the guarded killer
+func (k *Killer) Unwrap() action.Killer {
+	return k.Killer
+}
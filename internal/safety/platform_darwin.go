@@ -0,0 +1,13 @@
+//go:build darwin
+
+package safety
+
+// isKernelThread reports whether the subject is the kernel (kernel_task, PID 0)
+func isKernelThread(s Subject) bool {
+	return s.PID == 0 && s.Command == "kernel_task"
+}
+
+// unitOf returns "": macOS has no systemd units
+func unitOf(pid int) string {
+	return ""
+}
@@ -0,0 +1,49 @@
+//go:build linux
+
+package safety
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// kthreadd is the parent of every kernel thread
+const kthreadd = 2
+
+// isKernelThread reports whether the subject is kthreadd or one of its threads
+func isKernelThread(s Subject) bool {
+	return s.PID == kthreadd || s.PPid == kthreadd
+}
+
+// unitOf returns the systemd unit of a process from its cgroup, or "" if it has none
+func unitOf(pid int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return ""
+	}
+	return parseUnit(string(data))
+}
+
+// parseUnit returns the innermost .service or .scope in the cgroup v2 path of
+// /proc/<pid>/cgroup, preferring services
+func parseUnit(data string) string {
+	for _, line := range strings.Split(data, "\n") {
+		cgroup, ok := strings.CutPrefix(line, "0::")
+		if !ok {
+			continue
+		}
+		parts := strings.Split(strings.TrimSpace(cgroup), "/")
+		scope := ""
+		for i := len(parts) - 1; i >= 0; i-- {
+			switch {
+			case strings.HasSuffix(parts[i], ".service"):
+				return parts[i]
+			case strings.HasSuffix(parts[i], ".scope") && scope == "":
+				scope = parts[i]
+			}
+		}
+		return scope
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+//go:build linux
+
+package safety
+
+import "testing"
+
+func TestParseUnit(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"0::/system.slice/postgresql@16-main.service\n", "postgresql@16-main.service"},
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/app-code.scope\n", "user@1000.service"},
+		{"0::/user.slice/user-1000.slice/session-3.scope\n", "session-3.scope"},
+		{"0::/docker/4f1c2a\n", ""},
+		{"12:pids:/system.slice/nginx.service\n", ""}, // cgroup v1 only
+	}
+
+	for _, tt := range tests {
+		if got := parseUnit(tt.data); got != tt.want {
+			t.Errorf("parseUnit(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestIsKernelThread(t *testing.T) {
+	if !isKernelThread(Subject{PID: 2, Command: "kthreadd"}) || !isKernelThread(Subject{PID: 1234, PPid: 2, Command: "nfsd"}) {
+		t.Error("kthreadd and its children are kernel threads")
+	}
+	if isKernelThread(Subject{PID: 1234, PPid: 1, Command: "nginx"}) {
+		t.Error("nginx is not a kernel thread")
+	}
+}
@@ -0,0 +1,268 @@
+// Package safety keeps whoseport from stopping processes and containers that the
+// system or the user depends on: init, sshd, the container engine, kernel threads,
+// other users' processes and anything listed in the user's configuration.
+package safety
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+	"github.com/bluehoodie/whoseport/internal/privilege"
+)
+
+// ConfigName is the file name of the user's protection list
+const ConfigName = "protected.json"
+
+// ErrProtected is returned when an action targets a protected process without --force
+var ErrProtected = errors.New("target is protected")
+
+// ErrNotConfirmed is returned when the typed confirmation does not match
+var ErrNotConfirmed = errors.New("confirmation did not match")
+
+// daemons are system processes protected by default, matched against the command
+// name or executable, with why stopping them is dangerous
+var daemons = []struct {
+	pattern string
+	reason  string
+}{
+	{"sshd", "serves SSH logins: stopping it can lock you out of this machine"},
+	{"sshd-session", "serves an SSH login: stopping it can lock you out of this machine"},
+	{"dockerd", "is the Docker engine: every container depends on it"},
+	{"containerd", "is the container runtime: every container depends on it"},
+	{"containerd-shim*", "supervises a running container"},
+	{"podman", "is the Podman engine: its containers and API clients depend on it"},
+	{"conmon", "supervises a running Podman or CRI-O container"},
+	{"crio", "is the CRI-O container runtime: every pod on this node depends on it"},
+	{"kubelet", "is the Kubernetes node agent: every pod on this node depends on it"},
+	{"systemd", "is the system and service manager"},
+	{"systemd-*", "is a core systemd service"},
+}
+
+// Subject is what an action would stop: a process, or a container or service
+// identified by name and image
+type Subject struct {
+	PID       int
+	PPid      int
+	Command   string // Short command name
+	Exe       string // Executable path, if known
+	User      string // Owner of the process
+	Unit      string // systemd unit, e.g. postgresql.service
+	Port      int
+	Container string // Container or service name (containers only)
+	Image     string // Container image (containers only)
+}
+
+// ProcessSubject describes the process holding port
+func ProcessSubject(info *model.ProcessInfo, port int) Subject {
+	return Subject{
+		PID:     info.ID,
+		PPid:    info.PPid,
+		Command: info.Command,
+		Exe:     info.ExePath,
+		User:    info.User,
+		Unit:    unitOf(info.ID),
+		Port:    port,
+	}
+}
+
+// Policy is the user's own protection list, read from ConfigName:
+//
+//	{"commands": ["postgres", "nginx*"], "ports": [5432], "users": ["postgres"],
+//	 "units": ["postgresql.service"], "containers": ["prod-*"]}
+//
+// Commands, units and containers are shell patterns; containers also match images.
+type Policy struct {
+	Commands   []string `json:"commands"`
+	Ports      []int    `json:"ports"`
+	Users      []string `json:"users"`
+	Units      []string `json:"units"`
+	Containers []string `json:"containers"`
+	path       string
+}
+
+// DefaultConfigPath returns where the protection list is read from:
+// $XDG_CONFIG_HOME/whoseport/protected.json, or ~/.config/whoseport/protected.json.
+// Under sudo, doas or pkexec the invoking user's file is used.
+func DefaultConfigPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "whoseport", ConfigName)
+	}
+	home, err := os.UserHomeDir()
+	if caller := privilege.Invoker(); caller != nil {
+		home, err = caller.HomeDir, nil
+	}
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "whoseport", ConfigName)
+}
+
+// LoadPolicy reads a protection list. A missing file is an empty policy.
+func LoadPolicy(file string) (*Policy, error) {
+	policy := &Policy{path: file}
+	if file == "" {
+		return policy, nil
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return policy, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protection list: %w", err)
+	}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("invalid protection list %s: %w", file, err)
+	}
+	for _, pattern := range append(append(policy.Commands, policy.Units...), policy.Containers...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in %s: %w", pattern, file, err)
+		}
+	}
+	return policy, nil
+}
+
+// Guard decides whether a subject is protected and why
+type Guard struct {
+	policy   *Policy
+	invoker  string // User running whoseport (the sudo caller under sudo)
+	elevated bool   // whoseport runs as root, so root's processes can be signalled too
+}
+
+// NewGuard creates a Guard with the built-in rules and the user's protection list
+func NewGuard() (*Guard, error) {
+	policy, err := LoadPolicy(DefaultConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	guard := NewGuardWithPolicy(policy, invokingUser())
+	guard.elevated = privilege.Elevated()
+	return guard, nil
+}
+
+// NewGuardWithPolicy creates a Guard with a custom policy and invoking user (for testing)
+func NewGuardWithPolicy(policy *Policy, invoker string) *Guard {
+	if policy == nil {
+		policy = &Policy{}
+	}
+	return &Guard{policy: policy, invoker: invoker}
+}
+
+// Check returns why the subject is protected; an empty result means it is not
+func (g *Guard) Check(s Subject) []string {
+	var reasons []string
+	if s.Container == "" {
+		reasons = append(reasons, g.builtin(s)...)
+	}
+	return append(reasons, g.configured(s)...)
+}
+
+// builtin applies the default deny list to a process
+func (g *Guard) builtin(s Subject) []string {
+	var reasons []string
+	if s.PID == 1 {
+		reasons = append(reasons, fmt.Sprintf("PID 1 (%s) is the init process: stopping it brings the system down", s.Command))
+	}
+	if isKernelThread(s) {
+		reasons = append(reasons, fmt.Sprintf("%s is a kernel thread", s.Command))
+	}
+	for _, d := range daemons {
+		if matchCommand(d.pattern, s) {
+			reasons = append(reasons, fmt.Sprintf("%s %s", s.Command, d.reason))
+			break
+		}
+	}
+	// Without root, root's processes cannot be signalled anyway; under sudo they are
+	// another user's like any other
+	if s.User != "" && (s.User != "root" || g.elevated) && g.invoker != "" && s.User != g.invoker {
+		reasons = append(reasons, fmt.Sprintf("it belongs to user %s, not to you (%s)", s.User, g.invoker))
+	}
+	return reasons
+}
+
+// configured applies the user's protection list
+func (g *Guard) configured(s Subject) []string {
+	p := g.policy
+	source := "your protection list"
+	if p.path != "" {
+		source = p.path
+	}
+
+	var reasons []string
+	for _, port := range p.Ports {
+		if s.Port != 0 && port == s.Port {
+			reasons = append(reasons, fmt.Sprintf("port %d is protected by %s", port, source))
+		}
+	}
+	if s.Container != "" {
+		for _, pattern := range p.Containers {
+			if match(pattern, s.Container) || (s.Image != "" && match(pattern, s.Image)) {
+				reasons = append(reasons, fmt.Sprintf("container %s matches %q in %s", s.Container, pattern, source))
+				break
+			}
+		}
+		return reasons
+	}
+
+	for _, pattern := range p.Commands {
+		if matchCommand(pattern, s) {
+			reasons = append(reasons, fmt.Sprintf("command %s matches %q in %s", s.Command, pattern, source))
+			break
+		}
+	}
+	for _, name := range p.Users {
+		if s.User != "" && name == s.User {
+			reasons = append(reasons, fmt.Sprintf("user %s is protected by %s", name, source))
+		}
+	}
+	for _, pattern := range p.Units {
+		if s.Unit != "" && match(pattern, s.Unit) {
+			reasons = append(reasons, fmt.Sprintf("unit %s matches %q in %s", s.Unit, pattern, source))
+			break
+		}
+	}
+	return reasons
+}
+
+// Authorize lets an action on a protected subject go ahead only with force and
+// after the user typed the subject's name. Unprotected subjects always pass.
+func Authorize(reasons []string, word string, force bool, confirm func(word string) bool) error {
+	if len(reasons) == 0 {
+		return nil
+	}
+	if !force {
+		return fmt.Errorf("%w: %s (use --force to override)", ErrProtected, strings.Join(reasons, "; "))
+	}
+	if confirm == nil || !confirm(word) {
+		return ErrNotConfirmed
+	}
+	return nil
+}
+
+// matchCommand matches a pattern against the command name and the executable's base name
+func matchCommand(pattern string, s Subject) bool {
+	return match(pattern, s.Command) || (s.Exe != "" && match(pattern, filepath.Base(s.Exe)))
+}
+
+func match(pattern, name string) bool {
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// invokingUser returns the name of the user running whoseport, or the user who
+// ran sudo, doas or pkexec
+func invokingUser() string {
+	if caller := privilege.Invoker(); caller != nil {
+		return caller.Username
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
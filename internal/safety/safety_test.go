@@ -0,0 +1,210 @@
+package safety
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestCheckBuiltin(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject Subject
+		want    string // Substring of the reason; "" means not protected
+	}{
+		{"init", Subject{PID: 1, Command: "systemd", User: "root"}, "init process"},
+		{"sshd", Subject{PID: 812, PPid: 1, Command: "sshd", User: "root", Port: 22}, "lock you out"},
+		{"sshd by executable", Subject{PID: 812, PPid: 1, Command: "sshd: /usr/sbin", Exe: "/usr/sbin/sshd", User: "root"}, "lock you out"},
+		{"dockerd", Subject{PID: 900, PPid: 1, Command: "dockerd", User: "root"}, "Docker engine"},
+		{"containerd shim", Subject{PID: 950, PPid: 1, Command: "containerd-shim", User: "root"}, "supervises a running container"},
+		{"conmon", Subject{PID: 960, PPid: 1, Command: "conmon", User: "root"}, "Podman or CRI-O container"},
+		{"kubelet", Subject{PID: 970, PPid: 1, Command: "kubelet", User: "root", Port: 10250}, "Kubernetes node agent"},
+		{"systemd service", Subject{PID: 430, PPid: 1, Command: "systemd-resolve", User: "systemd-resolve"}, "core systemd service"},
+		{"other user", Subject{PID: 4242, PPid: 4000, Command: "node", User: "bob"}, "belongs to user bob"},
+		{"own process", Subject{PID: 4242, PPid: 4000, Command: "node", User: "alice"}, ""},
+		{"root daemon", Subject{PID: 700, PPid: 1, Command: "nginx", User: "root"}, ""},
+		{"container", Subject{Port: 22, Container: "sshd", Image: "linuxserver/openssh-server"}, ""},
+	}
+
+	guard := NewGuardWithPolicy(nil, "alice")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reasons := guard.Check(tt.subject)
+			if tt.want == "" {
+				if len(reasons) > 0 {
+					t.Errorf("Check() = %q, want no reasons", reasons)
+				}
+				return
+			}
+			if !strings.Contains(strings.Join(reasons, "; "), tt.want) {
+				t.Errorf("Check() = %q, want a reason containing %q", reasons, tt.want)
+			}
+		})
+	}
+}
+
+// TestCheckElevated tests that root's processes count as another user's under sudo
+func TestCheckElevated(t *testing.T) {
+	nginx := Subject{PID: 700, PPid: 1, Command: "nginx", User: "root"}
+
+	guard := NewGuardWithPolicy(nil, "alice")
+	guard.elevated = true
+	if reasons := guard.Check(nginx); !strings.Contains(strings.Join(reasons, "; "), "belongs to user root") {
+		t.Errorf("Check() = %q under sudo, want root's process protected", reasons)
+	}
+
+	// Logged in as root: root's processes are the user's own
+	guard = NewGuardWithPolicy(nil, "root")
+	guard.elevated = true
+	if reasons := guard.Check(nginx); len(reasons) > 0 {
+		t.Errorf("Check() = %q for root, want no reasons", reasons)
+	}
+}
+
+func TestCheckPolicy(t *testing.T) {
+	policy := &Policy{
+		Commands:   []string{"postgres*"},
+		Ports:      []int{6379},
+		Users:      []string{"mysql"},
+		Units:      []string{"grafana*.service"},
+		Containers: []string{"prod-*", "redis:*"},
+		path:       "/home/alice/.config/whoseport/protected.json",
+	}
+	guard := NewGuardWithPolicy(policy, "alice")
+
+	tests := []struct {
+		name    string
+		subject Subject
+		want    string
+	}{
+		{"command", Subject{PID: 10, Command: "postgres", User: "alice"}, `command postgres matches "postgres*"`},
+		{"port", Subject{PID: 11, Command: "redis-server", User: "alice", Port: 6379}, "port 6379 is protected by /home/alice/.config/whoseport/protected.json"},
+		{"user", Subject{PID: 12, Command: "mysqld", User: "mysql"}, "user mysql is protected"},
+		{"unit", Subject{PID: 13, Command: "grafana", User: "alice", Unit: "grafana-server.service"}, "unit grafana-server.service"},
+		{"container name", Subject{Port: 443, Container: "prod-web", Image: "nginx:1.27"}, `container prod-web matches "prod-*"`},
+		{"container image", Subject{Port: 6380, Container: "cache", Image: "redis:7"}, `matches "redis:*"`},
+		{"container port", Subject{Port: 6379, Container: "cache", Image: "valkey:8"}, "port 6379"},
+		{"unlisted", Subject{PID: 14, Command: "node", User: "alice", Port: 3000}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reasons := strings.Join(guard.Check(tt.subject), "; ")
+			if tt.want == "" && reasons != "" {
+				t.Errorf("Check() = %q, want no reasons", reasons)
+			}
+			if !strings.Contains(reasons, tt.want) {
+				t.Errorf("Check() = %q, want a reason containing %q", reasons, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+
+	policy, err := LoadPolicy(filepath.Join(dir, "missing.json"))
+	if err != nil || len(policy.Commands)+len(policy.Ports) != 0 {
+		t.Errorf("LoadPolicy() of a missing file = %+v, %v; want an empty policy", policy, err)
+	}
+
+	file := filepath.Join(dir, ConfigName)
+	if err := os.WriteFile(file, []byte(`{"commands": ["postgres"], "ports": [5432, 6379]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err = LoadPolicy(file)
+	if err != nil {
+		t.Fatalf("LoadPolicy() failed: %v", err)
+	}
+	if len(policy.Commands) != 1 || len(policy.Ports) != 2 {
+		t.Errorf("LoadPolicy() = %+v", policy)
+	}
+
+	for _, data := range []string{`{"ports": "5432"}`, `{"units": ["[bad"]}`} {
+		if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadPolicy(file); err == nil {
+			t.Errorf("LoadPolicy(%s) should fail", data)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	reasons := []string{"sshd serves SSH logins"}
+	typed := func(answer string) func(string) bool {
+		return func(word string) bool { return answer == word }
+	}
+
+	tests := []struct {
+		name    string
+		reasons []string
+		force   bool
+		confirm func(string) bool
+		want    error
+	}{
+		{"unprotected", nil, false, nil, nil},
+		{"without force", reasons, false, typed("sshd"), ErrProtected},
+		{"forced and confirmed", reasons, true, typed("sshd"), nil},
+		{"forced but mistyped", reasons, true, typed("ssh"), ErrNotConfirmed},
+		{"forced without a prompt", reasons, true, nil, ErrNotConfirmed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(tt.reasons, "sshd", tt.force, tt.confirm)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Authorize() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// recordingKiller records the signals it is asked to send
+type recordingKiller struct {
+	signals []syscall.Signal
+}
+
+func (r *recordingKiller) Kill(pid int, signal syscall.Signal) error {
+	r.signals = append(r.signals, signal)
+	return nil
+}
+
+func TestKillerConfirmsOnce(t *testing.T) {
+	inner := &recordingKiller{}
+	prompts := 0
+	killer := NewKiller(inner, []string{"PID 1 is the init process"}, "systemd")
+	killer.Force = true
+	killer.Confirm = func(word string) bool {
+		prompts++
+		return word == "systemd"
+	}
+
+	// A graceful stop sends SIGTERM, then SIGKILL
+	for _, signal := range []syscall.Signal{syscall.SIGTERM, syscall.SIGKILL} {
+		if err := killer.Kill(1, signal); err != nil {
+			t.Fatalf("Kill(%v) failed: %v", signal, err)
+		}
+	}
+	if prompts != 1 {
+		t.Errorf("Confirm was called %d times, want 1", prompts)
+	}
+	if len(inner.signals) != 2 {
+		t.Errorf("Wrapped killer received %v, want SIGTERM and SIGKILL", inner.signals)
+	}
+}
+
+func TestKillerRefusesWithoutForce(t *testing.T) {
+	inner := &recordingKiller{}
+	killer := NewKiller(inner, []string{"sshd serves SSH logins"}, "sshd")
+
+	if err := killer.Kill(812, syscall.SIGKILL); !errors.Is(err, ErrProtected) {
+		t.Errorf("Kill() = %v, want ErrProtected", err)
+	}
+	if len(inner.signals) != 0 {
+		t.Errorf("No signal should reach a protected process, got %v", inner.signals)
+	}
+}
@@ -0,0 +1,40 @@
+//go:build darwin
+
+package supervisor
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// psSource reads process data using the ps command.
+// Environment variables are not available, so program names fall back to PIDs.
+type psSource struct{}
+
+func newSource() Source {
+	return &psSource{}
+}
+
+// Lookup reads the parent PID, command name and arguments of a process.
+func (s *psSource) Lookup(pid int) (*Process, error) {
+	output, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "ppid=,comm=").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("process %d not found", pid)
+	}
+
+	p := &Process{PID: pid, Comm: fields[1], Environ: map[string]string{}}
+	p.PPid, _ = strconv.Atoi(fields[0])
+
+	if output, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "command=").Output(); err == nil {
+		p.Argv = strings.Fields(string(output))
+	}
+
+	return p, nil
+}
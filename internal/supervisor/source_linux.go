@@ -0,0 +1,57 @@
+//go:build linux
+
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/procfs"
+)
+
+// procSource reads process data from the /proc filesystem.
+type procSource struct{}
+
+func newSource() Source {
+	return &procSource{}
+}
+
+// Lookup reads comm, cmdline, cwd, environ and the parent PID of a process.
+func (s *procSource) Lookup(pid int) (*Process, error) {
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Process{PID: pid, Environ: map[string]string{}}
+	for _, line := range strings.Split(string(status), "\n") {
+		if strings.HasPrefix(line, "PPid:") {
+			p.PPid, _ = strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "PPid:")))
+			break
+		}
+	}
+
+	if comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid)); err == nil {
+		p.Comm = strings.TrimSpace(string(comm))
+	}
+
+	if cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid)); err == nil {
+		p.Argv = procfs.SplitNul(cmdline)
+	}
+
+	if cwd, err := os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid)); err == nil {
+		p.Cwd = cwd
+	}
+
+	if environ, err := os.ReadFile(fmt.Sprintf("/proc/%d/environ", pid)); err == nil {
+		for _, kv := range procfs.SplitNul(environ) {
+			if key, value, ok := strings.Cut(kv, "="); ok {
+				p.Environ[key] = value
+			}
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,241 @@
+// Package supervisor detects process managers and file watchers that respawn processes.
+package supervisor
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+// maxDepth limits how far up the parent chain the detector walks.
+const maxDepth = 16
+
+// Process is the subset of process data needed to recognize a supervisor.
+type Process struct {
+	PID     int
+	PPid    int
+	Comm    string            // Short command name (/proc/[pid]/comm)
+	Argv    []string          // Command line arguments
+	Cwd     string            // Working directory
+	Environ map[string]string // Environment variables (may be empty if unreadable)
+}
+
+// Source looks up processes by PID.
+type Source interface {
+	Lookup(pid int) (*Process, error)
+}
+
+// Detector finds the supervisor responsible for a process.
+type Detector interface {
+	// Detect returns the nearest supervisor in the parent chain, or nil if there is none
+	Detect(info *model.ProcessInfo) *model.SupervisorInfo
+}
+
+// DefaultDetector walks the parent chain using a process Source.
+type DefaultDetector struct {
+	source Source
+}
+
+// NewDetector creates a supervisor detector backed by the platform process source.
+func NewDetector() Detector {
+	return NewDetectorWithSource(newSource())
+}
+
+// NewDetectorWithSource creates a detector with a custom process source (for testing).
+func NewDetectorWithSource(source Source) *DefaultDetector {
+	return &DefaultDetector{source: source}
+}
+
+// Detect walks up from the process to its ancestors and returns the first recognized supervisor.
+func (d *DefaultDetector) Detect(info *model.ProcessInfo) *model.SupervisorInfo {
+	target, err := d.source.Lookup(info.ID)
+	if err != nil {
+		return nil
+	}
+
+	pid := target.PPid
+	for depth := 0; depth < maxDepth && pid > 1; depth++ {
+		ancestor, err := d.source.Lookup(pid)
+		if err != nil {
+			return nil
+		}
+		if s := match(target, ancestor); s != nil {
+			return s
+		}
+		pid = ancestor.PPid
+	}
+
+	return nil
+}
+
+// match checks whether ancestor is a known supervisor of target.
+func match(target, ancestor *Process) *model.SupervisorInfo {
+	name := executableName(ancestor)
+	cmdline := strings.Join(ancestor.Argv, " ")
+
+	switch {
+	case strings.HasPrefix(ancestor.Comm, "PM2") || strings.Contains(cmdline, "PM2 v") || strings.Contains(cmdline, "God Daemon"):
+		return matchPM2(target, ancestor)
+	case name == "supervisord" || scriptName(ancestor) == "supervisord":
+		return matchSupervisord(target, ancestor)
+	case name == "nodemon" || scriptName(ancestor) == "nodemon":
+		return &model.SupervisorInfo{
+			Kind:        "nodemon",
+			PID:         ancestor.PID,
+			Program:     strings.Join(watcherArgs(ancestor, "nodemon"), " "),
+			Restarts:    false,
+			StopCommand: []string{"kill", "-TERM", strconv.Itoa(ancestor.PID)},
+		}
+	case name == "air":
+		return &model.SupervisorInfo{
+			Kind:        "air",
+			PID:         ancestor.PID,
+			Program:     filepath.Base(ancestor.Cwd),
+			Restarts:    false,
+			StopCommand: []string{"kill", "-TERM", strconv.Itoa(ancestor.PID)},
+		}
+	case name == "runsv" && len(ancestor.Argv) > 1:
+		service := ancestor.Argv[1]
+		return &model.SupervisorInfo{
+			Kind:        "runit",
+			PID:         ancestor.PID,
+			Program:     filepath.Base(service),
+			Restarts:    true,
+			StopCommand: []string{"sv", "stop", absPath(ancestor.Cwd, service)},
+		}
+	case name == "s6-supervise" && len(ancestor.Argv) > 1:
+		service := ancestor.Argv[1]
+		return &model.SupervisorInfo{
+			Kind:        "s6",
+			PID:         ancestor.PID,
+			Program:     filepath.Base(service),
+			Restarts:    true,
+			StopCommand: []string{"s6-svc", "-d", absPath(ancestor.Cwd, service)},
+		}
+	}
+
+	return nil
+}
+
+// matchPM2 builds supervisor info for a pm2 God Daemon ancestor.
+// pm2 exports the app name and id into the environment of every managed process.
+func matchPM2(target, ancestor *Process) *model.SupervisorInfo {
+	s := &model.SupervisorInfo{
+		Kind:     "pm2",
+		PID:      ancestor.PID,
+		Restarts: true,
+	}
+
+	// pm2 ids are not OS PIDs: without the app name or id nothing safe can be suggested
+	ref := ""
+	if name := target.Environ["name"]; name != "" {
+		s.Program = name
+		ref = name
+	}
+	if ref == "" {
+		ref = target.Environ["pm_id"]
+	}
+	if ref != "" {
+		s.StopCommand = []string{"pm2", "stop", ref}
+	}
+
+	return s
+}
+
+// matchSupervisord builds supervisor info for a supervisord ancestor.
+// supervisord exports the program and group names into the environment of its children.
+func matchSupervisord(target, ancestor *Process) *model.SupervisorInfo {
+	s := &model.SupervisorInfo{
+		Kind:     "supervisord",
+		PID:      ancestor.PID,
+		Program:  target.Environ["SUPERVISOR_PROCESS_NAME"],
+		Restarts: true,
+	}
+
+	if s.Program == "" {
+		return s
+	}
+
+	ref := s.Program
+	if group := target.Environ["SUPERVISOR_GROUP_NAME"]; group != "" && group != s.Program {
+		ref = group + ":" + s.Program
+	}
+	s.StopCommand = []string{"supervisorctl", "stop", ref}
+
+	return s
+}
+
+// executableName returns the base name of the process executable.
+func executableName(p *Process) string {
+	if len(p.Argv) > 0 && p.Argv[0] != "" {
+		return filepath.Base(p.Argv[0])
+	}
+	return p.Comm
+}
+
+// scriptName returns the base name of the script run by an interpreter (node, python).
+func scriptName(p *Process) string {
+	for _, arg := range p.Argv[min(1, len(p.Argv)):] {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name := filepath.Base(arg)
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return ""
+}
+
+// watcherArgs returns the arguments passed to a watcher after its own name.
+func watcherArgs(p *Process, watcher string) []string {
+	for i, arg := range p.Argv {
+		if strings.Contains(filepath.Base(arg), watcher) {
+			return p.Argv[i+1:]
+		}
+	}
+	return nil
+}
+
+// absPath resolves path relative to dir.
+func absPath(dir, path string) string {
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// Describe returns a one-line warning explaining what the supervisor will do.
+func Describe(s *model.SupervisorInfo) string {
+	subject := fmt.Sprintf("%s (PID %d)", s.Kind, s.PID)
+	if s.Program != "" {
+		switch s.Kind {
+		case "pm2":
+			subject = fmt.Sprintf("pm2 app '%s'", s.Program)
+		case "supervisord":
+			subject = fmt.Sprintf("supervisord program '%s'", s.Program)
+		case "runit", "s6":
+			subject = fmt.Sprintf("%s service '%s'", s.Kind, s.Program)
+		}
+	}
+
+	if s.Restarts {
+		return fmt.Sprintf("This process is managed by %s and will be restarted", subject)
+	}
+	return fmt.Sprintf("This process is run by %s and will be restarted on the next file change", subject)
+}
+
+// Stop runs the supervisor-native stop command.
+func Stop(s *model.SupervisorInfo) error {
+	if len(s.StopCommand) == 0 {
+		return fmt.Errorf("no stop command known for %s", s.Kind)
+	}
+
+	cmd := exec.Command(s.StopCommand[0], s.StopCommand[1:]...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stop via %s: %w\nOutput: %s", s.Kind, err, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package supervisor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bluehoodie/whoseport/internal/model"
+)
+
+// fakeSource serves processes from a map for testing
+type fakeSource map[int]*Process
+
+func (f fakeSource) Lookup(pid int) (*Process, error) {
+	if p, ok := f[pid]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("process %d not found", pid)
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      fakeSource
+		wantKind    string
+		wantProgram string
+		wantStop    []string
+	}{
+		{
+			name: "pm2 app",
+			source: fakeSource{
+				100: {PID: 100, PPid: 50, Comm: "node", Argv: []string{"node", "/srv/api/index.js"}, Environ: map[string]string{"name": "api", "pm_id": "0"}},
+				50:  {PID: 50, PPid: 1, Comm: "PM2 v5.3.0: God", Argv: []string{"PM2 v5.3.0: God Daemon (/root/.pm2)"}},
+			},
+			wantKind:    "pm2",
+			wantProgram: "api",
+			wantStop:    []string{"pm2", "stop", "api"},
+		},
+		{
+			name: "pm2 app with unreadable environment",
+			source: fakeSource{
+				100: {PID: 100, PPid: 50, Comm: "node", Argv: []string{"node", "/srv/api/index.js"}},
+				50:  {PID: 50, PPid: 1, Comm: "PM2 v5.3.0: God", Argv: []string{"PM2 v5.3.0: God Daemon (/root/.pm2)"}},
+			},
+			wantKind: "pm2",
+		},
+		{
+			name: "supervisord program in group",
+			source: fakeSource{
+				100: {PID: 100, PPid: 50, Comm: "gunicorn", Environ: map[string]string{"SUPERVISOR_PROCESS_NAME": "web", "SUPERVISOR_GROUP_NAME": "site"}},
+				50:  {PID: 50, PPid: 1, Comm: "supervisord", Argv: []string{"/usr/bin/python3", "/usr/bin/supervisord", "-n"}},
+			},
+			wantKind:    "supervisord",
+			wantProgram: "web",
+			wantStop:    []string{"supervisorctl", "stop", "site:web"},
+		},
+		{
+			name: "nodemon watcher two levels up",
+			source: fakeSource{
+				100: {PID: 100, PPid: 90, Comm: "node", Argv: []string{"node", "server.js"}},
+				90:  {PID: 90, PPid: 80, Comm: "sh", Argv: []string{"sh", "-c", "node server.js"}},
+				80:  {PID: 80, PPid: 1, Comm: "node", Argv: []string{"node", "/usr/local/bin/nodemon", "server.js"}},
+			},
+			wantKind:    "nodemon",
+			wantProgram: "server.js",
+			wantStop:    []string{"kill", "-TERM", "80"},
+		},
+		{
+			name: "runit service",
+			source: fakeSource{
+				100: {PID: 100, PPid: 70, Comm: "api"},
+				70:  {PID: 70, PPid: 1, Comm: "runsv", Argv: []string{"runsv", "api"}, Cwd: "/etc/service"},
+			},
+			wantKind:    "runit",
+			wantProgram: "api",
+			wantStop:    []string{"sv", "stop", "/etc/service/api"},
+		},
+		{
+			name: "s6 service",
+			source: fakeSource{
+				100: {PID: 100, PPid: 70, Comm: "api"},
+				70:  {PID: 70, PPid: 1, Comm: "s6-supervise", Argv: []string{"s6-supervise", "api"}, Cwd: "/run/service"},
+			},
+			wantKind:    "s6",
+			wantProgram: "api",
+			wantStop:    []string{"s6-svc", "-d", "/run/service/api"},
+		},
+		{
+			name: "unsupervised process",
+			source: fakeSource{
+				100: {PID: 100, PPid: 90, Comm: "node", Argv: []string{"node", "server.js"}},
+				90:  {PID: 90, PPid: 1, Comm: "bash", Argv: []string{"-bash"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewDetectorWithSource(tt.source)
+			got := detector.Detect(&model.ProcessInfo{ID: 100})
+
+			if tt.wantKind == "" {
+				if got != nil {
+					t.Fatalf("Detect() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("Detect() = nil, want %s", tt.wantKind)
+			}
+			if got.Kind != tt.wantKind {
+				t.Errorf("Kind = %v, want %v", got.Kind, tt.wantKind)
+			}
+			if got.Program != tt.wantProgram {
+				t.Errorf("Program = %v, want %v", got.Program, tt.wantProgram)
+			}
+			if !reflect.DeepEqual(got.StopCommand, tt.wantStop) {
+				t.Errorf("StopCommand = %v, want %v", got.StopCommand, tt.wantStop)
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	got := Describe(&model.SupervisorInfo{Kind: "pm2", PID: 50, Program: "api", Restarts: true})
+	want := "This process is managed by pm2 app 'api' and will be restarted"
+	if got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+
+	got = Describe(&model.SupervisorInfo{Kind: "nodemon", PID: 80, Program: "server.js"})
+	if !strings.Contains(got, "nodemon (PID 80)") || !strings.Contains(got, "file change") {
+		t.Errorf("Describe() = %q, want watcher description", got)
+	}
+}